@@ -0,0 +1,110 @@
+// Command apikey creates a managed API key straight in the database. Every HTTP route needs an API key,
+// including /api/v1/api-keys, so this is how the first key of a deployment is made without the deprecated API_KEY.
+//
+//	go run ./cmd/apikey -owner admin@example.com -name bootstrap [-scopes auth,me] [-expires 720h]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"gin/user-management-api/internal/db"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/pkg/loggers"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	owner := flag.String("owner", "", "email of the user that owns the key")
+	name := flag.String("name", "", "name of the key")
+	scopes := flag.String("scopes", "", "comma separated route groups the key may call, empty allows all")
+	expires := flag.Duration("expires", 0, "lifetime of the key, 0 never expires")
+	flag.Parse()
+
+	if *owner == "" || *name == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	rootDir := utils.MustGetWorkingDir()
+
+	loggers.InitLogger(loggers.LoggerConfig{
+		Level:      "warn",
+		Filename:   filepath.Join(rootDir, "internal/logs/app.log"),
+		MaxSize:    1,
+		MaxBackups: 5,
+		MaxAge:     5,
+		Compress:   true,
+		IsDev:      utils.GetEnv("APP_ENV", "development"),
+	})
+
+	if err := godotenv.Load(filepath.Join(rootDir, ".env")); err != nil {
+		loggers.Log.Warn().Msg("No .env file found")
+	}
+
+	if err := run(*owner, *name, *scopes, *expires); err != nil {
+		fmt.Fprintln(os.Stderr, "apikey:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ownerEmail, name, scopeList string, expires time.Duration) error {
+	scopes := []string{}
+	for _, scope := range strings.Split(scopeList, ",") {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if !slices.Contains(auth.APIKeyScopes, scope) {
+			return fmt.Errorf("unknown scope %q, expected one of %s", scope, strings.Join(auth.APIKeyScopes, ", "))
+		}
+		scopes = append(scopes, scope)
+	}
+
+	if err := db.InitDB(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	owner, err := repository.NewSqlUserRepository(db.DB).GetByEmail(ctx, utils.NormalizeString(ownerEmail))
+	if err != nil {
+		return fmt.Errorf("owner %s not found: %w", ownerEmail, err)
+	}
+
+	plainKey, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return err
+	}
+
+	params := sqlc.CreateApiKeyParams{
+		ApiKeyOwnerID: owner.UserID,
+		ApiKeyName:    name,
+		ApiKeyPrefix:  prefix,
+		ApiKeyHash:    hash,
+		ApiKeyScopes:  scopes,
+	}
+	if expires > 0 {
+		params.ApiKeyExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(expires), Valid: true}
+	}
+
+	key, err := repository.NewSqlApiKeyRepository(db.DB).Create(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	// The plain key is only shown once, only its hash is stored
+	fmt.Printf("API key %s (%s) created for %s\n%s\n", key.ApiKeyUuid, key.ApiKeyName, owner.UserEmail, plainKey)
+	return nil
+}
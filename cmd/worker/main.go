@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"gin/user-management-api/internal/config"
+	"gin/user-management-api/internal/db"
+	"gin/user-management-api/internal/repository"
+	v1service "gin/user-management-api/internal/service/v1"
 	"gin/user-management-api/internal/utils"
 	"gin/user-management-api/pkg/loggers"
 	"gin/user-management-api/pkg/mail"
@@ -19,12 +22,18 @@ import (
 )
 
 type Worker struct {
-	rabbitMQ    rabbitmq.RabbitMQSerivce
-	mailService mail.EmailProviderService
-	cfg         *config.Config
-	logger      *zerolog.Logger
+	rabbitMQ           rabbitmq.RabbitMQSerivce
+	mailService        mail.EmailProviderService
+	securityEventRepo  repository.SecurityEventRepository
+	dataExportRepo     repository.DataExportRepository
+	accountClosureRepo repository.AccountClosureRepository
+	dataExporter       *v1service.DataExporter
+	cfg                *config.Config
+	logger             *zerolog.Logger
 }
 
+const housekeepingInterval = time.Hour
+
 func newWorker(cfg *config.Config) *Worker {
 	log := utils.NewLoggerWithPath("worker.log", "info")
 
@@ -51,12 +60,30 @@ func newWorker(cfg *config.Config) *Worker {
 		return nil
 	}
 
-	return &Worker{
+	worker := &Worker{
 		rabbitMQ:    rabbitMG,
 		mailService: mailService,
 		cfg:         cfg,
 		logger:      log,
 	}
+
+	// The database is only needed for data exports and housekeeping, emails are still sent without it
+	if err := db.InitDB(); err != nil {
+		log.Error().Err(err).Msg("Database init failed, data exports and housekeeping are disabled")
+		return worker
+	}
+
+	worker.securityEventRepo = repository.NewSqlSecurityEventRepository(db.DB)
+	worker.dataExportRepo = repository.NewSqlDataExportRepository(db.DB)
+	worker.accountClosureRepo = repository.NewSqlAccountClosureRepository(db.DB)
+	worker.dataExporter = v1service.NewDataExporter(
+		worker.dataExportRepo,
+		repository.NewSqlUserRepository(db.DB),
+		repository.NewSqlRoleRepository(db.DB),
+		repository.NewSqlMfaRepository(db.DB),
+		worker.securityEventRepo,
+	)
+	return worker
 }
 
 func (wk *Worker) Start(ctx context.Context) error {
@@ -83,12 +110,88 @@ func (wk *Worker) Start(ctx context.Context) error {
 		return err
 	}
 
+	if wk.dataExporter != nil {
+		if err := wk.rabbitMQ.Consume(ctx, v1service.DataExportQueue, wk.handleDataExport(ctx)); err != nil {
+			wk.logger.Error().Err(err).Msg("Failed to start data export consumer")
+			return err
+		}
+
+		go wk.runEvery(ctx, housekeepingInterval, wk.pruneSecurityEvents)
+		go wk.runEvery(ctx, housekeepingInterval, wk.pruneDataExports)
+		go wk.runEvery(ctx, housekeepingInterval, wk.anonymizeClosedAccounts)
+	}
+
 	wk.logger.Info().Msgf("Worker started, consuming from queue: %s", emailQueueName)
 	<-ctx.Done()
 	wk.logger.Info().Msgf("Worker stopped consuming due to context cancellation: %s", emailQueueName)
 	return ctx.Err()
 }
 
+// handleDataExport builds the archive of a queued export and emails its download link
+func (wk *Worker) handleDataExport(ctx context.Context) func([]byte) error {
+	return func(body []byte) error {
+		var job v1service.DataExportJob
+		if err := json.Unmarshal(body, &job); err != nil {
+			wk.logger.Error().Err(err).Msg("Failed to unmarshal data export job")
+			return err
+		}
+
+		if err := wk.dataExporter.Process(ctx, job, wk.mailService); err != nil {
+			wk.logger.Error().Err(err).Str("export_uuid", job.ExportUUID).Msg("Failed to process data export")
+			return err
+		}
+
+		wk.logger.Info().Str("export_uuid", job.ExportUUID).Msg("Data export completed")
+		return nil
+	}
+}
+
+// runEvery runs task right away and then on every tick until the worker stops
+func (wk *Worker) runEvery(ctx context.Context, interval time.Duration, task func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		task(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pruneSecurityEvents deletes security events past SECURITY_EVENT_RETENTION_DAYS
+func (wk *Worker) pruneSecurityEvents(ctx context.Context) {
+	deleted, err := v1service.PruneSecurityEvents(ctx, wk.securityEventRepo, v1service.SecurityEventRetention())
+	if err != nil {
+		wk.logger.Error().Err(err).Msg("Failed to prune security events")
+	} else if deleted > 0 {
+		wk.logger.Info().Int64("deleted", deleted).Msg("Pruned old security events")
+	}
+}
+
+func (wk *Worker) pruneDataExports(ctx context.Context) {
+	deleted, err := v1service.PruneDataExports(ctx, wk.dataExportRepo)
+	if err != nil {
+		wk.logger.Error().Err(err).Msg("Failed to prune data exports")
+	} else if deleted > 0 {
+		wk.logger.Info().Int64("deleted", deleted).Msg("Pruned expired data exports")
+	}
+}
+
+// anonymizeClosedAccounts replaces the personal data of accounts closed longer than ACCOUNT_CLOSURE_GRACE_DAYS
+func (wk *Worker) anonymizeClosedAccounts(ctx context.Context) {
+	anonymized, err := v1service.AnonymizeClosedAccounts(ctx, wk.accountClosureRepo, v1service.AccountClosureBatchSize)
+	if err != nil {
+		wk.logger.Error().Err(err).Msg("Failed to anonymize closed accounts")
+	}
+	if anonymized > 0 {
+		wk.logger.Info().Int("anonymized", anonymized).Msg("Anonymized closed accounts")
+	}
+}
+
 func (wk *Worker) Shutdown(ctx context.Context) error {
 	wk.logger.Info().Msgf("Shutting down worker .....")
 	if err := wk.rabbitMQ.Close(); err != nil {
@@ -97,6 +200,10 @@ func (wk *Worker) Shutdown(ctx context.Context) error {
 	}
 	wk.logger.Info().Msgf("RabbitMQ connection closed successfully")
 
+	if db.DBpool != nil {
+		db.DBpool.Close()
+	}
+
 	select {
 	case <-ctx.Done():
 		if ctx.Err() == context.DeadlineExceeded {
@@ -0,0 +1,41 @@
+package app
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/routes"
+	v1routes "gin/user-management-api/internal/routes/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/pkg/cache"
+)
+
+type ApiKeyModule struct {
+	routes  routes.Route
+	service v1service.ApiKeyService
+}
+
+func NewApiKeyModule(ctx *MouldeContext, cacheService cache.RedisCacheService) *ApiKeyModule {
+	// Initialize the repositories
+	apiKeyRepository := repository.NewSqlApiKeyRepository(ctx.DB)
+	userRepository := repository.NewSqlUserRepository(ctx.DB)
+
+	// Initialize the api key services
+	apiKeyService := v1service.NewApiKeyService(apiKeyRepository, userRepository, cacheService)
+
+	// Initialize the api key handler
+	apiKeyHandler := v1handler.NewApiKeyHandler(apiKeyService)
+
+	// Initialize the api key routes
+	apiKeyRoutes := v1routes.NewApiKeyRoutes(apiKeyHandler)
+
+	return &ApiKeyModule{routes: apiKeyRoutes, service: apiKeyService}
+}
+
+func (m *ApiKeyModule) Routes() routes.Route {
+	return m.routes
+}
+
+// Authenticator is used by the global API key middleware
+func (m *ApiKeyModule) Authenticator() v1service.ApiKeyService {
+	return m.service
+}
@@ -5,11 +5,16 @@ import (
 	"gin/user-management-api/internal/config"
 	"gin/user-management-api/internal/db"
 	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
 	"gin/user-management-api/internal/routes"
+	v1service "gin/user-management-api/internal/service/v1"
 	"gin/user-management-api/internal/utils"
 	"gin/user-management-api/internal/validation"
 	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/pkg/breach"
 	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/directory"
+	"gin/user-management-api/pkg/hasher"
 	"gin/user-management-api/pkg/loggers"
 	"gin/user-management-api/pkg/mail"
 	"gin/user-management-api/pkg/rabbitmq"
@@ -36,6 +41,8 @@ type Application struct {
 type MouldeContext struct {
 	DB    sqlc.Querier
 	Redis *redis.Client
+	// Directory is nil unless LDAP_URL is set
+	Directory directory.Client
 }
 
 func NewApplication(cfg *config.Config) (*Application, error) {
@@ -44,6 +51,32 @@ func NewApplication(cfg *config.Config) (*Application, error) {
 		return nil, err
 	}
 
+	hasherParams, err := hasher.ParamsFromEnv()
+	if err != nil {
+		loggers.Log.Fatal().Err(err).Msg("Failed to configure password hasher")
+		return nil, err
+	}
+	hasher.Default = hasher.NewArgon2idHasher(hasherParams)
+
+	if err := v1service.InitMfaEncryptKey(); err != nil {
+		loggers.Log.Fatal().Err(err).Msg("Failed to load MFA encryption key")
+		return nil, err
+	}
+
+	if err := v1service.InitMagicLinkSecret(); err != nil {
+		loggers.Log.Fatal().Err(err).Msg("Failed to load magic link secret")
+		return nil, err
+	}
+
+	breach.Default = breach.NewChecker(utils.GetEnv("BREACHED_PASSWORDS_PATH", ""))
+	if err := breach.Default.Load(); err != nil {
+		loggers.Log.Fatal().Err(err).Msg("Failed to load breached password list")
+		return nil, err
+	}
+	go breach.Default.Watch(context.Background(), time.Minute, func(err error) {
+		loggers.Log.Warn().Err(err).Msg("Failed to reload breached password list")
+	})
+
 	r := gin.Default()
 
 	if err := db.InitDB(); err != nil {
@@ -53,7 +86,13 @@ func NewApplication(cfg *config.Config) (*Application, error) {
 
 	redisClinet := config.NewRedisClient()
 	cacheRedisService := cache.NewRedisCacheService(redisClinet)
-	tokenService := auth.NewJWTService(cacheRedisService)
+
+	keySet, err := auth.LoadKeySetFromEnv()
+	if err != nil {
+		loggers.Log.Fatal().Err(err).Msg("Failed to load JWT signing keys")
+		return nil, err
+	}
+	tokenService := auth.NewJWTService(cacheRedisService, keySet)
 
 	mailLogger := utils.NewLoggerWithPath("mail.log", "info")
 	factory, err := mail.NewProviderFactory(mail.ProviderMailtrap)
@@ -79,12 +118,34 @@ func NewApplication(cfg *config.Config) (*Application, error) {
 		Redis: redisClinet,
 	}
 
+	ldapConfig, ldapEnabled, err := directory.ConfigFromEnv()
+	if err != nil {
+		loggers.Log.Fatal().Err(err).Msg("Failed to configure LDAP directory")
+		return nil, err
+	}
+	if ldapEnabled {
+		ctx.Directory = directory.NewLDAPClient(ldapConfig)
+	}
+
+	apiKeyModule := NewApiKeyModule(ctx, cacheRedisService)
+	userStatusChecker := v1service.NewUserStatusService(repository.NewSqlUserRepository(ctx.DB), cacheRedisService)
+
 	models := []Module{
-		NewUserModule(ctx),
+		NewUserModule(ctx, tokenService),
 		NewAuthModule(ctx, tokenService, cacheRedisService, mailService, rabbitmgService),
+		NewRoleModule(ctx, cacheRedisService),
+		NewSessionModule(ctx, tokenService),
+		NewProfileModule(ctx, tokenService, cacheRedisService, mailService, rabbitmgService),
+		NewImpersonationModule(ctx, tokenService),
+		apiKeyModule,
+		NewSecurityEventModule(ctx),
+		NewWellKnownModule(tokenService),
+		NewOAuthModule(ctx, tokenService, cacheRedisService, mailService, rabbitmgService),
+		NewInvitationModule(ctx, cacheRedisService, rabbitmgService),
+		NewDataExportModule(ctx, tokenService, cacheRedisService, rabbitmgService),
 	}
 
-	routes.RegisterRoutes(r, tokenService, cacheRedisService, getModlRoutes(models)...)
+	routes.RegisterRoutes(r, tokenService, cacheRedisService, apiKeyModule.Authenticator(), userStatusChecker, getModlRoutes(models)...)
 
 	return &Application{
 		config:  cfg,
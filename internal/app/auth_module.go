@@ -19,9 +19,14 @@ type AuthModule struct {
 func NewAuthModule(ctx *MouldeContext, tokenService auth.TokenService, cacheService cache.RedisCacheService, mailService mail.EmailProviderService, rabbitService rabbitmq.RabbitMQSerivce) *AuthModule {
 	// Initialize the auth repository
 	userRepository := repository.NewSqlUserRepository(ctx.DB)
+	roleRepository := repository.NewSqlRoleRepository(ctx.DB)
+	mfaRepository := repository.NewSqlMfaRepository(ctx.DB)
+	securityEventRepository := repository.NewSqlSecurityEventRepository(ctx.DB)
+	accountClosureRepository := repository.NewSqlAccountClosureRepository(ctx.DB)
+	identityRepository := repository.NewSqlIdentityRepository(ctx.DB)
 
 	// Initialize the auth services
-	authService := v1service.NewAuthService(userRepository, tokenService, cacheService, mailService, rabbitService)
+	authService := v1service.NewAuthService(userRepository, roleRepository, mfaRepository, securityEventRepository, accountClosureRepository, identityRepository, newAuthenticators(ctx, userRepository, accountClosureRepository, identityRepository, cacheService), tokenService, cacheService, mailService, rabbitService)
 
 	// Initialize the auth handler
 	authHandler := v1handler.NewAuthHandler(authService)
@@ -35,3 +40,14 @@ func NewAuthModule(ctx *MouldeContext, tokenService auth.TokenService, cacheServ
 func (m *AuthModule) Routes() routes.Route {
 	return m.routes
 }
+
+// newAuthenticators checks local passwords first so directory lookups only happen for unknown or directory accounts
+func newAuthenticators(ctx *MouldeContext, userRepository repository.UserRepository, accountClosureRepository repository.AccountClosureRepository, identityRepository repository.IdentityRepository, cacheService cache.RedisCacheService) []v1service.Authenticator {
+	authenticators := []v1service.Authenticator{
+		v1service.NewLocalAuthenticator(userRepository, accountClosureRepository, identityRepository),
+	}
+	if ctx.Directory != nil {
+		authenticators = append(authenticators, v1service.NewLDAPAuthenticator(ctx.Directory, userRepository, accountClosureRepository, identityRepository, cacheService))
+	}
+	return authenticators
+}
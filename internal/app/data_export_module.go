@@ -0,0 +1,37 @@
+package app
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/routes"
+	v1routes "gin/user-management-api/internal/routes/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/rabbitmq"
+)
+
+type DataExportModule struct {
+	routes routes.Route
+}
+
+func NewDataExportModule(ctx *MouldeContext, tokenService auth.TokenService, cacheService cache.RedisCacheService, rabbitService rabbitmq.RabbitMQSerivce) *DataExportModule {
+	// Initialize the repositories
+	dataExportRepository := repository.NewSqlDataExportRepository(ctx.DB)
+	userRepository := repository.NewSqlUserRepository(ctx.DB)
+
+	// Initialize the data export services
+	dataExportService := v1service.NewDataExportService(dataExportRepository, userRepository, tokenService, cacheService, rabbitService)
+
+	// Initialize the data export handler
+	dataExportHandler := v1handler.NewDataExportHandler(dataExportService)
+
+	// Initialize the data export routes
+	dataExportRoutes := v1routes.NewDataExportRoutes(dataExportHandler)
+
+	return &DataExportModule{routes: dataExportRoutes}
+}
+
+func (m *DataExportModule) Routes() routes.Route {
+	return m.routes
+}
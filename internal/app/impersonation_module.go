@@ -0,0 +1,36 @@
+package app
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/routes"
+	v1routes "gin/user-management-api/internal/routes/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/pkg/auth"
+)
+
+type ImpersonationModule struct {
+	routes routes.Route
+}
+
+func NewImpersonationModule(ctx *MouldeContext, tokenService auth.TokenService) *ImpersonationModule {
+	// Initialize the repositories
+	userRepository := repository.NewSqlUserRepository(ctx.DB)
+	roleRepository := repository.NewSqlRoleRepository(ctx.DB)
+	auditRepository := repository.NewSqlAuditRepository(ctx.DB)
+
+	// Initialize the impersonation services
+	impersonationService := v1service.NewImpersonationService(userRepository, roleRepository, auditRepository, tokenService)
+
+	// Initialize the impersonation handler
+	impersonationHandler := v1handler.NewImpersonationHandler(impersonationService)
+
+	// Initialize the impersonation routes
+	impersonationRoutes := v1routes.NewImpersonationRoutes(impersonationHandler)
+
+	return &ImpersonationModule{routes: impersonationRoutes}
+}
+
+func (m *ImpersonationModule) Routes() routes.Route {
+	return m.routes
+}
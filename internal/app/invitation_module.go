@@ -0,0 +1,36 @@
+package app
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/routes"
+	v1routes "gin/user-management-api/internal/routes/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/rabbitmq"
+)
+
+type InvitationModule struct {
+	routes routes.Route
+}
+
+func NewInvitationModule(ctx *MouldeContext, cacheService cache.RedisCacheService, rabbitService rabbitmq.RabbitMQSerivce) *InvitationModule {
+	// Initialize the repositories
+	invitationRepository := repository.NewSqlInvitationRepository(ctx.DB)
+	userRepository := repository.NewSqlUserRepository(ctx.DB)
+
+	// Initialize the invitation services
+	invitationService := v1service.NewInvitationService(invitationRepository, userRepository, cacheService, rabbitService)
+
+	// Initialize the invitation handler
+	invitationHandler := v1handler.NewInvitationHandler(invitationService)
+
+	// Initialize the invitation routes
+	invitationRoutes := v1routes.NewInvitationRoutes(invitationHandler)
+
+	return &InvitationModule{routes: invitationRoutes}
+}
+
+func (m *InvitationModule) Routes() routes.Route {
+	return m.routes
+}
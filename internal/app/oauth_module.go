@@ -0,0 +1,44 @@
+package app
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/routes"
+	v1routes "gin/user-management-api/internal/routes/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/mail"
+	"gin/user-management-api/pkg/rabbitmq"
+)
+
+type OAuthModule struct {
+	routes routes.Route
+}
+
+func NewOAuthModule(ctx *MouldeContext, tokenService auth.TokenService, cacheService cache.RedisCacheService, mailService mail.EmailProviderService, rabbitService rabbitmq.RabbitMQSerivce) *OAuthModule {
+	// Initialize the oauth repository
+	userRepository := repository.NewSqlUserRepository(ctx.DB)
+	roleRepository := repository.NewSqlRoleRepository(ctx.DB)
+	mfaRepository := repository.NewSqlMfaRepository(ctx.DB)
+	securityEventRepository := repository.NewSqlSecurityEventRepository(ctx.DB)
+	accountClosureRepository := repository.NewSqlAccountClosureRepository(ctx.DB)
+	identityRepository := repository.NewSqlIdentityRepository(ctx.DB)
+	oauthRepository := repository.NewSqlOAuthRepository(ctx.DB)
+
+	// Initialize the oauth services
+	authService := v1service.NewAuthService(userRepository, roleRepository, mfaRepository, securityEventRepository, accountClosureRepository, identityRepository, newAuthenticators(ctx, userRepository, accountClosureRepository, identityRepository, cacheService), tokenService, cacheService, mailService, rabbitService)
+	oauthService := v1service.NewOAuthService(authService, oauthRepository)
+
+	// Initialize the oauth handler
+	oauthHandler := v1handler.NewOAuthHandler(oauthService)
+
+	// Initialize the oauth routes
+	oauthRoutes := v1routes.NewOAuthRoutes(oauthHandler)
+
+	return &OAuthModule{routes: oauthRoutes}
+}
+
+func (m *OAuthModule) Routes() routes.Route {
+	return m.routes
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/routes"
+	v1routes "gin/user-management-api/internal/routes/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/mail"
+	"gin/user-management-api/pkg/rabbitmq"
+)
+
+type ProfileModule struct {
+	routes routes.Route
+}
+
+func NewProfileModule(ctx *MouldeContext, tokenService auth.TokenService, cacheService cache.RedisCacheService, mailService mail.EmailProviderService, rabbitService rabbitmq.RabbitMQSerivce) *ProfileModule {
+	// Initialize the repositories
+	userRepository := repository.NewSqlUserRepository(ctx.DB)
+	roleRepository := repository.NewSqlRoleRepository(ctx.DB)
+	mfaRepository := repository.NewSqlMfaRepository(ctx.DB)
+	securityEventRepository := repository.NewSqlSecurityEventRepository(ctx.DB)
+	accountClosureRepository := repository.NewSqlAccountClosureRepository(ctx.DB)
+	identityRepository := repository.NewSqlIdentityRepository(ctx.DB)
+
+	// Initialize the profile services, passwords are confirmed with the login lockout of the auth service
+	authService := v1service.NewAuthService(userRepository, roleRepository, mfaRepository, securityEventRepository, accountClosureRepository, identityRepository, newAuthenticators(ctx, userRepository, accountClosureRepository, identityRepository, cacheService), tokenService, cacheService, mailService, rabbitService)
+	profileService := v1service.NewProfileService(userRepository, accountClosureRepository, securityEventRepository, authService, tokenService, cacheService)
+
+	// Initialize the profile handler
+	profileHandler := v1handler.NewProfileHandler(profileService)
+
+	// Initialize the profile routes
+	profileRoutes := v1routes.NewProfileRoutes(profileHandler)
+
+	return &ProfileModule{routes: profileRoutes}
+}
+
+func (m *ProfileModule) Routes() routes.Route {
+	return m.routes
+}
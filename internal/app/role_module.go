@@ -0,0 +1,35 @@
+package app
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/routes"
+	v1routes "gin/user-management-api/internal/routes/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/pkg/cache"
+)
+
+type RoleModule struct {
+	routes routes.Route
+}
+
+func NewRoleModule(ctx *MouldeContext, cacheService cache.RedisCacheService) *RoleModule {
+	// Initialize the role repository
+	roleRepository := repository.NewSqlRoleRepository(ctx.DB)
+	userRepository := repository.NewSqlUserRepository(ctx.DB)
+
+	// Initialize the role services
+	roleService := v1service.NewRoleService(roleRepository, userRepository, cacheService)
+
+	// Initialize the role handler
+	roleHandler := v1handler.NewRoleHandler(roleService)
+
+	// Initialize the role routes
+	roleRoutes := v1routes.NewRoleRoutes(roleHandler)
+
+	return &RoleModule{routes: roleRoutes}
+}
+
+func (m *RoleModule) Routes() routes.Route {
+	return m.routes
+}
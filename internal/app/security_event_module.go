@@ -0,0 +1,34 @@
+package app
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/routes"
+	v1routes "gin/user-management-api/internal/routes/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+)
+
+type SecurityEventModule struct {
+	routes routes.Route
+}
+
+func NewSecurityEventModule(ctx *MouldeContext) *SecurityEventModule {
+	// Initialize the repositories
+	securityEventRepository := repository.NewSqlSecurityEventRepository(ctx.DB)
+	userRepository := repository.NewSqlUserRepository(ctx.DB)
+
+	// Initialize the security event services
+	securityEventService := v1service.NewSecurityEventService(securityEventRepository, userRepository)
+
+	// Initialize the security event handler
+	securityEventHandler := v1handler.NewSecurityEventHandler(securityEventService)
+
+	// Initialize the security event routes
+	securityEventRoutes := v1routes.NewSecurityEventRoutes(securityEventHandler)
+
+	return &SecurityEventModule{routes: securityEventRoutes}
+}
+
+func (m *SecurityEventModule) Routes() routes.Route {
+	return m.routes
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/routes"
+	v1routes "gin/user-management-api/internal/routes/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/pkg/auth"
+)
+
+type SessionModule struct {
+	routes routes.Route
+}
+
+func NewSessionModule(ctx *MouldeContext, tokenService auth.TokenService) *SessionModule {
+	// Initialize the user repository
+	userRepository := repository.NewSqlUserRepository(ctx.DB)
+
+	// Initialize the session services
+	sessionService := v1service.NewSessionService(userRepository, tokenService)
+
+	// Initialize the session handler
+	sessionHandler := v1handler.NewSessionHandler(sessionService)
+
+	// Initialize the session routes
+	sessionRoutes := v1routes.NewSessionRoutes(sessionHandler)
+
+	return &SessionModule{routes: sessionRoutes}
+}
+
+func (m *SessionModule) Routes() routes.Route {
+	return m.routes
+}
@@ -6,6 +6,7 @@ import (
 	"gin/user-management-api/internal/routes"
 	v1routes "gin/user-management-api/internal/routes/v1"
 	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/pkg/auth"
 )
 
 type UserModule struct {
@@ -13,12 +14,12 @@ type UserModule struct {
 
 }
 
-func NewUserModule(ctx *MouldeContext) *UserModule {
+func NewUserModule(ctx *MouldeContext, tokenService auth.TokenService) *UserModule {
 	// Initialize the user repository
 	userRepository := repository.NewSqlUserRepository(ctx.DB)
 
 	// Initialize the user services
-	userService := v1service.NewUserService(userRepository, ctx.Redis)
+	userService := v1service.NewUserService(userRepository, tokenService, ctx.Redis)
 
 	// Initialize the user handler
 	userHandler := v1handler.NewUserHandler(userService)
@@ -0,0 +1,26 @@
+package app
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/routes"
+	v1routes "gin/user-management-api/internal/routes/v1"
+	"gin/user-management-api/pkg/auth"
+)
+
+type WellKnownModule struct {
+	routes routes.Route
+}
+
+func NewWellKnownModule(tokenService auth.TokenService) *WellKnownModule {
+	// Initialize the well-known handler
+	wellKnownHandler := v1handler.NewWellKnownHandler(tokenService)
+
+	// Initialize the well-known routes
+	wellKnownRoutes := v1routes.NewWellKnownRoutes(wellKnownHandler)
+
+	return &WellKnownModule{routes: wellKnownRoutes}
+}
+
+func (m *WellKnownModule) Routes() routes.Route {
+	return m.routes
+}
@@ -0,0 +1,19 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_user_level;
+
+UPDATE users SET user_level = 3 WHERE user_level NOT IN (1,2,3);
+
+ALTER TABLE users ADD CONSTRAINT users_user_level_check CHECK (user_level IN (1,2,3));
+
+COMMENT ON COLUMN users.user_level IS 'User status: 1 - Administrator, 2 - Moderator, 3 - Member';
+
+DROP INDEX IF EXISTS idx_user_roles_role_id;
+
+DROP INDEX IF EXISTS idx_role_permissions_permission_id;
+
+DROP TABLE IF EXISTS user_roles;
+
+DROP TABLE IF EXISTS role_permissions;
+
+DROP TABLE IF EXISTS permissions;
+
+DROP TABLE IF EXISTS roles;
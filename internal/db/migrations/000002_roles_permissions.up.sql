@@ -0,0 +1,69 @@
+CREATE TABLE IF NOT EXISTS roles (
+  role_id          INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  role_code        VARCHAR(50) NOT NULL UNIQUE,
+  role_name        VARCHAR(100) NOT NULL,
+  role_description TEXT,
+  role_is_system   BOOLEAN NOT NULL DEFAULT FALSE,
+  role_created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  role_updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN roles.role_is_system IS 'System roles are seeded by migrations and cannot be deleted';
+
+CREATE TABLE IF NOT EXISTS permissions (
+  permission_id          INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  permission_code        VARCHAR(100) NOT NULL UNIQUE,
+  permission_description TEXT
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+  role_id       INT NOT NULL REFERENCES roles(role_id) ON DELETE CASCADE,
+  permission_id INT NOT NULL REFERENCES permissions(permission_id) ON DELETE CASCADE,
+  PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+  user_id         INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+  role_id         INT NOT NULL REFERENCES roles(role_id) ON DELETE CASCADE,
+  user_role_created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, role_id)
+);
+
+COMMENT ON TABLE user_roles IS 'Additional roles granted to a user on top of users.user_level';
+
+CREATE INDEX IF NOT EXISTS idx_role_permissions_permission_id ON role_permissions(permission_id);
+CREATE INDEX IF NOT EXISTS idx_user_roles_role_id ON user_roles(role_id);
+
+-- Seed the legacy levels as system roles, keeping their ids
+INSERT INTO roles (role_id, role_code, role_name, role_is_system) VALUES
+  (1, 'administrator', 'Administrator', TRUE),
+  (2, 'moderator', 'Moderator', TRUE),
+  (3, 'member', 'Member', TRUE)
+ON CONFLICT (role_id) DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('roles', 'role_id'), (SELECT MAX(role_id) FROM roles));
+
+INSERT INTO permissions (permission_code, permission_description) VALUES
+  ('users:read', 'List and view users'),
+  ('users:create', 'Create users'),
+  ('users:update', 'Update any user field'),
+  ('users:update_status', 'Change user status'),
+  ('users:delete', 'Soft delete users'),
+  ('users:restore', 'Restore soft deleted users'),
+  ('users:trash', 'Permanently delete users'),
+  ('roles:manage', 'Manage roles and permissions')
+ON CONFLICT (permission_code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT 1, permission_id FROM permissions
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT 2, permission_id FROM permissions WHERE permission_code IN ('users:read', 'users:update_status')
+ON CONFLICT DO NOTHING;
+
+-- user_level now references roles instead of a fixed list
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_user_level_check;
+ALTER TABLE users ADD CONSTRAINT fk_users_user_level FOREIGN KEY (user_level) REFERENCES roles(role_id);
+
+COMMENT ON COLUMN users.user_level IS 'Primary role of the user, references roles.role_id';
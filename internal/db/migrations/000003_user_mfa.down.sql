@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_user_recovery_codes_user_id;
+
+DROP TABLE IF EXISTS user_recovery_codes;
+
+DROP TABLE IF EXISTS user_mfa;
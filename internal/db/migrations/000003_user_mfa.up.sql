@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS user_mfa (
+  user_id         INT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+  mfa_secret      TEXT NOT NULL,
+  mfa_enabled     BOOLEAN NOT NULL DEFAULT FALSE,
+  mfa_enabled_at  TIMESTAMPTZ DEFAULT NULL,
+  mfa_created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  mfa_updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN user_mfa.mfa_secret IS 'TOTP secret encrypted with AES-GCM';
+COMMENT ON COLUMN user_mfa.mfa_enabled IS 'FALSE while the enrollment is pending confirmation';
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+  recovery_code_id         INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  user_id                  INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+  recovery_code_hash       VARCHAR(64) NOT NULL,
+  recovery_code_used_at    TIMESTAMPTZ DEFAULT NULL,
+  recovery_code_created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN user_recovery_codes.recovery_code_hash IS 'SHA-256 hex digest of the recovery code';
+
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user_id ON user_recovery_codes(user_id);
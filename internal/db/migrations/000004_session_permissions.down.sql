@@ -0,0 +1 @@
+DELETE FROM permissions WHERE permission_code = 'users:sessions';
@@ -0,0 +1,7 @@
+INSERT INTO permissions (permission_code, permission_description) VALUES
+  ('users:sessions', 'View and revoke sessions of any user')
+ON CONFLICT (permission_code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT 1, permission_id FROM permissions WHERE permission_code = 'users:sessions'
+ON CONFLICT DO NOTHING;
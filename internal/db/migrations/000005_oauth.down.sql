@@ -0,0 +1,7 @@
+DELETE FROM permissions WHERE permission_code = 'oauth:manage';
+
+DROP INDEX IF EXISTS idx_oauth_consents_oauth_client_id;
+
+DROP TABLE IF EXISTS oauth_consents;
+
+DROP TABLE IF EXISTS oauth_clients;
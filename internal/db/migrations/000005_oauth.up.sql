@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS oauth_clients (
+  oauth_client_id        INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  client_id              VARCHAR(64) NOT NULL UNIQUE,
+  client_secret_hash     VARCHAR(255) DEFAULT NULL,
+  client_name            VARCHAR(100) NOT NULL,
+  client_redirect_uris   TEXT[] NOT NULL DEFAULT '{}',
+  client_grant_types     TEXT[] NOT NULL DEFAULT '{}',
+  client_scopes          TEXT[] NOT NULL DEFAULT '{}',
+  client_created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  client_updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN oauth_clients.client_secret_hash IS 'Bcrypt hash of the client secret, NULL for public clients that must use PKCE';
+COMMENT ON COLUMN oauth_clients.client_grant_types IS 'Allowed grants: authorization_code, refresh_token, client_credentials';
+COMMENT ON COLUMN oauth_clients.client_scopes IS 'Permission codes the client may request';
+
+CREATE TABLE IF NOT EXISTS oauth_consents (
+  user_id            INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+  oauth_client_id    INT NOT NULL REFERENCES oauth_clients(oauth_client_id) ON DELETE CASCADE,
+  consent_scopes     TEXT[] NOT NULL DEFAULT '{}',
+  consent_created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  consent_updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, oauth_client_id)
+);
+
+COMMENT ON TABLE oauth_consents IS 'Scopes a user has approved for an OAuth client';
+
+CREATE INDEX idx_oauth_consents_oauth_client_id ON oauth_consents(oauth_client_id);
+
+INSERT INTO permissions (permission_code, permission_description) VALUES
+  ('oauth:manage', 'Manage OAuth clients')
+ON CONFLICT (permission_code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT 1, permission_id FROM permissions WHERE permission_code = 'oauth:manage'
+ON CONFLICT DO NOTHING;
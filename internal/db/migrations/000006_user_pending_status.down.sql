@@ -0,0 +1,7 @@
+UPDATE users SET user_status = 2 WHERE user_status = 4;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_user_status_check;
+
+ALTER TABLE users ADD CONSTRAINT users_user_status_check CHECK (user_status IN (1,2,3));
+
+COMMENT ON COLUMN users.user_status IS 'User status: 1 - Active, 2 - Inactive, 3 - Banned';
@@ -0,0 +1,5 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_user_status_check;
+
+ALTER TABLE users ADD CONSTRAINT users_user_status_check CHECK (user_status IN (1,2,3,4));
+
+COMMENT ON COLUMN users.user_status IS 'User status: 1 - Active, 2 - Inactive, 3 - Banned, 4 - Pending verification';
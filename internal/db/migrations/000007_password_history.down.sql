@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_password_history_user_id;
+
+DROP TABLE IF EXISTS password_history;
+
+ALTER TABLE users DROP COLUMN IF EXISTS user_password_changed_at;
@@ -0,0 +1,14 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS user_password_changed_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+COMMENT ON COLUMN users.user_password_changed_at IS 'Last time the password was set, used for the expiry policy';
+
+CREATE TABLE IF NOT EXISTS password_history (
+  password_history_id         INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  user_id                     INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+  password_hash               VARCHAR(90) NOT NULL,
+  password_history_created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN password_history.password_hash IS 'Bcrypt hash of a password the user had before';
+
+CREATE INDEX IF NOT EXISTS idx_password_history_user_id ON password_history(user_id, password_history_created_at DESC);
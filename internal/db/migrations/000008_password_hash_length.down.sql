@@ -0,0 +1,6 @@
+COMMENT ON COLUMN password_history.password_hash IS 'Bcrypt hash of a password the user had before';
+COMMENT ON COLUMN users.user_password IS NULL;
+
+ALTER TABLE password_history ALTER COLUMN password_hash TYPE VARCHAR(90);
+
+ALTER TABLE users ALTER COLUMN user_password TYPE VARCHAR(90);
@@ -0,0 +1,6 @@
+ALTER TABLE users ALTER COLUMN user_password TYPE VARCHAR(255);
+
+ALTER TABLE password_history ALTER COLUMN password_hash TYPE VARCHAR(255);
+
+COMMENT ON COLUMN users.user_password IS 'PHC formatted argon2id hash, bcrypt hashes are upgraded on the next login';
+COMMENT ON COLUMN password_history.password_hash IS 'Argon2id or bcrypt hash of a password the user had before';
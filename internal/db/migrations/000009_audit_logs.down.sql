@@ -0,0 +1,7 @@
+DELETE FROM permissions WHERE permission_code = 'users:impersonate';
+
+DROP INDEX IF EXISTS idx_audit_logs_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_target_id;
+DROP INDEX IF EXISTS idx_audit_logs_actor_id;
+
+DROP TABLE IF EXISTS audit_logs;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS audit_logs (
+  audit_log_id         INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  audit_actor_id       INT REFERENCES users(user_id) ON DELETE SET NULL,
+  audit_target_id      INT REFERENCES users(user_id) ON DELETE SET NULL,
+  audit_action         VARCHAR(50) NOT NULL,
+  audit_ip_address     VARCHAR(45) NOT NULL DEFAULT '',
+  audit_user_agent     TEXT NOT NULL DEFAULT '',
+  audit_metadata       JSONB NOT NULL DEFAULT '{}',
+  audit_created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN audit_logs.audit_actor_id IS 'User who performed the action';
+COMMENT ON COLUMN audit_logs.audit_target_id IS 'User the action was performed on';
+COMMENT ON COLUMN audit_logs.audit_action IS 'Action name, e.g. impersonation.start, impersonation.stop';
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs(audit_actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_target_id ON audit_logs(audit_target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs(audit_created_at);
+
+INSERT INTO permissions (permission_code, permission_description) VALUES
+  ('users:impersonate', 'Act as another user with a short-lived token')
+ON CONFLICT (permission_code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT 1, permission_id FROM permissions WHERE permission_code = 'users:impersonate'
+ON CONFLICT DO NOTHING;
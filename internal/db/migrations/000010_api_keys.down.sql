@@ -0,0 +1,5 @@
+DELETE FROM permissions WHERE permission_code = 'api_keys:manage';
+
+DROP INDEX IF EXISTS idx_api_keys_owner_id;
+
+DROP TABLE IF EXISTS api_keys;
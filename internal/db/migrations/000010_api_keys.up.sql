@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+  api_key_id           INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  api_key_uuid         UUID NOT NULL DEFAULT gen_random_uuid() UNIQUE,
+  api_key_owner_id     INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+  api_key_name         VARCHAR(100) NOT NULL,
+  api_key_prefix       VARCHAR(16) NOT NULL,
+  api_key_hash         VARCHAR(64) NOT NULL UNIQUE,
+  api_key_scopes       TEXT[] NOT NULL DEFAULT '{}',
+  api_key_expires_at   TIMESTAMPTZ DEFAULT NULL,
+  api_key_last_used_at TIMESTAMPTZ DEFAULT NULL,
+  api_key_revoked_at   TIMESTAMPTZ DEFAULT NULL,
+  api_key_created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  api_key_updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN api_keys.api_key_prefix IS 'First characters of the key, shown to identify it';
+COMMENT ON COLUMN api_keys.api_key_hash IS 'SHA-256 hex digest of the key, the key itself is only shown once';
+COMMENT ON COLUMN api_keys.api_key_expires_at IS 'NULL means the key does not expire';
+COMMENT ON COLUMN api_keys.api_key_revoked_at IS 'NULL means the key is active';
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_owner_id ON api_keys(api_key_owner_id);
+
+INSERT INTO permissions (permission_code, permission_description) VALUES
+  ('api_keys:manage', 'Create, rotate and revoke API keys')
+ON CONFLICT (permission_code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT 1, permission_id FROM permissions WHERE permission_code = 'api_keys:manage'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+DELETE FROM permissions WHERE permission_code = 'security_events:read';
+
+DROP INDEX IF EXISTS idx_security_events_created_at;
+DROP INDEX IF EXISTS idx_security_events_user_id_created_at;
+
+DROP TABLE IF EXISTS security_events;
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS security_events (
+  security_event_id         BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  security_event_user_id    INT REFERENCES users(user_id) ON DELETE CASCADE,
+  security_event_email      VARCHAR(255) NOT NULL DEFAULT '',
+  security_event_type       VARCHAR(50) NOT NULL,
+  security_event_outcome    VARCHAR(20) NOT NULL,
+  security_event_reason     VARCHAR(255) NOT NULL DEFAULT '',
+  security_event_ip_address VARCHAR(45) NOT NULL DEFAULT '',
+  security_event_user_agent TEXT NOT NULL DEFAULT '',
+  security_event_trace_id   VARCHAR(64) NOT NULL DEFAULT '',
+  security_event_created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN security_events.security_event_user_id IS 'NULL when the email did not match an account';
+COMMENT ON COLUMN security_events.security_event_email IS 'Email that was submitted, kept for failed logins on unknown accounts';
+COMMENT ON COLUMN security_events.security_event_type IS 'Event name, e.g. login, token_refresh, logout, password_reset, account_locked';
+COMMENT ON COLUMN security_events.security_event_outcome IS 'success or failure';
+
+CREATE INDEX IF NOT EXISTS idx_security_events_user_id_created_at ON security_events(security_event_user_id, security_event_created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_security_events_created_at ON security_events(security_event_created_at);
+
+INSERT INTO permissions (permission_code, permission_description) VALUES
+  ('security_events:read', 'Search the login history and security events of every user')
+ON CONFLICT (permission_code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT 1, permission_id FROM permissions WHERE permission_code = 'security_events:read'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_user_invitations_pending_email;
+
+DROP TABLE IF EXISTS user_invitations;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS user_invitations (
+  invitation_id          INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  invitation_uuid        UUID NOT NULL DEFAULT gen_random_uuid() UNIQUE,
+  invitation_email       VARCHAR(150) NOT NULL,
+  invitation_fullname    VARCHAR(100) NOT NULL,
+  invitation_level       INT NOT NULL REFERENCES roles(role_id),
+  invitation_token_hash  VARCHAR(64) NOT NULL UNIQUE,
+  invitation_invited_by  INT REFERENCES users(user_id) ON DELETE SET NULL,
+  invitation_expires_at  TIMESTAMPTZ NOT NULL,
+  invitation_accepted_at TIMESTAMPTZ DEFAULT NULL,
+  invitation_revoked_at  TIMESTAMPTZ DEFAULT NULL,
+  invitation_created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  invitation_updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN user_invitations.invitation_level IS 'Primary role given to the account, references roles.role_id';
+COMMENT ON COLUMN user_invitations.invitation_token_hash IS 'SHA-256 hex digest of the emailed token, rotated on resend';
+COMMENT ON COLUMN user_invitations.invitation_invited_by IS 'Administrator who sent the invitation, NULL once they are deleted';
+COMMENT ON COLUMN user_invitations.invitation_accepted_at IS 'NULL means the invitation has not been accepted';
+COMMENT ON COLUMN user_invitations.invitation_revoked_at IS 'NULL means the invitation has not been revoked';
+
+-- Only one pending invitation per email
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_invitations_pending_email ON user_invitations(invitation_email)
+WHERE invitation_accepted_at IS NULL AND invitation_revoked_at IS NULL;
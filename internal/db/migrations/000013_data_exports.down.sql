@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_data_exports_expires_at;
+DROP INDEX IF EXISTS idx_data_exports_user_id;
+
+DROP TABLE IF EXISTS data_exports;
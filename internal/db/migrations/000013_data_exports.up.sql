@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS data_exports (
+  export_id           INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  export_uuid         UUID NOT NULL DEFAULT gen_random_uuid() UNIQUE,
+  user_id             INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+  export_status       VARCHAR(20) NOT NULL DEFAULT 'pending',
+  export_token_hash   VARCHAR(64) DEFAULT NULL UNIQUE,
+  export_archive      BYTEA DEFAULT NULL,
+  export_error        TEXT NOT NULL DEFAULT '',
+  export_expires_at   TIMESTAMPTZ DEFAULT NULL,
+  export_created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  export_completed_at TIMESTAMPTZ DEFAULT NULL
+);
+
+COMMENT ON COLUMN data_exports.export_status IS 'pending, completed or failed';
+COMMENT ON COLUMN data_exports.export_token_hash IS 'SHA-256 hex digest of the download token sent by email';
+COMMENT ON COLUMN data_exports.export_archive IS 'ZIP archive of JSON files, deleted once the export expires';
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports(user_id);
+CREATE INDEX IF NOT EXISTS idx_data_exports_expires_at ON data_exports(export_expires_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_account_closures_purge_at;
+
+DROP TABLE IF EXISTS account_closures;
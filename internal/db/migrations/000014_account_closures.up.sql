@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS account_closures (
+  user_id               INT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+  closure_requested_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  closure_purge_at      TIMESTAMPTZ NOT NULL,
+  closure_anonymized_at TIMESTAMPTZ DEFAULT NULL
+);
+
+COMMENT ON TABLE account_closures IS 'Accounts closed by their owner, signing in before closure_purge_at restores them';
+COMMENT ON COLUMN account_closures.closure_anonymized_at IS 'NULL until the personal data of the account has been anonymized';
+
+CREATE INDEX IF NOT EXISTS idx_account_closures_purge_at ON account_closures(closure_purge_at) WHERE closure_anonymized_at IS NULL;
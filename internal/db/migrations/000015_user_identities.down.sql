@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_user_identities_subject;
+
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS user_identities (
+  user_id             INT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+  identity_provider   VARCHAR(20) NOT NULL,
+  identity_subject    VARCHAR(255) NOT NULL,
+  identity_synced_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  identity_created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE user_identities IS 'Accounts provisioned from an external directory, their password is checked by the directory';
+COMMENT ON COLUMN user_identities.identity_provider IS 'Directory the account comes from, e.g. ldap';
+COMMENT ON COLUMN user_identities.identity_subject IS 'Identifier of the account in the directory, the DN for LDAP';
+COMMENT ON COLUMN user_identities.identity_synced_at IS 'Last login that copied the name and groups from the directory';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_subject ON user_identities(identity_provider, identity_subject);
@@ -0,0 +1,98 @@
+-- name: AnonymizeClosedUser :one
+UPDATE users
+SET
+  user_email      = 'deleted-' || user_uuid::text || '@anonymized.invalid',
+  user_password   = '!',
+  user_fullname   = 'Deleted user',
+  user_age        = NULL,
+  user_status     = 2,
+  user_updated_at = now()
+WHERE
+  user_id = $1
+  AND user_deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: AnonymizeUserAuditLogs :exec
+UPDATE audit_logs
+SET
+  audit_ip_address = '',
+  audit_user_agent = ''
+WHERE audit_actor_id = $1;
+
+-- name: AnonymizeUserSecurityEvents :exec
+UPDATE security_events
+SET
+  security_event_email      = '',
+  security_event_ip_address = '',
+  security_event_user_agent = ''
+WHERE security_event_user_id = $1;
+
+-- name: CreateAccountClosure :one
+INSERT INTO account_closures (
+  user_id,
+  closure_purge_at
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE
+SET
+  closure_requested_at  = now(),
+  closure_purge_at      = EXCLUDED.closure_purge_at,
+  closure_anonymized_at = NULL
+RETURNING *;
+
+-- name: DeleteAccountClosure :exec
+DELETE FROM account_closures
+WHERE user_id = $1;
+
+-- name: DeleteUserApiKeys :exec
+DELETE FROM api_keys
+WHERE api_key_owner_id = $1;
+
+-- name: DeleteUserInvitations :exec
+DELETE FROM user_invitations
+WHERE invitation_email = (
+  SELECT user_email FROM users WHERE user_id = $1
+);
+
+-- name: DeleteUserOAuthConsents :exec
+DELETE FROM oauth_consents
+WHERE user_id = $1;
+
+-- name: DeleteUserPasswordHistory :exec
+DELETE FROM password_history
+WHERE user_id = $1;
+
+-- name: GetClosedUserByEmail :one
+SELECT users.*
+FROM users
+JOIN account_closures ON account_closures.user_id = users.user_id
+WHERE
+  users.user_email = $1
+  AND users.user_deleted_at IS NOT NULL
+  AND account_closures.closure_anonymized_at IS NULL
+  AND account_closures.closure_purge_at > now();
+
+-- name: GetClosedUserByUuid :one
+SELECT users.*
+FROM users
+JOIN account_closures ON account_closures.user_id = users.user_id
+WHERE
+  users.user_uuid = $1
+  AND users.user_deleted_at IS NOT NULL
+  AND account_closures.closure_anonymized_at IS NULL
+  AND account_closures.closure_purge_at > now();
+
+-- name: ListDueAccountClosures :many
+SELECT *
+FROM account_closures
+WHERE
+  closure_anonymized_at IS NULL
+  AND closure_purge_at <= now()
+ORDER BY closure_purge_at ASC
+LIMIT $1;
+
+-- name: MarkAccountClosureAnonymized :exec
+UPDATE account_closures
+SET closure_anonymized_at = now()
+WHERE user_id = $1;
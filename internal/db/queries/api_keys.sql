@@ -0,0 +1,57 @@
+-- name: CreateApiKey :one
+INSERT INTO api_keys (
+  api_key_owner_id,
+  api_key_name,
+  api_key_prefix,
+  api_key_hash,
+  api_key_scopes,
+  api_key_expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetApiKeyByHash :one
+SELECT *
+FROM api_keys
+WHERE api_key_hash = $1;
+
+-- name: GetApiKeyByUuid :one
+SELECT *
+FROM api_keys
+WHERE api_key_uuid = $1;
+
+-- name: GetApiKeyOwnerUuid :one
+SELECT user_uuid
+FROM users
+WHERE user_id = $1;
+
+-- name: ListApiKeys :many
+SELECT *
+FROM api_keys
+ORDER BY api_key_id ASC;
+
+-- name: RevokeApiKey :one
+UPDATE api_keys
+SET
+  api_key_revoked_at = now(),
+  api_key_updated_at = now()
+WHERE
+  api_key_uuid = $1
+  AND api_key_revoked_at IS NULL
+RETURNING *;
+
+-- name: RotateApiKey :one
+UPDATE api_keys
+SET
+  api_key_prefix     = $2,
+  api_key_hash       = $3,
+  api_key_updated_at = now()
+WHERE
+  api_key_uuid = $1
+  AND api_key_revoked_at IS NULL
+RETURNING *;
+
+-- name: TouchApiKeyLastUsed :exec
+UPDATE api_keys
+SET api_key_last_used_at = now()
+WHERE api_key_id = $1;
@@ -0,0 +1,11 @@
+-- name: CreateAuditLog :exec
+INSERT INTO audit_logs (
+  audit_actor_id,
+  audit_target_id,
+  audit_action,
+  audit_ip_address,
+  audit_user_agent,
+  audit_metadata
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+);
@@ -0,0 +1,59 @@
+-- name: CompleteDataExport :one
+UPDATE data_exports
+SET
+  export_status       = 'completed',
+  export_token_hash   = $2,
+  export_archive      = $3,
+  export_expires_at   = $4,
+  export_completed_at = now()
+WHERE export_id = $1
+RETURNING *;
+
+-- name: CreateDataExport :one
+INSERT INTO data_exports (
+  user_id
+) VALUES (
+  $1
+) RETURNING *;
+
+-- name: DeleteExpiredDataExports :execrows
+DELETE FROM data_exports
+WHERE export_expires_at < sqlc.arg(expired_before)::timestamptz;
+
+-- name: FailDataExport :exec
+UPDATE data_exports
+SET
+  export_status       = 'failed',
+  export_error        = $2,
+  export_completed_at = now()
+WHERE export_id = $1;
+
+-- name: GetDataExportByTokenHash :one
+SELECT *
+FROM data_exports
+WHERE export_token_hash = $1;
+
+-- name: GetDataExportByUuid :one
+SELECT *
+FROM data_exports
+WHERE export_uuid = $1;
+
+-- name: ListApiKeysByOwner :many
+SELECT *
+FROM api_keys
+WHERE api_key_owner_id = $1
+ORDER BY api_key_id ASC;
+
+-- name: ListAuditLogsByUser :many
+SELECT *
+FROM audit_logs
+WHERE
+  audit_actor_id = sqlc.arg(user_id)
+  OR audit_target_id = sqlc.arg(user_id)
+ORDER BY audit_log_id ASC;
+
+-- name: ListOAuthConsentsByUser :many
+SELECT *
+FROM oauth_consents
+WHERE user_id = $1
+ORDER BY oauth_client_id ASC;
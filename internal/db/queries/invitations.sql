@@ -0,0 +1,64 @@
+-- name: AcceptInvitation :one
+UPDATE user_invitations
+SET
+  invitation_accepted_at = now(),
+  invitation_updated_at  = now()
+WHERE
+  invitation_id = $1
+  AND invitation_accepted_at IS NULL
+  AND invitation_revoked_at IS NULL
+  AND invitation_expires_at > now()
+RETURNING *;
+
+-- name: CreateInvitation :one
+INSERT INTO user_invitations (
+  invitation_email,
+  invitation_fullname,
+  invitation_level,
+  invitation_token_hash,
+  invitation_invited_by,
+  invitation_expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetInvitationByTokenHash :one
+SELECT *
+FROM user_invitations
+WHERE invitation_token_hash = $1;
+
+-- name: GetInvitationByUuid :one
+SELECT *
+FROM user_invitations
+WHERE invitation_uuid = $1;
+
+-- name: ListPendingInvitations :many
+SELECT *
+FROM user_invitations
+WHERE
+  invitation_accepted_at IS NULL
+  AND invitation_revoked_at IS NULL
+ORDER BY invitation_id ASC;
+
+-- name: RenewInvitation :one
+UPDATE user_invitations
+SET
+  invitation_token_hash = $2,
+  invitation_expires_at = $3,
+  invitation_updated_at = now()
+WHERE
+  invitation_uuid = $1
+  AND invitation_accepted_at IS NULL
+  AND invitation_revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeInvitation :one
+UPDATE user_invitations
+SET
+  invitation_revoked_at = now(),
+  invitation_updated_at = now()
+WHERE
+  invitation_uuid = $1
+  AND invitation_accepted_at IS NULL
+  AND invitation_revoked_at IS NULL
+RETURNING *;
@@ -0,0 +1,56 @@
+-- name: GetUserMfa :one
+SELECT *
+FROM user_mfa
+WHERE user_id = $1;
+
+-- name: UpsertUserMfaSecret :one
+INSERT INTO user_mfa (
+  user_id,
+  mfa_secret
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE
+SET
+  mfa_secret     = EXCLUDED.mfa_secret,
+  mfa_enabled    = FALSE,
+  mfa_enabled_at = NULL,
+  mfa_updated_at = now()
+RETURNING *;
+
+-- name: EnableUserMfa :one
+UPDATE user_mfa
+SET
+  mfa_enabled    = TRUE,
+  mfa_enabled_at = now(),
+  mfa_updated_at = now()
+WHERE user_id = $1
+RETURNING *;
+
+-- name: DeleteUserMfa :exec
+DELETE FROM user_mfa
+WHERE user_id = $1;
+
+-- name: CreateRecoveryCodes :exec
+INSERT INTO user_recovery_codes (user_id, recovery_code_hash)
+SELECT sqlc.arg(user_id)::INT, unnest(sqlc.arg(code_hashes)::TEXT[]);
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM user_recovery_codes
+WHERE user_id = $1;
+
+-- name: UseRecoveryCode :execrows
+UPDATE user_recovery_codes
+SET
+  recovery_code_used_at = now()
+WHERE
+  user_id = $1
+  AND recovery_code_hash = $2
+  AND recovery_code_used_at IS NULL;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*)
+FROM user_recovery_codes
+WHERE
+  user_id = $1
+  AND recovery_code_used_at IS NULL;
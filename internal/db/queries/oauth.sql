@@ -0,0 +1,54 @@
+-- name: CreateOAuthClient :one
+INSERT INTO oauth_clients (
+  client_id,
+  client_secret_hash,
+  client_name,
+  client_redirect_uris,
+  client_grant_types,
+  client_scopes
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetOAuthClientByClientID :one
+SELECT *
+FROM oauth_clients
+WHERE client_id = $1;
+
+-- name: ListOAuthClients :many
+SELECT *
+FROM oauth_clients
+ORDER BY oauth_client_id ASC;
+
+-- name: UpdateOAuthClientSecret :one
+UPDATE oauth_clients
+SET
+  client_secret_hash = $2,
+  client_updated_at  = now()
+WHERE client_id = $1
+RETURNING *;
+
+-- name: DeleteOAuthClient :execrows
+DELETE FROM oauth_clients
+WHERE client_id = $1;
+
+-- name: GetOAuthConsent :one
+SELECT *
+FROM oauth_consents
+WHERE
+  user_id = $1
+  AND oauth_client_id = $2;
+
+-- name: UpsertOAuthConsent :one
+INSERT INTO oauth_consents (
+  user_id,
+  oauth_client_id,
+  consent_scopes
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (user_id, oauth_client_id) DO UPDATE
+SET
+  consent_scopes     = EXCLUDED.consent_scopes,
+  consent_updated_at = now()
+RETURNING *;
@@ -0,0 +1,26 @@
+-- name: CreatePasswordHistory :exec
+INSERT INTO password_history (
+  user_id,
+  password_hash
+) VALUES (
+  $1, $2
+);
+
+-- name: ListPasswordHistoryHashes :many
+SELECT password_hash
+FROM password_history
+WHERE user_id = $1
+ORDER BY password_history_created_at DESC, password_history_id DESC
+LIMIT $2;
+
+-- name: PrunePasswordHistory :exec
+DELETE FROM password_history
+WHERE
+  user_id = sqlc.arg(user_id)
+  AND password_history_id NOT IN (
+    SELECT password_history_id
+    FROM password_history
+    WHERE user_id = sqlc.arg(user_id)
+    ORDER BY password_history_created_at DESC, password_history_id DESC
+    LIMIT sqlc.arg(keep)
+  );
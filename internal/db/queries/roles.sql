@@ -0,0 +1,85 @@
+-- name: ListRoles :many
+SELECT *
+FROM roles
+ORDER BY role_id ASC;
+
+-- name: GetRoleByID :one
+SELECT *
+FROM roles
+WHERE role_id = $1;
+
+-- name: CreateRole :one
+INSERT INTO roles (
+  role_code,
+  role_name,
+  role_description
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: UpdateRole :one
+UPDATE roles
+SET
+  role_name        = COALESCE(sqlc.narg(role_name), role_name),
+  role_description = COALESCE(sqlc.narg(role_description), role_description),
+  role_updated_at  = now()
+WHERE
+  role_id = sqlc.arg(role_id)
+RETURNING *;
+
+-- name: DeleteRole :one
+DELETE FROM roles
+WHERE
+  role_id = $1
+  AND role_is_system = FALSE
+RETURNING *;
+
+-- name: ListPermissions :many
+SELECT *
+FROM permissions
+ORDER BY permission_code ASC;
+
+-- name: GetPermissionsByRoleID :many
+SELECT p.*
+FROM permissions p
+JOIN role_permissions rp ON rp.permission_id = p.permission_id
+WHERE rp.role_id = $1
+ORDER BY p.permission_code ASC;
+
+-- name: GetPermissionCodesByUserID :many
+SELECT DISTINCT p.permission_code
+FROM permissions p
+JOIN role_permissions rp ON rp.permission_id = p.permission_id
+WHERE rp.role_id IN (
+  SELECT u.user_level FROM users u WHERE u.user_id = sqlc.arg(user_id)
+  UNION
+  SELECT ur.role_id FROM user_roles ur WHERE ur.user_id = sqlc.arg(user_id)
+)
+ORDER BY p.permission_code ASC;
+
+-- name: DeleteRolePermissions :exec
+DELETE FROM role_permissions
+WHERE role_id = $1;
+
+-- name: AddRolePermissions :execrows
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT sqlc.arg(role_id)::INT, permission_id
+FROM permissions
+WHERE permission_code = ANY(sqlc.arg(permission_codes)::TEXT[])
+ON CONFLICT DO NOTHING;
+
+-- name: GetRolesByUserID :many
+SELECT r.*
+FROM roles r
+JOIN user_roles ur ON ur.role_id = r.role_id
+WHERE ur.user_id = $1
+ORDER BY r.role_id ASC;
+
+-- name: AssignUserRole :exec
+INSERT INTO user_roles (user_id, role_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveUserRole :execrows
+DELETE FROM user_roles
+WHERE user_id = $1 AND role_id = $2;
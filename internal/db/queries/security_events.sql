@@ -0,0 +1,41 @@
+-- name: CountSecurityEvents :one
+SELECT COUNT(*)
+FROM security_events
+WHERE
+  (sqlc.narg(user_id)::INT IS NULL OR security_event_user_id = sqlc.narg(user_id))
+  AND (sqlc.narg(event_type)::TEXT IS NULL OR security_event_type = sqlc.narg(event_type))
+  AND (sqlc.narg(outcome)::TEXT IS NULL OR security_event_outcome = sqlc.narg(outcome))
+  AND (sqlc.narg(ip_address)::TEXT IS NULL OR security_event_ip_address = sqlc.narg(ip_address))
+  AND (sqlc.narg(created_from)::TIMESTAMPTZ IS NULL OR security_event_created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::TIMESTAMPTZ IS NULL OR security_event_created_at < sqlc.narg(created_to));
+
+-- name: CreateSecurityEvent :exec
+INSERT INTO security_events (
+  security_event_user_id,
+  security_event_email,
+  security_event_type,
+  security_event_outcome,
+  security_event_reason,
+  security_event_ip_address,
+  security_event_user_agent,
+  security_event_trace_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+);
+
+-- name: DeleteSecurityEventsBefore :execrows
+DELETE FROM security_events
+WHERE security_event_created_at < $1;
+
+-- name: ListSecurityEvents :many
+SELECT *
+FROM security_events
+WHERE
+  (sqlc.narg(user_id)::INT IS NULL OR security_event_user_id = sqlc.narg(user_id))
+  AND (sqlc.narg(event_type)::TEXT IS NULL OR security_event_type = sqlc.narg(event_type))
+  AND (sqlc.narg(outcome)::TEXT IS NULL OR security_event_outcome = sqlc.narg(outcome))
+  AND (sqlc.narg(ip_address)::TEXT IS NULL OR security_event_ip_address = sqlc.narg(ip_address))
+  AND (sqlc.narg(created_from)::TIMESTAMPTZ IS NULL OR security_event_created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::TIMESTAMPTZ IS NULL OR security_event_created_at < sqlc.narg(created_to))
+ORDER BY security_event_created_at DESC, security_event_id DESC
+LIMIT $1 OFFSET $2;
@@ -0,0 +1,43 @@
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (
+  user_id,
+  identity_provider,
+  identity_subject
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: DeleteUserIdentity :exec
+DELETE FROM user_identities
+WHERE user_id = $1;
+
+-- name: GetUserByIdentity :one
+-- Deleted accounts are returned too, the caller restores closed ones and refuses the others
+SELECT users.*
+FROM users
+JOIN user_identities ON user_identities.user_id = users.user_id
+WHERE
+  user_identities.identity_provider = $1
+  AND user_identities.identity_subject = $2;
+
+-- name: GetUserIdentity :one
+SELECT *
+FROM user_identities
+WHERE user_id = $1;
+
+-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET
+  identity_synced_at = now()
+WHERE user_id = $1;
+
+-- name: UpdateIdentityUser :one
+UPDATE users
+SET
+  user_fullname   = $2,
+  user_level      = $3,
+  user_updated_at = now()
+WHERE
+  user_id = $1
+  AND user_deleted_at IS NULL
+RETURNING *;
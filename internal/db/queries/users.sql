@@ -83,6 +83,7 @@ WHERE
 UPDATE users
 SET
   user_password = COALESCE(sqlc.narg(user_password), user_password),
+  user_password_changed_at = CASE WHEN sqlc.narg(user_password)::TEXT IS NULL THEN user_password_changed_at ELSE now() END,
   user_fullname = COALESCE(sqlc.narg(user_fullname), user_fullname),
   user_age      = COALESCE(sqlc.narg(user_age), user_age),
   user_status   = COALESCE(sqlc.narg(user_status), user_status),
@@ -129,10 +130,67 @@ WHERE
   AND user_deleted_at IS NOT NULL
 RETURNING *;
 
+-- name: DeletePendingUser :exec
+-- Removes a registration whose verification email could not be sent, verified accounts are never matched
+DELETE FROM users
+WHERE
+  user_uuid = sqlc.arg(user_uuid)::uuid
+  AND user_status = 4;
+
+-- name: ReplacePendingUser :one
+-- Registering again before the email is verified starts over with the new password and name
+UPDATE users
+SET
+  user_password   = sqlc.arg(user_password),
+  user_fullname   = sqlc.arg(user_fullname),
+  user_age        = sqlc.narg(user_age),
+  user_updated_at = now()
+WHERE
+  user_email = sqlc.arg(user_email)
+  AND user_status = 4
+  AND user_deleted_at IS NULL
+RETURNING *;
+
 -- name: UpdatePassword :one
+-- Accounts in their closure grace period can still change an expired password while they are restored
+UPDATE users
+SET
+  user_password            = sqlc.arg(user_password),
+  user_password_changed_at = now()
+WHERE
+  user_uuid = sqlc.arg(user_uuid)::uuid
+  AND (
+    user_deleted_at IS NULL
+    OR EXISTS (
+      SELECT 1 FROM account_closures
+      WHERE account_closures.user_id = users.user_id
+        AND account_closures.closure_anonymized_at IS NULL
+        AND account_closures.closure_purge_at > now()
+    )
+  )
+RETURNING *;
+
+-- name: VerifyUserEmail :one
+UPDATE users
+SET
+  user_status = 1
+WHERE
+  user_uuid = sqlc.arg(user_uuid)::uuid
+  AND user_status = 4
+  AND user_deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdatePasswordHash :exec
 UPDATE users
 SET
   user_password = sqlc.arg(user_password)
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: UpdateUserEmail :one
+UPDATE users
+SET
+  user_email      = sqlc.arg(user_email),
+  user_updated_at = now()
 WHERE
   user_uuid = sqlc.arg(user_uuid)::uuid
   AND user_deleted_at IS NULL
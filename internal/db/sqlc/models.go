@@ -11,20 +11,211 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// Accounts closed by their owner, signing in before closure_purge_at restores them
+type AccountClosure struct {
+	UserID             int32     `json:"user_id"`
+	ClosureRequestedAt time.Time `json:"closure_requested_at"`
+	ClosurePurgeAt     time.Time `json:"closure_purge_at"`
+	// NULL until the personal data of the account has been anonymized
+	ClosureAnonymizedAt pgtype.Timestamptz `json:"closure_anonymized_at"`
+}
+
+type ApiKey struct {
+	ApiKeyID      int32     `json:"api_key_id"`
+	ApiKeyUuid    uuid.UUID `json:"api_key_uuid"`
+	ApiKeyOwnerID int32     `json:"api_key_owner_id"`
+	ApiKeyName    string    `json:"api_key_name"`
+	// First characters of the key, shown to identify it
+	ApiKeyPrefix string `json:"api_key_prefix"`
+	// SHA-256 hex digest of the key, the key itself is only shown once
+	ApiKeyHash   string   `json:"api_key_hash"`
+	ApiKeyScopes []string `json:"api_key_scopes"`
+	// NULL means the key does not expire
+	ApiKeyExpiresAt  pgtype.Timestamptz `json:"api_key_expires_at"`
+	ApiKeyLastUsedAt pgtype.Timestamptz `json:"api_key_last_used_at"`
+	// NULL means the key is active
+	ApiKeyRevokedAt pgtype.Timestamptz `json:"api_key_revoked_at"`
+	ApiKeyCreatedAt time.Time          `json:"api_key_created_at"`
+	ApiKeyUpdatedAt time.Time          `json:"api_key_updated_at"`
+}
+
+type AuditLog struct {
+	AuditLogID int32 `json:"audit_log_id"`
+	// User who performed the action
+	AuditActorID *int32 `json:"audit_actor_id"`
+	// User the action was performed on
+	AuditTargetID *int32 `json:"audit_target_id"`
+	// Action name, e.g. impersonation.start, impersonation.stop
+	AuditAction    string    `json:"audit_action"`
+	AuditIpAddress string    `json:"audit_ip_address"`
+	AuditUserAgent string    `json:"audit_user_agent"`
+	AuditMetadata  []byte    `json:"audit_metadata"`
+	AuditCreatedAt time.Time `json:"audit_created_at"`
+}
+
+type DataExport struct {
+	ExportID   int32     `json:"export_id"`
+	ExportUuid uuid.UUID `json:"export_uuid"`
+	UserID     int32     `json:"user_id"`
+	// pending, completed or failed
+	ExportStatus string `json:"export_status"`
+	// SHA-256 hex digest of the download token sent by email
+	ExportTokenHash *string `json:"export_token_hash"`
+	// ZIP archive of JSON files, deleted once the export expires
+	ExportArchive     []byte             `json:"export_archive"`
+	ExportError       string             `json:"export_error"`
+	ExportExpiresAt   pgtype.Timestamptz `json:"export_expires_at"`
+	ExportCreatedAt   time.Time          `json:"export_created_at"`
+	ExportCompletedAt pgtype.Timestamptz `json:"export_completed_at"`
+}
+
+type OauthClient struct {
+	OauthClientID int32  `json:"oauth_client_id"`
+	ClientID      string `json:"client_id"`
+	// Bcrypt hash of the client secret, NULL for public clients that must use PKCE
+	ClientSecretHash   *string  `json:"client_secret_hash"`
+	ClientName         string   `json:"client_name"`
+	ClientRedirectUris []string `json:"client_redirect_uris"`
+	// Allowed grants: authorization_code, refresh_token, client_credentials
+	ClientGrantTypes []string `json:"client_grant_types"`
+	// Permission codes the client may request
+	ClientScopes    []string  `json:"client_scopes"`
+	ClientCreatedAt time.Time `json:"client_created_at"`
+	ClientUpdatedAt time.Time `json:"client_updated_at"`
+}
+
+// Scopes a user has approved for an OAuth client
+type OauthConsent struct {
+	UserID           int32     `json:"user_id"`
+	OauthClientID    int32     `json:"oauth_client_id"`
+	ConsentScopes    []string  `json:"consent_scopes"`
+	ConsentCreatedAt time.Time `json:"consent_created_at"`
+	ConsentUpdatedAt time.Time `json:"consent_updated_at"`
+}
+
+type PasswordHistory struct {
+	PasswordHistoryID int32 `json:"password_history_id"`
+	UserID            int32 `json:"user_id"`
+	// Argon2id or bcrypt hash of a password the user had before
+	PasswordHash             string    `json:"password_hash"`
+	PasswordHistoryCreatedAt time.Time `json:"password_history_created_at"`
+}
+
+type Permission struct {
+	PermissionID          int32   `json:"permission_id"`
+	PermissionCode        string  `json:"permission_code"`
+	PermissionDescription *string `json:"permission_description"`
+}
+
+type Role struct {
+	RoleID          int32   `json:"role_id"`
+	RoleCode        string  `json:"role_code"`
+	RoleName        string  `json:"role_name"`
+	RoleDescription *string `json:"role_description"`
+	// System roles are seeded by migrations and cannot be deleted
+	RoleIsSystem  bool      `json:"role_is_system"`
+	RoleCreatedAt time.Time `json:"role_created_at"`
+	RoleUpdatedAt time.Time `json:"role_updated_at"`
+}
+
+type RolePermission struct {
+	RoleID       int32 `json:"role_id"`
+	PermissionID int32 `json:"permission_id"`
+}
+
+type SecurityEvent struct {
+	SecurityEventID int64 `json:"security_event_id"`
+	// NULL when the email did not match an account
+	SecurityEventUserID *int32 `json:"security_event_user_id"`
+	// Email that was submitted, kept for failed logins on unknown accounts
+	SecurityEventEmail string `json:"security_event_email"`
+	// Event name, e.g. login, token_refresh, logout, password_reset, account_locked
+	SecurityEventType string `json:"security_event_type"`
+	// success or failure
+	SecurityEventOutcome   string    `json:"security_event_outcome"`
+	SecurityEventReason    string    `json:"security_event_reason"`
+	SecurityEventIpAddress string    `json:"security_event_ip_address"`
+	SecurityEventUserAgent string    `json:"security_event_user_agent"`
+	SecurityEventTraceID   string    `json:"security_event_trace_id"`
+	SecurityEventCreatedAt time.Time `json:"security_event_created_at"`
+}
+
 type User struct {
-	UserID       int32     `json:"user_id"`
-	UserUuid     uuid.UUID `json:"user_uuid"`
-	UserEmail    string    `json:"user_email"`
-	UserPassword string    `json:"user_password"`
-	UserFullname string    `json:"user_fullname"`
+	UserID    int32     `json:"user_id"`
+	UserUuid  uuid.UUID `json:"user_uuid"`
+	UserEmail string    `json:"user_email"`
+	// PHC formatted argon2id hash, bcrypt hashes are upgraded on the next login
+	UserPassword string `json:"user_password"`
+	UserFullname string `json:"user_fullname"`
 	// User age, must be between 1 and 150
 	UserAge *int32 `json:"user_age"`
-	// User status: 1 - Active, 2 - Inactive, 3 - Banned
+	// User status: 1 - Active, 2 - Inactive, 3 - Banned, 4 - Pending verification
 	UserStatus int32 `json:"user_status"`
-	// User status: 1 - Administrator, 2 - Moderator, 3 - Member
+	// Primary role of the user, references roles.role_id
 	UserLevel     int32     `json:"user_level"`
 	UserCreatedAt time.Time `json:"user_created_at"`
 	UserUpdatedAt time.Time `json:"user_updated_at"`
 	// Sorf delete timestamp: NULL means not deleted
 	UserDeletedAt pgtype.Timestamptz `json:"user_deleted_at"`
+	// Last time the password was set, used for the expiry policy
+	UserPasswordChangedAt time.Time `json:"user_password_changed_at"`
+}
+
+// Accounts provisioned from an external directory, their password is checked by the directory
+type UserIdentity struct {
+	UserID int32 `json:"user_id"`
+	// Directory the account comes from, e.g. ldap
+	IdentityProvider string `json:"identity_provider"`
+	// Identifier of the account in the directory, the DN for LDAP
+	IdentitySubject string `json:"identity_subject"`
+	// Last login that copied the name and groups from the directory
+	IdentitySyncedAt  time.Time `json:"identity_synced_at"`
+	IdentityCreatedAt time.Time `json:"identity_created_at"`
+}
+
+type UserInvitation struct {
+	InvitationID       int32     `json:"invitation_id"`
+	InvitationUuid     uuid.UUID `json:"invitation_uuid"`
+	InvitationEmail    string    `json:"invitation_email"`
+	InvitationFullname string    `json:"invitation_fullname"`
+	// Primary role given to the account, references roles.role_id
+	InvitationLevel int32 `json:"invitation_level"`
+	// SHA-256 hex digest of the emailed token, rotated on resend
+	InvitationTokenHash string `json:"invitation_token_hash"`
+	// Administrator who sent the invitation, NULL once they are deleted
+	InvitationInvitedBy *int32    `json:"invitation_invited_by"`
+	InvitationExpiresAt time.Time `json:"invitation_expires_at"`
+	// NULL means the invitation has not been accepted
+	InvitationAcceptedAt pgtype.Timestamptz `json:"invitation_accepted_at"`
+	// NULL means the invitation has not been revoked
+	InvitationRevokedAt pgtype.Timestamptz `json:"invitation_revoked_at"`
+	InvitationCreatedAt time.Time          `json:"invitation_created_at"`
+	InvitationUpdatedAt time.Time          `json:"invitation_updated_at"`
+}
+
+type UserMfa struct {
+	UserID int32 `json:"user_id"`
+	// TOTP secret encrypted with AES-GCM
+	MfaSecret string `json:"mfa_secret"`
+	// FALSE while the enrollment is pending confirmation
+	MfaEnabled   bool               `json:"mfa_enabled"`
+	MfaEnabledAt pgtype.Timestamptz `json:"mfa_enabled_at"`
+	MfaCreatedAt time.Time          `json:"mfa_created_at"`
+	MfaUpdatedAt time.Time          `json:"mfa_updated_at"`
+}
+
+type UserRecoveryCode struct {
+	RecoveryCodeID int32 `json:"recovery_code_id"`
+	UserID         int32 `json:"user_id"`
+	// SHA-256 hex digest of the recovery code
+	RecoveryCodeHash      string             `json:"recovery_code_hash"`
+	RecoveryCodeUsedAt    pgtype.Timestamptz `json:"recovery_code_used_at"`
+	RecoveryCodeCreatedAt time.Time          `json:"recovery_code_created_at"`
+}
+
+// Additional roles granted to a user on top of users.user_level
+type UserRole struct {
+	UserID            int32     `json:"user_id"`
+	RoleID            int32     `json:"role_id"`
+	UserRoleCreatedAt time.Time `json:"user_role_created_at"`
 }
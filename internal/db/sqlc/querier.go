@@ -6,24 +6,113 @@ package sqlc
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AcceptInvitation(ctx context.Context, invitationID int32) (UserInvitation, error)
+	AddRolePermissions(ctx context.Context, arg AddRolePermissionsParams) (int64, error)
+	AnonymizeClosedUser(ctx context.Context, userID int32) (User, error)
+	AnonymizeUserAuditLogs(ctx context.Context, auditActorID *int32) error
+	AnonymizeUserSecurityEvents(ctx context.Context, securityEventUserID *int32) error
+	AssignUserRole(ctx context.Context, arg AssignUserRoleParams) error
+	CompleteDataExport(ctx context.Context, arg CompleteDataExportParams) (DataExport, error)
+	CountSecurityEvents(ctx context.Context, arg CountSecurityEventsParams) (int64, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID int32) (int64, error)
 	CountUsers(ctx context.Context, arg CountUsersParams) (int64, error)
+	CreateAccountClosure(ctx context.Context, arg CreateAccountClosureParams) (AccountClosure, error)
+	CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (ApiKey, error)
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) error
+	CreateDataExport(ctx context.Context, userID int32) (DataExport, error)
+	CreateInvitation(ctx context.Context, arg CreateInvitationParams) (UserInvitation, error)
+	CreateOAuthClient(ctx context.Context, arg CreateOAuthClientParams) (OauthClient, error)
+	CreatePasswordHistory(ctx context.Context, arg CreatePasswordHistoryParams) error
+	CreateRecoveryCodes(ctx context.Context, arg CreateRecoveryCodesParams) error
+	CreateRole(ctx context.Context, arg CreateRoleParams) (Role, error)
+	CreateSecurityEvent(ctx context.Context, arg CreateSecurityEventParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
+	DeleteAccountClosure(ctx context.Context, userID int32) error
+	DeleteExpiredDataExports(ctx context.Context, expiredBefore time.Time) (int64, error)
+	DeleteOAuthClient(ctx context.Context, clientID string) (int64, error)
+	// Removes a registration whose verification email could not be sent, verified accounts are never matched
+	DeletePendingUser(ctx context.Context, userUuid uuid.UUID) error
+	DeleteRecoveryCodes(ctx context.Context, userID int32) error
+	DeleteRole(ctx context.Context, roleID int32) (Role, error)
+	DeleteRolePermissions(ctx context.Context, roleID int32) error
+	DeleteSecurityEventsBefore(ctx context.Context, securityEventCreatedAt time.Time) (int64, error)
+	DeleteUserApiKeys(ctx context.Context, apiKeyOwnerID int32) error
+	DeleteUserIdentity(ctx context.Context, userID int32) error
+	DeleteUserInvitations(ctx context.Context, userID int32) error
+	DeleteUserMfa(ctx context.Context, userID int32) error
+	DeleteUserOAuthConsents(ctx context.Context, userID int32) error
+	DeleteUserPasswordHistory(ctx context.Context, userID int32) error
+	EnableUserMfa(ctx context.Context, userID int32) (UserMfa, error)
+	FailDataExport(ctx context.Context, arg FailDataExportParams) error
 	GetAllUsersUserCraetedAtAsc(ctx context.Context, arg GetAllUsersUserCraetedAtAscParams) ([]User, error)
 	GetAllUsersUserCreatedAtDesc(ctx context.Context, arg GetAllUsersUserCreatedAtDescParams) ([]User, error)
 	GetAllUsersUserIdAsc(ctx context.Context, arg GetAllUsersUserIdAscParams) ([]User, error)
 	GetAllUsersUserIdDesc(ctx context.Context, arg GetAllUsersUserIdDescParams) ([]User, error)
+	GetApiKeyByHash(ctx context.Context, apiKeyHash string) (ApiKey, error)
+	GetApiKeyByUuid(ctx context.Context, apiKeyUuid uuid.UUID) (ApiKey, error)
+	GetApiKeyOwnerUuid(ctx context.Context, userID int32) (uuid.UUID, error)
+	GetClosedUserByEmail(ctx context.Context, userEmail string) (User, error)
+	GetClosedUserByUuid(ctx context.Context, userUuid uuid.UUID) (User, error)
+	GetDataExportByTokenHash(ctx context.Context, exportTokenHash *string) (DataExport, error)
+	GetDataExportByUuid(ctx context.Context, exportUuid uuid.UUID) (DataExport, error)
+	GetInvitationByTokenHash(ctx context.Context, invitationTokenHash string) (UserInvitation, error)
+	GetInvitationByUuid(ctx context.Context, invitationUuid uuid.UUID) (UserInvitation, error)
+	GetOAuthClientByClientID(ctx context.Context, clientID string) (OauthClient, error)
+	GetOAuthConsent(ctx context.Context, arg GetOAuthConsentParams) (OauthConsent, error)
+	GetPermissionCodesByUserID(ctx context.Context, userID int32) ([]string, error)
+	GetPermissionsByRoleID(ctx context.Context, roleID int32) ([]Permission, error)
+	GetRoleByID(ctx context.Context, roleID int32) (Role, error)
+	GetRolesByUserID(ctx context.Context, userID int32) ([]Role, error)
 	GetUserByEmail(ctx context.Context, userEmail string) (User, error)
+	// Deleted accounts are returned too, the caller restores closed ones and refuses the others
+	GetUserByIdentity(ctx context.Context, arg GetUserByIdentityParams) (User, error)
 	GetUserByUuid(ctx context.Context, userUuid uuid.UUID) (User, error)
+	GetUserIdentity(ctx context.Context, userID int32) (UserIdentity, error)
+	GetUserMfa(ctx context.Context, userID int32) (UserMfa, error)
+	ListApiKeys(ctx context.Context) ([]ApiKey, error)
+	ListApiKeysByOwner(ctx context.Context, apiKeyOwnerID int32) ([]ApiKey, error)
+	ListAuditLogsByUser(ctx context.Context, userID *int32) ([]AuditLog, error)
+	ListDueAccountClosures(ctx context.Context, limit int32) ([]AccountClosure, error)
+	ListOAuthClients(ctx context.Context) ([]OauthClient, error)
+	ListOAuthConsentsByUser(ctx context.Context, userID int32) ([]OauthConsent, error)
+	ListPasswordHistoryHashes(ctx context.Context, arg ListPasswordHistoryHashesParams) ([]string, error)
+	ListPendingInvitations(ctx context.Context) ([]UserInvitation, error)
+	ListPermissions(ctx context.Context) ([]Permission, error)
+	ListRoles(ctx context.Context) ([]Role, error)
+	ListSecurityEvents(ctx context.Context, arg ListSecurityEventsParams) ([]SecurityEvent, error)
+	MarkAccountClosureAnonymized(ctx context.Context, userID int32) error
+	PrunePasswordHistory(ctx context.Context, arg PrunePasswordHistoryParams) error
+	RemoveUserRole(ctx context.Context, arg RemoveUserRoleParams) (int64, error)
+	RenewInvitation(ctx context.Context, arg RenewInvitationParams) (UserInvitation, error)
+	// Registering again before the email is verified starts over with the new password and name
+	ReplacePendingUser(ctx context.Context, arg ReplacePendingUserParams) (User, error)
 	RestoreUser(ctx context.Context, userUuid uuid.UUID) (User, error)
+	RevokeApiKey(ctx context.Context, apiKeyUuid uuid.UUID) (ApiKey, error)
+	RevokeInvitation(ctx context.Context, invitationUuid uuid.UUID) (UserInvitation, error)
+	RotateApiKey(ctx context.Context, arg RotateApiKeyParams) (ApiKey, error)
 	SoftDeleteUser(ctx context.Context, userUuid uuid.UUID) (User, error)
+	TouchApiKeyLastUsed(ctx context.Context, apiKeyID int32) error
+	TouchUserIdentity(ctx context.Context, userID int32) error
 	TrashUser(ctx context.Context, userUuid uuid.UUID) (User, error)
+	UpdateIdentityUser(ctx context.Context, arg UpdateIdentityUserParams) (User, error)
+	UpdateOAuthClientSecret(ctx context.Context, arg UpdateOAuthClientSecretParams) (OauthClient, error)
+	// Accounts in their closure grace period can still change an expired password while they are restored
 	UpdatePassword(ctx context.Context, arg UpdatePasswordParams) (User, error)
+	UpdatePasswordHash(ctx context.Context, arg UpdatePasswordHashParams) error
+	UpdateRole(ctx context.Context, arg UpdateRoleParams) (Role, error)
 	UpdateUserByUuid(ctx context.Context, arg UpdateUserByUuidParams) (User, error)
+	UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error)
+	UpsertOAuthConsent(ctx context.Context, arg UpsertOAuthConsentParams) (OauthConsent, error)
+	UpsertUserMfaSecret(ctx context.Context, arg UpsertUserMfaSecretParams) (UserMfa, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
+	VerifyUserEmail(ctx context.Context, userUuid uuid.UUID) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -48,7 +48,7 @@ INSERT INTO users (
   user_level
 ) VALUES (
   $1, $2, $3, $4, $5, $6
-) RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at
+) RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
 `
 
 type CreateUserParams struct {
@@ -82,12 +82,26 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.UserCreatedAt,
 		&i.UserUpdatedAt,
 		&i.UserDeletedAt,
+		&i.UserPasswordChangedAt,
 	)
 	return i, err
 }
 
+const deletePendingUser = `-- name: DeletePendingUser :exec
+DELETE FROM users
+WHERE
+  user_uuid = $1::uuid
+  AND user_status = 4
+`
+
+// Removes a registration whose verification email could not be sent, verified accounts are never matched
+func (q *Queries) DeletePendingUser(ctx context.Context, userUuid uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deletePendingUser, userUuid)
+	return err
+}
+
 const getAllUsersUserCraetedAtAsc = `-- name: GetAllUsersUserCraetedAtAsc :many
-SELECT user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at
+SELECT user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
 FROM users
 WHERE user_deleted_at IS NULL
 AND (
@@ -127,6 +141,7 @@ func (q *Queries) GetAllUsersUserCraetedAtAsc(ctx context.Context, arg GetAllUse
 			&i.UserCreatedAt,
 			&i.UserUpdatedAt,
 			&i.UserDeletedAt,
+			&i.UserPasswordChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -139,7 +154,7 @@ func (q *Queries) GetAllUsersUserCraetedAtAsc(ctx context.Context, arg GetAllUse
 }
 
 const getAllUsersUserCreatedAtDesc = `-- name: GetAllUsersUserCreatedAtDesc :many
-SELECT user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at
+SELECT user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
 FROM users
 WHERE user_deleted_at IS NULL
 AND (
@@ -179,6 +194,7 @@ func (q *Queries) GetAllUsersUserCreatedAtDesc(ctx context.Context, arg GetAllUs
 			&i.UserCreatedAt,
 			&i.UserUpdatedAt,
 			&i.UserDeletedAt,
+			&i.UserPasswordChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -191,7 +207,7 @@ func (q *Queries) GetAllUsersUserCreatedAtDesc(ctx context.Context, arg GetAllUs
 }
 
 const getAllUsersUserIdAsc = `-- name: GetAllUsersUserIdAsc :many
-SELECT user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at
+SELECT user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
 FROM users
 WHERE user_deleted_at IS NULL
 AND (
@@ -231,6 +247,7 @@ func (q *Queries) GetAllUsersUserIdAsc(ctx context.Context, arg GetAllUsersUserI
 			&i.UserCreatedAt,
 			&i.UserUpdatedAt,
 			&i.UserDeletedAt,
+			&i.UserPasswordChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -243,7 +260,7 @@ func (q *Queries) GetAllUsersUserIdAsc(ctx context.Context, arg GetAllUsersUserI
 }
 
 const getAllUsersUserIdDesc = `-- name: GetAllUsersUserIdDesc :many
-SELECT user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at
+SELECT user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
 FROM users
 WHERE user_deleted_at IS NULL
 AND (
@@ -283,6 +300,7 @@ func (q *Queries) GetAllUsersUserIdDesc(ctx context.Context, arg GetAllUsersUser
 			&i.UserCreatedAt,
 			&i.UserUpdatedAt,
 			&i.UserDeletedAt,
+			&i.UserPasswordChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -295,7 +313,7 @@ func (q *Queries) GetAllUsersUserIdDesc(ctx context.Context, arg GetAllUsersUser
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at
+SELECT user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
 FROM users
 WHERE
   user_email = $1
@@ -317,12 +335,13 @@ func (q *Queries) GetUserByEmail(ctx context.Context, userEmail string) (User, e
 		&i.UserCreatedAt,
 		&i.UserUpdatedAt,
 		&i.UserDeletedAt,
+		&i.UserPasswordChangedAt,
 	)
 	return i, err
 }
 
 const getUserByUuid = `-- name: GetUserByUuid :one
-SELECT user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at
+SELECT user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
 FROM users
 WHERE
   user_uuid = $1
@@ -344,6 +363,54 @@ func (q *Queries) GetUserByUuid(ctx context.Context, userUuid uuid.UUID) (User,
 		&i.UserCreatedAt,
 		&i.UserUpdatedAt,
 		&i.UserDeletedAt,
+		&i.UserPasswordChangedAt,
+	)
+	return i, err
+}
+
+const replacePendingUser = `-- name: ReplacePendingUser :one
+UPDATE users
+SET
+  user_password   = $1,
+  user_fullname   = $2,
+  user_age        = $3,
+  user_updated_at = now()
+WHERE
+  user_email = $4
+  AND user_status = 4
+  AND user_deleted_at IS NULL
+RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
+`
+
+type ReplacePendingUserParams struct {
+	UserPassword string `json:"user_password"`
+	UserFullname string `json:"user_fullname"`
+	UserAge      *int32 `json:"user_age"`
+	UserEmail    string `json:"user_email"`
+}
+
+// Registering again before the email is verified starts over with the new password and name
+func (q *Queries) ReplacePendingUser(ctx context.Context, arg ReplacePendingUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, replacePendingUser,
+		arg.UserPassword,
+		arg.UserFullname,
+		arg.UserAge,
+		arg.UserEmail,
+	)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.UserUuid,
+		&i.UserEmail,
+		&i.UserPassword,
+		&i.UserFullname,
+		&i.UserAge,
+		&i.UserStatus,
+		&i.UserLevel,
+		&i.UserCreatedAt,
+		&i.UserUpdatedAt,
+		&i.UserDeletedAt,
+		&i.UserPasswordChangedAt,
 	)
 	return i, err
 }
@@ -355,7 +422,7 @@ SET
 WHERE
   user_uuid = $1::uuid
   AND user_deleted_at IS NOT NULL
-RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at
+RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
 `
 
 func (q *Queries) RestoreUser(ctx context.Context, userUuid uuid.UUID) (User, error) {
@@ -373,6 +440,7 @@ func (q *Queries) RestoreUser(ctx context.Context, userUuid uuid.UUID) (User, er
 		&i.UserCreatedAt,
 		&i.UserUpdatedAt,
 		&i.UserDeletedAt,
+		&i.UserPasswordChangedAt,
 	)
 	return i, err
 }
@@ -384,7 +452,7 @@ SET
 WHERE
   user_uuid = $1::uuid
   AND user_deleted_at IS NULL
-RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at
+RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
 `
 
 func (q *Queries) SoftDeleteUser(ctx context.Context, userUuid uuid.UUID) (User, error) {
@@ -402,6 +470,7 @@ func (q *Queries) SoftDeleteUser(ctx context.Context, userUuid uuid.UUID) (User,
 		&i.UserCreatedAt,
 		&i.UserUpdatedAt,
 		&i.UserDeletedAt,
+		&i.UserPasswordChangedAt,
 	)
 	return i, err
 }
@@ -411,7 +480,7 @@ DELETE FROM users
 WHERE
   user_uuid = $1::uuid
   AND user_deleted_at IS NOT NULL
-RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at
+RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
 `
 
 func (q *Queries) TrashUser(ctx context.Context, userUuid uuid.UUID) (User, error) {
@@ -429,6 +498,7 @@ func (q *Queries) TrashUser(ctx context.Context, userUuid uuid.UUID) (User, erro
 		&i.UserCreatedAt,
 		&i.UserUpdatedAt,
 		&i.UserDeletedAt,
+		&i.UserPasswordChangedAt,
 	)
 	return i, err
 }
@@ -436,11 +506,20 @@ func (q *Queries) TrashUser(ctx context.Context, userUuid uuid.UUID) (User, erro
 const updatePassword = `-- name: UpdatePassword :one
 UPDATE users
 SET
-  user_password = $1
+  user_password            = $1,
+  user_password_changed_at = now()
 WHERE
   user_uuid = $2::uuid
-  AND user_deleted_at IS NULL
-RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at
+  AND (
+    user_deleted_at IS NULL
+    OR EXISTS (
+      SELECT 1 FROM account_closures
+      WHERE account_closures.user_id = users.user_id
+        AND account_closures.closure_anonymized_at IS NULL
+        AND account_closures.closure_purge_at > now()
+    )
+  )
+RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
 `
 
 type UpdatePasswordParams struct {
@@ -448,6 +527,7 @@ type UpdatePasswordParams struct {
 	UserUuid     uuid.UUID `json:"user_uuid"`
 }
 
+// Accounts in their closure grace period can still change an expired password while they are restored
 func (q *Queries) UpdatePassword(ctx context.Context, arg UpdatePasswordParams) (User, error) {
 	row := q.db.QueryRow(ctx, updatePassword, arg.UserPassword, arg.UserUuid)
 	var i User
@@ -463,14 +543,33 @@ func (q *Queries) UpdatePassword(ctx context.Context, arg UpdatePasswordParams)
 		&i.UserCreatedAt,
 		&i.UserUpdatedAt,
 		&i.UserDeletedAt,
+		&i.UserPasswordChangedAt,
 	)
 	return i, err
 }
 
+const updatePasswordHash = `-- name: UpdatePasswordHash :exec
+UPDATE users
+SET
+  user_password = $1
+WHERE user_id = $2
+`
+
+type UpdatePasswordHashParams struct {
+	UserPassword string `json:"user_password"`
+	UserID       int32  `json:"user_id"`
+}
+
+func (q *Queries) UpdatePasswordHash(ctx context.Context, arg UpdatePasswordHashParams) error {
+	_, err := q.db.Exec(ctx, updatePasswordHash, arg.UserPassword, arg.UserID)
+	return err
+}
+
 const updateUserByUuid = `-- name: UpdateUserByUuid :one
 UPDATE users
 SET
   user_password = COALESCE($1, user_password),
+  user_password_changed_at = CASE WHEN $1::TEXT IS NULL THEN user_password_changed_at ELSE now() END,
   user_fullname = COALESCE($2, user_fullname),
   user_age      = COALESCE($3, user_age),
   user_status   = COALESCE($4, user_status),
@@ -478,7 +577,7 @@ SET
 WHERE
   user_uuid = $6::uuid
   AND user_deleted_at IS NULL
-RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at
+RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
 `
 
 type UpdateUserByUuidParams struct {
@@ -512,6 +611,74 @@ func (q *Queries) UpdateUserByUuid(ctx context.Context, arg UpdateUserByUuidPara
 		&i.UserCreatedAt,
 		&i.UserUpdatedAt,
 		&i.UserDeletedAt,
+		&i.UserPasswordChangedAt,
+	)
+	return i, err
+}
+
+const updateUserEmail = `-- name: UpdateUserEmail :one
+UPDATE users
+SET
+  user_email      = $1,
+  user_updated_at = now()
+WHERE
+  user_uuid = $2::uuid
+  AND user_deleted_at IS NULL
+RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
+`
+
+type UpdateUserEmailParams struct {
+	UserEmail string    `json:"user_email"`
+	UserUuid  uuid.UUID `json:"user_uuid"`
+}
+
+func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserEmail, arg.UserEmail, arg.UserUuid)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.UserUuid,
+		&i.UserEmail,
+		&i.UserPassword,
+		&i.UserFullname,
+		&i.UserAge,
+		&i.UserStatus,
+		&i.UserLevel,
+		&i.UserCreatedAt,
+		&i.UserUpdatedAt,
+		&i.UserDeletedAt,
+		&i.UserPasswordChangedAt,
+	)
+	return i, err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE users
+SET
+  user_status = 1
+WHERE
+  user_uuid = $1::uuid
+  AND user_status = 4
+  AND user_deleted_at IS NULL
+RETURNING user_id, user_uuid, user_email, user_password, user_fullname, user_age, user_status, user_level, user_created_at, user_updated_at, user_deleted_at, user_password_changed_at
+`
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, userUuid uuid.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, verifyUserEmail, userUuid)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.UserUuid,
+		&i.UserEmail,
+		&i.UserPassword,
+		&i.UserFullname,
+		&i.UserAge,
+		&i.UserStatus,
+		&i.UserLevel,
+		&i.UserCreatedAt,
+		&i.UserUpdatedAt,
+		&i.UserDeletedAt,
+		&i.UserPasswordChangedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,61 @@
+package v1dto
+
+import (
+	"gin/user-management-api/internal/db/sqlc"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ApiKeyDTO struct {
+	ID         string   `json:"id"`
+	Key        string   `json:"key,omitempty"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	OwnerID    int32    `json:"owner_id"`
+	Scopes     []string `json:"scopes"`
+	Revoked    bool     `json:"revoked"`
+	ExpiresAt  string   `json:"expires_at,omitempty"`
+	LastUsedAt string   `json:"last_used_at,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+type CreateApiKeyInput struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"omitempty,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at" binding:"omitempty,gt"`
+}
+
+type ApiKeyParams struct {
+	Uuid string `uri:"uuid" binding:"required,uuid"`
+}
+
+func formatTimestamptz(t pgtype.Timestamptz) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format("2006-01-02 15:04:05")
+}
+
+func MapApiKeyToDTO(key sqlc.ApiKey, plainKey string) *ApiKeyDTO {
+	return &ApiKeyDTO{
+		ID:         key.ApiKeyUuid.String(),
+		Key:        plainKey,
+		Name:       key.ApiKeyName,
+		Prefix:     key.ApiKeyPrefix,
+		OwnerID:    key.ApiKeyOwnerID,
+		Scopes:     key.ApiKeyScopes,
+		Revoked:    key.ApiKeyRevokedAt.Valid,
+		ExpiresAt:  formatTimestamptz(key.ApiKeyExpiresAt),
+		LastUsedAt: formatTimestamptz(key.ApiKeyLastUsedAt),
+		CreatedAt:  key.ApiKeyCreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func MapApiKeysToDTO(keys []sqlc.ApiKey) []ApiKeyDTO {
+	dtos := make([]ApiKeyDTO, 0, len(keys))
+	for _, key := range keys {
+		dtos = append(dtos, *MapApiKeyToDTO(key, ""))
+	}
+	return dtos
+}
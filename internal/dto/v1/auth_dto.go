@@ -1,8 +1,48 @@
 package v1dto
 
+import (
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/utils"
+)
+
 type LoginInput struct {
+	Email      string `json:"email" binding:"required,email,email_advanced"`
+	Password   string `json:"password" binding:"required,min=8"`
+	DeviceName string `json:"device_name" binding:"omitempty,max=100"`
+}
+
+type RegisterInput struct {
+	Name     string `json:"name" binding:"required,max=100"`
 	Email    string `json:"email" binding:"required,email,email_advanced"`
-	Password string `json:"password" binding:"required,min=8"`
+	Age      int32  `json:"age" binding:"omitempty,gt=0"`
+	Password string `json:"password" binding:"required,min=8,password_strong,password_not_breached"`
+}
+
+type VerifyEmailInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ReportSignInInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type EmailChangeInput struct {
+	NewEmail string `json:"new_email" binding:"required,email,email_advanced"`
+	Password string `json:"password" binding:"required"`
+}
+
+type EmailChangeTokenInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type MagicLinkInput struct {
+	Email      string `json:"email" binding:"required,email,email_advanced"`
+	DeviceName string `json:"device_name" binding:"omitempty,max=100"`
+}
+
+type ConsumeMagicLinkInput struct {
+	Token       string `json:"token" binding:"required"`
+	DeviceToken string `json:"device_token" binding:"required"`
 }
 
 type RefreshTokenInput struct {
@@ -14,11 +54,71 @@ type RequestPasswordInput struct {
 }
 type ResetPasswordInput struct {
 	Token       string `json:"token" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=8"`
+	NewPassword string `json:"new_password" binding:"required,min=8,password_strong,password_not_breached"`
+}
+
+type ChangeExpiredPasswordInput struct {
+	PasswordChangeToken string `json:"password_change_token" binding:"required"`
+	NewPassword         string `json:"new_password" binding:"required,min=8,password_strong,password_not_breached"`
+}
+
+type MfaVerifyInput struct {
+	MfaToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required,min=6,max=32"`
+}
+
+type MfaSetupInput struct {
+	MfaToken string `json:"mfa_token" binding:"required"`
+}
+
+type MfaCodeInput struct {
+	Code string `json:"code" binding:"required,min=6,max=32"`
+}
+
+type MfaDisableInput struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required,min=6,max=32"`
+}
+
+func (input *RegisterInput) MapRegisterInputToModel() sqlc.CreateUserParams {
+	return sqlc.CreateUserParams{
+		UserEmail:    input.Email,
+		UserFullname: input.Name,
+		UserPassword: input.Password,
+		UserAge:      utils.ConvertToInt32Pointer(input.Age),
+	}
 }
 
 type LoginResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	ExpiresIn    int    `json:"expires_in"`
+	AccessToken   string   `json:"access_token"`
+	RefreshToken  string   `json:"refresh_token"`
+	ExpiresIn     int      `json:"expires_in"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+type MagicLinkResponse struct {
+	DeviceToken string `json:"device_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+type MfaChallengeResponse struct {
+	Status    string `json:"status"`
+	MfaToken  string `json:"mfa_token"`
+	ExpiresIn int    `json:"expires_in"`
+}
+
+type PasswordExpiredResponse struct {
+	Status              string `json:"status"`
+	PasswordChangeToken string `json:"password_change_token"`
+	ExpiresIn           int    `json:"expires_in"`
+}
+
+type MfaEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	URL    string `json:"otpauth_url"`
+	QRCode string `json:"qr_code"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
 }
@@ -0,0 +1,31 @@
+package v1dto
+
+import "gin/user-management-api/internal/db/sqlc"
+
+type DataExportDTO struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+	ExpiresAt   string `json:"expires_at,omitempty"`
+	CompletedAt string `json:"completed_at,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type DataExportParams struct {
+	Uuid string `uri:"uuid" binding:"required,uuid"`
+}
+
+type DownloadDataExportInput struct {
+	Token string `form:"token" binding:"required"`
+}
+
+func MapDataExportToDTO(export sqlc.DataExport) *DataExportDTO {
+	return &DataExportDTO{
+		ID:          export.ExportUuid.String(),
+		Status:      export.ExportStatus,
+		Error:       export.ExportError,
+		ExpiresAt:   formatTimestamptz(export.ExportExpiresAt),
+		CompletedAt: formatTimestamptz(export.ExportCompletedAt),
+		CreatedAt:   export.ExportCreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
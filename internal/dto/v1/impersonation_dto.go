@@ -0,0 +1,11 @@
+package v1dto
+
+type ImpersonateInput struct {
+	Reason string `json:"reason" binding:"omitempty,max=255"`
+}
+
+type ImpersonationResponse struct {
+	AccessToken string   `json:"access_token"`
+	ExpiresIn   int      `json:"expires_in"`
+	User        *UserDTO `json:"user"`
+}
@@ -0,0 +1,48 @@
+package v1dto
+
+import "gin/user-management-api/internal/db/sqlc"
+
+type InvitationDTO struct {
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	Name      string `json:"full_name"`
+	Level     int32  `json:"level"`
+	InvitedBy *int32 `json:"invited_by"`
+	ExpiresAt string `json:"expires_at"`
+	CreatedAt string `json:"created_at"`
+}
+
+type CreateInvitationInput struct {
+	Email string `json:"email" binding:"required,email,email_advanced"`
+	Name  string `json:"name" binding:"required,max=100"`
+	Level int32  `json:"level" binding:"required,gte=1"`
+}
+
+type AcceptInvitationInput struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8,password_strong,password_not_breached"`
+}
+
+type InvitationParams struct {
+	Uuid string `uri:"uuid" binding:"required,uuid"`
+}
+
+func MapInvitationToDTO(invitation sqlc.UserInvitation) *InvitationDTO {
+	return &InvitationDTO{
+		ID:        invitation.InvitationUuid.String(),
+		Email:     invitation.InvitationEmail,
+		Name:      invitation.InvitationFullname,
+		Level:     invitation.InvitationLevel,
+		InvitedBy: invitation.InvitationInvitedBy,
+		ExpiresAt: invitation.InvitationExpiresAt.Format("2006-01-02 15:04:05"),
+		CreatedAt: invitation.InvitationCreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func MapInvitationsToDTO(invitations []sqlc.UserInvitation) []InvitationDTO {
+	dtos := make([]InvitationDTO, 0, len(invitations))
+	for _, invitation := range invitations {
+		dtos = append(dtos, *MapInvitationToDTO(invitation))
+	}
+	return dtos
+}
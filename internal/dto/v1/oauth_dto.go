@@ -0,0 +1,130 @@
+package v1dto
+
+import "gin/user-management-api/internal/db/sqlc"
+
+type OAuthClientDTO struct {
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"`
+	GrantTypes   []string `json:"grant_types"`
+	Scopes       []string `json:"scopes"`
+	Confidential bool     `json:"confidential"`
+	CreatedAt    string   `json:"created_at"`
+}
+
+type CreateOAuthClientInput struct {
+	Name         string   `json:"name" binding:"required,max=100"`
+	RedirectURIs []string `json:"redirect_uris" binding:"omitempty,dive,url"`
+	GrantTypes   []string `json:"grant_types" binding:"required,min=1,dive,oneof=authorization_code refresh_token client_credentials"`
+	Scopes       []string `json:"scopes" binding:"omitempty,dive,required"`
+	Confidential *bool    `json:"confidential" binding:"required"`
+}
+
+type OAuthClientParams struct {
+	ClientID string `uri:"client_id" binding:"required"`
+}
+
+type AuthorizeInput struct {
+	ResponseType        string `form:"response_type" json:"response_type" binding:"required,oneof=code"`
+	ClientID            string `form:"client_id" json:"client_id" binding:"required"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri" binding:"required,url"`
+	Scope               string `form:"scope" json:"scope" binding:"omitempty,max=1000"`
+	State               string `form:"state" json:"state" binding:"omitempty,max=255"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge" binding:"required,min=43,max=128"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method" binding:"required,oneof=S256"`
+}
+
+type ConsentInput struct {
+	AuthorizeInput
+	Approve *bool `json:"approve" binding:"required"`
+}
+
+type AuthorizePromptResponse struct {
+	ClientID       string   `json:"client_id"`
+	ClientName     string   `json:"client_name"`
+	Scopes         []string `json:"scopes"`
+	ConsentGranted bool     `json:"consent_granted"`
+}
+
+type AuthorizeResponse struct {
+	RedirectURI string `json:"redirect_uri"`
+}
+
+type TokenInput struct {
+	GrantType    string `form:"grant_type" binding:"required"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+	Scope        string `form:"scope"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+// TokenResponse follows RFC 6749 section 5.1
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+type ClientTokenInput struct {
+	Token         string `form:"token" binding:"required"`
+	TokenTypeHint string `form:"token_type_hint"`
+	ClientID      string `form:"client_id"`
+	ClientSecret  string `form:"client_secret"`
+}
+
+// IntrospectionResponse follows RFC 7662 section 2.2
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Sub       string `json:"sub,omitempty"`
+	Username  string `json:"username,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	Role      int32  `json:"role,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Jti       string `json:"jti,omitempty"`
+}
+
+func (input *CreateOAuthClientInput) MapCreateInputToModel() sqlc.CreateOAuthClientParams {
+	params := sqlc.CreateOAuthClientParams{
+		ClientName:         input.Name,
+		ClientRedirectUris: input.RedirectURIs,
+		ClientGrantTypes:   input.GrantTypes,
+		ClientScopes:       input.Scopes,
+	}
+	if params.ClientRedirectUris == nil {
+		params.ClientRedirectUris = []string{}
+	}
+	if params.ClientScopes == nil {
+		params.ClientScopes = []string{}
+	}
+	return params
+}
+
+func MapOAuthClientToDTO(client sqlc.OauthClient, secret string) *OAuthClientDTO {
+	return &OAuthClientDTO{
+		ClientID:     client.ClientID,
+		ClientSecret: secret,
+		Name:         client.ClientName,
+		RedirectURIs: client.ClientRedirectUris,
+		GrantTypes:   client.ClientGrantTypes,
+		Scopes:       client.ClientScopes,
+		Confidential: client.ClientSecretHash != nil,
+		CreatedAt:    client.ClientCreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func MapOAuthClientsToDTO(clients []sqlc.OauthClient) []OAuthClientDTO {
+	dtos := make([]OAuthClientDTO, 0, len(clients))
+	for _, client := range clients {
+		dtos = append(dtos, *MapOAuthClientToDTO(client, ""))
+	}
+	return dtos
+}
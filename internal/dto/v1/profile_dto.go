@@ -0,0 +1,30 @@
+package v1dto
+
+import "gin/user-management-api/internal/db/sqlc"
+
+// Status and level are managed by administrators through /users and cannot be set here
+type UpdateProfileInput struct {
+	Name *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Age  *int32  `json:"age" binding:"omitempty,gt=0"`
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8,password_strong,password_not_breached"`
+}
+
+type CloseAccountInput struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type AccountClosureDTO struct {
+	ClosedAt      string `json:"closed_at"`
+	RestoreBefore string `json:"restore_before"`
+}
+
+func MapAccountClosureToDTO(closure sqlc.AccountClosure) *AccountClosureDTO {
+	return &AccountClosureDTO{
+		ClosedAt:      closure.ClosureRequestedAt.Format("2006-01-02 15:04:05"),
+		RestoreBefore: closure.ClosurePurgeAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,93 @@
+package v1dto
+
+import "gin/user-management-api/internal/db/sqlc"
+
+type RoleDTO struct {
+	ID          int32    `json:"id"`
+	Code        string   `json:"code"`
+	Name        string   `json:"name"`
+	Description *string  `json:"description"`
+	IsSystem    bool     `json:"is_system"`
+	Permissions []string `json:"permissions,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+type PermissionDTO struct {
+	Code        string  `json:"code"`
+	Description *string `json:"description"`
+}
+
+type CreateRoleInput struct {
+	Code        string   `json:"code" binding:"required,max=50,slug"`
+	Name        string   `json:"name" binding:"required,max=100"`
+	Description *string  `json:"description" binding:"omitempty,max=255"`
+	Permissions []string `json:"permissions" binding:"omitempty,dive,required"`
+}
+
+type UpdateRoleInput struct {
+	Name        *string  `json:"name" binding:"omitempty,max=100"`
+	Description *string  `json:"description" binding:"omitempty,max=255"`
+	Permissions []string `json:"permissions" binding:"omitempty,dive,required"`
+}
+
+type GetRoleByIDParams struct {
+	ID int32 `uri:"id" binding:"required,gt=0"`
+}
+
+type RoleUserParams struct {
+	ID   int32  `uri:"id" binding:"required,gt=0"`
+	Uuid string `uri:"uuid" binding:"uuid"`
+}
+
+func (input *CreateRoleInput) MapCreateInputToModel() sqlc.CreateRoleParams {
+	return sqlc.CreateRoleParams{
+		RoleCode:        input.Code,
+		RoleName:        input.Name,
+		RoleDescription: input.Description,
+	}
+}
+
+func (input *UpdateRoleInput) MapUpdateInputToModel(roleID int32) sqlc.UpdateRoleParams {
+	return sqlc.UpdateRoleParams{
+		RoleName:        input.Name,
+		RoleDescription: input.Description,
+		RoleID:          roleID,
+	}
+}
+
+func MapRoleToDTO(role sqlc.Role, permissions []sqlc.Permission) *RoleDTO {
+	dto := &RoleDTO{
+		ID:          role.RoleID,
+		Code:        role.RoleCode,
+		Name:        role.RoleName,
+		Description: role.RoleDescription,
+		IsSystem:    role.RoleIsSystem,
+		CreatedAt:   role.RoleCreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if permissions != nil {
+		dto.Permissions = make([]string, 0, len(permissions))
+		for _, p := range permissions {
+			dto.Permissions = append(dto.Permissions, p.PermissionCode)
+		}
+	}
+	return dto
+}
+
+func MapRolesToDTO(roles []sqlc.Role) []RoleDTO {
+	dtos := make([]RoleDTO, 0, len(roles))
+	for _, role := range roles {
+		dtos = append(dtos, *MapRoleToDTO(role, nil))
+	}
+	return dtos
+}
+
+func MapPermissionsToDTO(permissions []sqlc.Permission) []PermissionDTO {
+	dtos := make([]PermissionDTO, 0, len(permissions))
+	for _, p := range permissions {
+		dtos = append(dtos, PermissionDTO{
+			Code:        p.PermissionCode,
+			Description: p.PermissionDescription,
+		})
+	}
+	return dtos
+}
@@ -0,0 +1,58 @@
+package v1dto
+
+import (
+	"gin/user-management-api/internal/db/sqlc"
+	"time"
+)
+
+type SecurityEventDTO struct {
+	ID        int64  `json:"id"`
+	UserID    *int32 `json:"user_id,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Type      string `json:"type"`
+	Outcome   string `json:"outcome"`
+	Reason    string `json:"reason,omitempty"`
+	IPAddress string `json:"ip_address"`
+	UserAgent string `json:"user_agent"`
+	TraceID   string `json:"trace_id,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+type GetMySecurityEventsParams struct {
+	Page  int32 `form:"page" binding:"omitempty,gte=1"`
+	Limit int32 `form:"limit" binding:"omitempty,gte=1,lte=100"`
+}
+
+type GetSecurityEventsParams struct {
+	UserUuid  string     `form:"user_uuid" binding:"omitempty,uuid"`
+	Type      string     `form:"type" binding:"omitempty,oneof=login token_refresh logout password_reset_request password_reset account_locked sign_in_reported email_change_request email_change account_restored password_change account_closed"`
+	Outcome   string     `form:"outcome" binding:"omitempty,oneof=success failure"`
+	IPAddress string     `form:"ip_address" binding:"omitempty,ip"`
+	From      *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page      int32      `form:"page" binding:"omitempty,gte=1"`
+	Limit     int32      `form:"limit" binding:"omitempty,gte=1,lte=500"`
+}
+
+func MapSecurityEventToDTO(event sqlc.SecurityEvent) *SecurityEventDTO {
+	return &SecurityEventDTO{
+		ID:        event.SecurityEventID,
+		UserID:    event.SecurityEventUserID,
+		Email:     event.SecurityEventEmail,
+		Type:      event.SecurityEventType,
+		Outcome:   event.SecurityEventOutcome,
+		Reason:    event.SecurityEventReason,
+		IPAddress: event.SecurityEventIpAddress,
+		UserAgent: event.SecurityEventUserAgent,
+		TraceID:   event.SecurityEventTraceID,
+		CreatedAt: event.SecurityEventCreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func MapSecurityEventsToDTO(events []sqlc.SecurityEvent) []SecurityEventDTO {
+	dtos := make([]SecurityEventDTO, 0, len(events))
+	for _, event := range events {
+		dtos = append(dtos, *MapSecurityEventToDTO(event))
+	}
+	return dtos
+}
@@ -0,0 +1,44 @@
+package v1dto
+
+import "gin/user-management-api/pkg/auth"
+
+type SessionDTO struct {
+	ID         string `json:"id"`
+	DeviceName string `json:"device_name"`
+	IPAddress  string `json:"ip_address"`
+	UserAgent  string `json:"user_agent"`
+	Current    bool   `json:"current"`
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at"`
+	ExpiresAt  string `json:"expires_at"`
+}
+
+type SessionParams struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type UserSessionParams struct {
+	Uuid string `uri:"uuid" binding:"uuid"`
+	ID   string `uri:"id" binding:"required,uuid"`
+}
+
+func MapSessionToDTO(session auth.Session, currentSessionID string) *SessionDTO {
+	return &SessionDTO{
+		ID:         session.ID,
+		DeviceName: session.DeviceName,
+		IPAddress:  session.IPAddress,
+		UserAgent:  session.UserAgent,
+		Current:    session.ID == currentSessionID,
+		CreatedAt:  session.CreatedAt.Format("2006-01-02 15:04:05"),
+		LastUsedAt: session.LastUsedAt.Format("2006-01-02 15:04:05"),
+		ExpiresAt:  session.ExpiresAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func MapSessionsToDTO(sessions []auth.Session, currentSessionID string) []SessionDTO {
+	dtos := make([]SessionDTO, 0, len(sessions))
+	for _, session := range sessions {
+		dtos = append(dtos, *MapSessionToDTO(session, currentSessionID))
+	}
+	return dtos
+}
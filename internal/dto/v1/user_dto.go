@@ -21,9 +21,9 @@ type CreateUserInput struct {
 	Name 						string 			`json:"name" binding:"required"`
 	Email 					string 			`json:"email" binding:"required,email,email_advanced"`
 	Age 						int32				`json:"age" binding:"gt=0"`
-	Password 				string 			`json:"password" binding:"required,min=8,password_strong"`
+	Password 				string 			`json:"password" binding:"required,min=8,password_strong,password_not_breached"`
 	Status 					int32 				`json:"status" binding:"required,oneof=1 2 3"`
-	Level 					int32 				`json:"level" binding:"required,oneof=1 2 3"`
+	Level 					int32 				`json:"level" binding:"required,gte=1"`
 }
 
 
@@ -53,9 +53,20 @@ func (input *CreateUserInput) MapCreaeteInputToModel() sqlc.CreateUserParams {
 type UpdateUserInput struct {
 	Name 						*string 			`json:"name" binding:"omitempty"`
 	Age 						*int32				`json:"age" binding:"omitempty,gt=0"`
-	Password 				*string 			`json:"password" binding:"omitempty,min=8,password_strong"`
+	Password 				*string 			`json:"password" binding:"omitempty,min=8,password_strong,password_not_breached"`
 	Status 					*int32 				`json:"status" binding:"omitempty,oneof=1 2 3"`
-	Level 					*int32 				`json:"level" binding:"omitempty,oneof=1 2 3"`
+	Level 					*int32 				`json:"level" binding:"omitempty,gte=1"`
+}
+
+type UpdateUserStatusInput struct {
+	Status 					int32 				`json:"status" binding:"required,oneof=1 2 3"`
+}
+
+func (input *UpdateUserStatusInput) MapUpdateStatusInputToModel(userUuid uuid.UUID) sqlc.UpdateUserByUuidParams {
+	return sqlc.UpdateUserByUuidParams{
+		UserStatus: &input.Status,
+		UserUuid: userUuid,
+	}
 }
 
 func (input *UpdateUserInput) MapUpdateInputToModel(userUuid uuid.UUID) sqlc.UpdateUserByUuidParams {
@@ -101,6 +112,8 @@ func mapStatusToString(status int) string {
 		return "Inactive"
 	case 3:
 		return "Banned"
+	case 4:
+		return "Pending verification"
 	default:
 		return "None"
 	}
@@ -115,6 +128,6 @@ func mapLevelToString(level int) string {
 	case 3:
 		return "Member"
 	default:
-		return "None"
+		return "Custom"
 	}
 }
@@ -0,0 +1,92 @@
+package v1handler
+
+import (
+	v1dto "gin/user-management-api/internal/dto/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/internal/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ApiKeyHandler struct {
+	service v1service.ApiKeyService
+}
+
+func NewApiKeyHandler(service v1service.ApiKeyService) *ApiKeyHandler {
+	return &ApiKeyHandler{
+		service: service,
+	}
+}
+
+func (ah *ApiKeyHandler) GetAllAPIKeys(ctx *gin.Context) {
+	keys, err := ah.service.GetAllAPIKeys(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Get all api keys successfully", v1dto.MapApiKeysToDTO(keys))
+}
+
+func (ah *ApiKeyHandler) CreateAPIKey(ctx *gin.Context) {
+	var input v1dto.CreateApiKeyInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	key, plainKey, err := ah.service.CreateAPIKey(ctx, input.Name, input.Scopes, input.ExpiresAt)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, "API key created successfully, store the key now as it will not be shown again", v1dto.MapApiKeyToDTO(key, plainKey))
+}
+
+func (ah *ApiKeyHandler) RotateAPIKey(ctx *gin.Context) {
+	keyUuid, ok := bindApiKeyUuid(ctx)
+	if !ok {
+		return
+	}
+
+	key, plainKey, err := ah.service.RotateAPIKey(ctx, keyUuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "API key rotated successfully, store the key now as it will not be shown again", v1dto.MapApiKeyToDTO(key, plainKey))
+}
+
+func (ah *ApiKeyHandler) RevokeAPIKey(ctx *gin.Context) {
+	keyUuid, ok := bindApiKeyUuid(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ah.service.RevokeAPIKey(ctx, keyUuid); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseStatusCode(ctx, http.StatusOK)
+}
+
+func bindApiKeyUuid(ctx *gin.Context) (uuid.UUID, bool) {
+	var params v1dto.ApiKeyParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return uuid.Nil, false
+	}
+
+	keyUuid, err := uuid.Parse(params.Uuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return uuid.Nil, false
+	}
+	return keyUuid, true
+}
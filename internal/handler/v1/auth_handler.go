@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
@@ -20,6 +21,118 @@ func NewAuthHandler(service v1service.AuthService) *AuthHandler {
 	}
 }
 
+func (ah *AuthHandler) Register(ctx *gin.Context) {
+	var input v1dto.RegisterInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	user, err := ah.service.Register(ctx, input.MapRegisterInputToModel())
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, "Account created, please check your email to verify it", v1dto.MapUserToDTO(user))
+}
+
+func (ah *AuthHandler) VerifyEmail(ctx *gin.Context) {
+	var input v1dto.VerifyEmailInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	if err := ah.service.VerifyEmail(ctx, input.Token); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Email verified successfully")
+}
+
+func (ah *AuthHandler) ReportSignIn(ctx *gin.Context) {
+	var input v1dto.ReportSignInInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	if err := ah.service.ReportSignIn(ctx, input.Token); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "All sessions have been signed out, check your email to set a new password")
+}
+
+func (ah *AuthHandler) RequestEmailChange(ctx *gin.Context) {
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	var input v1dto.EmailChangeInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	if err := ah.service.RequestEmailChange(ctx, userUuid, input.NewEmail, input.Password); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Check your new email address to confirm the change")
+}
+
+func (ah *AuthHandler) ConfirmEmailChange(ctx *gin.Context) {
+	var input v1dto.EmailChangeTokenInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	if err := ah.service.ConfirmEmailChange(ctx, input.Token); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Email changed successfully, please sign in again")
+}
+
+func (ah *AuthHandler) CancelEmailChange(ctx *gin.Context) {
+	var input v1dto.EmailChangeTokenInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	if err := ah.service.CancelEmailChange(ctx, input.Token); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Email change cancelled")
+}
+
+func (ah *AuthHandler) ResendVerificationEmail(ctx *gin.Context) {
+	var input v1dto.RequestPasswordInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	if err := ah.service.ResendVerificationEmail(ctx, input.Email); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Verification email sent")
+}
+
 func (ah *AuthHandler) Login(ctx *gin.Context) {
 	var input v1dto.LoginInput
 	if err := ctx.ShouldBindJSON(&input); err != nil {
@@ -27,17 +140,78 @@ func (ah *AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, refreshToken, expiresIn, err := ah.service.Login(ctx, input.Email, input.Password)
+	result, err := ah.service.Login(ctx, input.Email, input.Password, input.DeviceName)
+
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	respondLoginResult(ctx, result)
+}
+
+func (ah *AuthHandler) RequestMagicLink(ctx *gin.Context) {
+	var input v1dto.MagicLinkInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	deviceToken, err := ah.service.RequestMagicLink(ctx, input.Email, input.DeviceName)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
 
+	response := v1dto.MagicLinkResponse{
+		DeviceToken: deviceToken,
+		ExpiresIn:   int(v1service.MagicLinkTTL.Seconds()),
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "If the email is registered, a sign-in link has been sent", response)
+}
+
+func (ah *AuthHandler) ConsumeMagicLink(ctx *gin.Context) {
+	var input v1dto.ConsumeMagicLinkInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	result, err := ah.service.ConsumeMagicLink(ctx, input.Token, input.DeviceToken)
 	if err != nil {
 		utils.ResponseError(ctx, err)
 		return
 	}
 
+	respondLoginResult(ctx, result)
+}
+
+func respondLoginResult(ctx *gin.Context, result v1service.LoginResult) {
+	if result.PasswordStatus != "" {
+		response := v1dto.PasswordExpiredResponse{
+			Status:              result.PasswordStatus,
+			PasswordChangeToken: result.PasswordToken,
+			ExpiresIn:           result.ExpiresIn,
+		}
+		utils.ResponseSuccess(ctx, http.StatusOK, "Password has expired and must be changed", response)
+		return
+	}
+
+	if result.MfaStatus != "" {
+		response := v1dto.MfaChallengeResponse{
+			Status:    result.MfaStatus,
+			MfaToken:  result.MfaToken,
+			ExpiresIn: result.ExpiresIn,
+		}
+		utils.ResponseSuccess(ctx, http.StatusOK, "MFA verification required", response)
+		return
+	}
+
 	response := v1dto.LoginResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    expiresIn,
+		AccessToken:  result.AccessToken,
+		RefreshToken: result.RefreshToken,
+		ExpiresIn:    result.ExpiresIn,
 	}
 
 	utils.ResponseSuccess(ctx, http.StatusOK, "Login successfully", response)
@@ -109,3 +283,159 @@ func (ah *AuthHandler) ResetPassword(ctx *gin.Context) {
 
 	utils.ResponseSuccess(ctx, http.StatusOK, "Password reset successfully")
 }
+
+func (ah *AuthHandler) ChangeExpiredPassword(ctx *gin.Context) {
+	var input v1dto.ChangeExpiredPasswordInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	result, err := ah.service.ChangeExpiredPassword(ctx, input.PasswordChangeToken, input.NewPassword)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	respondLoginResult(ctx, result)
+}
+
+func (ah *AuthHandler) VerifyMfa(ctx *gin.Context) {
+	var input v1dto.MfaVerifyInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	result, err := ah.service.VerifyMfa(ctx, input.MfaToken, input.Code)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	response := v1dto.LoginResponse{
+		AccessToken:   result.AccessToken,
+		RefreshToken:  result.RefreshToken,
+		ExpiresIn:     result.ExpiresIn,
+		RecoveryCodes: result.RecoveryCodes,
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Login successfully", response)
+}
+
+func (ah *AuthHandler) SetupMfa(ctx *gin.Context) {
+	var input v1dto.MfaSetupInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	enrollment, err := ah.service.SetupMfa(ctx, input.MfaToken)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	response := v1dto.MfaEnrollmentResponse{
+		Secret: enrollment.Secret,
+		URL:    enrollment.URL,
+		QRCode: enrollment.QRCode,
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Scan the QR code and verify a code to finish MFA setup", response)
+}
+
+func (ah *AuthHandler) EnrollMfa(ctx *gin.Context) {
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	enrollment, err := ah.service.EnrollMfa(ctx, userUuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	response := v1dto.MfaEnrollmentResponse{
+		Secret: enrollment.Secret,
+		URL:    enrollment.URL,
+		QRCode: enrollment.QRCode,
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Scan the QR code and confirm a code to enable MFA", response)
+}
+
+func (ah *AuthHandler) ConfirmMfa(ctx *gin.Context) {
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	var input v1dto.MfaCodeInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	codes, err := ah.service.ConfirmMfa(ctx, userUuid, input.Code)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "MFA enabled successfully", v1dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (ah *AuthHandler) DisableMfa(ctx *gin.Context) {
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	var input v1dto.MfaDisableInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	if err := ah.service.DisableMfa(ctx, userUuid, input.Password, input.Code); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "MFA disabled successfully")
+}
+
+func (ah *AuthHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	var input v1dto.MfaCodeInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	codes, err := ah.service.RegenerateRecoveryCodes(ctx, userUuid, input.Code)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Recovery codes regenerated successfully", v1dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func getAuthUserUUID(ctx *gin.Context) (uuid.UUID, error) {
+	userUuid, err := uuid.Parse(ctx.GetString("user_uuid"))
+	if err != nil {
+		return uuid.Nil, utils.NewError(utils.UnauthorizedError, "Invalid user in access token")
+	}
+	return userUuid, nil
+}
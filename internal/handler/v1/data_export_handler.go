@@ -0,0 +1,85 @@
+package v1handler
+
+import (
+	"fmt"
+	v1dto "gin/user-management-api/internal/dto/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/internal/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DataExportHandler struct {
+	service v1service.DataExportService
+}
+
+func NewDataExportHandler(service v1service.DataExportService) *DataExportHandler {
+	return &DataExportHandler{
+		service: service,
+	}
+}
+
+func (dh *DataExportHandler) RequestExport(ctx *gin.Context) {
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	export, err := dh.service.RequestExport(ctx, userUuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusAccepted, "Data export requested, a download link will be sent by email", v1dto.MapDataExportToDTO(export))
+}
+
+func (dh *DataExportHandler) GetExport(ctx *gin.Context) {
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	var params v1dto.DataExportParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	exportUuid, err := uuid.Parse(params.Uuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	export, err := dh.service.GetExport(ctx, userUuid, exportUuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Get data export successfully", v1dto.MapDataExportToDTO(export))
+}
+
+func (dh *DataExportHandler) DownloadExport(ctx *gin.Context) {
+	var input v1dto.DownloadDataExportInput
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	export, err := dh.service.DownloadExport(ctx, input.Token)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"data-export-%s.zip\"", export.ExportUuid))
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Data(http.StatusOK, "application/zip", export.ExportArchive)
+}
@@ -0,0 +1,66 @@
+package v1handler
+
+import (
+	v1dto "gin/user-management-api/internal/dto/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/internal/validation"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ImpersonationHandler struct {
+	service v1service.ImpersonationService
+}
+
+func NewImpersonationHandler(service v1service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		service: service,
+	}
+}
+
+func (ih *ImpersonationHandler) StartImpersonation(ctx *gin.Context) {
+	var params v1dto.GetUserByUuidParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	var input v1dto.ImpersonateInput
+	if err := ctx.ShouldBindJSON(&input); err != nil && ctx.Request.ContentLength > 0 {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	targetUuid, err := uuid.Parse(params.Uuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	accessToken, user, err := ih.service.StartImpersonation(ctx, targetUuid, input.Reason)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	response := v1dto.ImpersonationResponse{
+		AccessToken: accessToken.Token,
+		ExpiresIn:   int(time.Until(accessToken.ExpiresAt).Seconds()),
+		User:        v1dto.MapUserToDTO(user),
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Impersonation started", response)
+}
+
+func (ih *ImpersonationHandler) StopImpersonation(ctx *gin.Context) {
+	if err := ih.service.StopImpersonation(ctx); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Impersonation stopped")
+}
@@ -0,0 +1,108 @@
+package v1handler
+
+import (
+	v1dto "gin/user-management-api/internal/dto/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/internal/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InvitationHandler struct {
+	service v1service.InvitationService
+}
+
+func NewInvitationHandler(service v1service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{
+		service: service,
+	}
+}
+
+func (ih *InvitationHandler) GetPendingInvitations(ctx *gin.Context) {
+	invitations, err := ih.service.GetPendingInvitations(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Get pending invitations successfully", v1dto.MapInvitationsToDTO(invitations))
+}
+
+func (ih *InvitationHandler) CreateInvitation(ctx *gin.Context) {
+	var input v1dto.CreateInvitationInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	invitation, err := ih.service.CreateInvitation(ctx, input.Email, input.Name, input.Level)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, "Invitation sent successfully", v1dto.MapInvitationToDTO(invitation))
+}
+
+func (ih *InvitationHandler) ResendInvitation(ctx *gin.Context) {
+	invitationUuid, ok := bindInvitationUuid(ctx)
+	if !ok {
+		return
+	}
+
+	invitation, err := ih.service.ResendInvitation(ctx, invitationUuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Invitation resent successfully", v1dto.MapInvitationToDTO(invitation))
+}
+
+func (ih *InvitationHandler) RevokeInvitation(ctx *gin.Context) {
+	invitationUuid, ok := bindInvitationUuid(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ih.service.RevokeInvitation(ctx, invitationUuid); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseStatusCode(ctx, http.StatusOK)
+}
+
+func (ih *InvitationHandler) AcceptInvitation(ctx *gin.Context) {
+	var input v1dto.AcceptInvitationInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	user, err := ih.service.AcceptInvitation(ctx, input.Token, input.Password)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, "Invitation accepted, you can now sign in", v1dto.MapUserToDTO(user))
+}
+
+func bindInvitationUuid(ctx *gin.Context) (uuid.UUID, bool) {
+	var params v1dto.InvitationParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return uuid.Nil, false
+	}
+
+	invitationUuid, err := uuid.Parse(params.Uuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return uuid.Nil, false
+	}
+	return invitationUuid, true
+}
@@ -0,0 +1,265 @@
+package v1handler
+
+import (
+	"errors"
+	v1dto "gin/user-management-api/internal/dto/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/internal/validation"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type OAuthHandler struct {
+	service v1service.OAuthService
+}
+
+func NewOAuthHandler(service v1service.OAuthService) *OAuthHandler {
+	return &OAuthHandler{
+		service: service,
+	}
+}
+
+func (oh *OAuthHandler) GetAllClients(ctx *gin.Context) {
+	clients, err := oh.service.GetAllClients(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Get all oauth clients successfully", v1dto.MapOAuthClientsToDTO(clients))
+}
+
+func (oh *OAuthHandler) CreateClient(ctx *gin.Context) {
+	var input v1dto.CreateOAuthClientInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	client, secret, err := oh.service.CreateClient(ctx, input.MapCreateInputToModel(), *input.Confidential)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, "OAuth client created successfully, store the client secret now as it will not be shown again", v1dto.MapOAuthClientToDTO(client, secret))
+}
+
+func (oh *OAuthHandler) RotateClientSecret(ctx *gin.Context) {
+	var params v1dto.OAuthClientParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	client, secret, err := oh.service.RotateClientSecret(ctx, params.ClientID)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Client secret rotated successfully", v1dto.MapOAuthClientToDTO(client, secret))
+}
+
+func (oh *OAuthHandler) DeleteClient(ctx *gin.Context) {
+	var params v1dto.OAuthClientParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	if err := oh.service.DeleteClient(ctx, params.ClientID); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseStatusCode(ctx, http.StatusOK)
+}
+
+func (oh *OAuthHandler) GetAuthorization(ctx *gin.Context) {
+	var input v1dto.AuthorizeInput
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	userUuid, err := getAuthorizingUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	prompt, err := oh.service.PrepareAuthorization(ctx, userUuid, mapAuthorizeInput(input))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	response := v1dto.AuthorizePromptResponse{
+		ClientID:       prompt.Client.ClientID,
+		ClientName:     prompt.Client.ClientName,
+		Scopes:         prompt.Scopes,
+		ConsentGranted: prompt.ConsentGranted,
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Review the requested access", response)
+}
+
+func (oh *OAuthHandler) Authorize(ctx *gin.Context) {
+	var input v1dto.ConsentInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	userUuid, err := getAuthorizingUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	redirectURI, err := oh.service.Authorize(ctx, userUuid, mapAuthorizeInput(input.AuthorizeInput), *input.Approve)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Redirect the user agent to continue", v1dto.AuthorizeResponse{RedirectURI: redirectURI})
+}
+
+// Token speaks the RFC 6749 wire format instead of the usual response envelope
+func (oh *OAuthHandler) Token(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+
+	var input v1dto.TokenInput
+	if err := ctx.ShouldBind(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_request",
+			"error_description": "grant_type is required",
+		})
+		return
+	}
+
+	result, err := oh.service.Token(ctx, v1service.TokenRequest{
+		GrantType:    input.GrantType,
+		Code:         input.Code,
+		RedirectURI:  input.RedirectURI,
+		CodeVerifier: input.CodeVerifier,
+		RefreshToken: input.RefreshToken,
+		Scope:        input.Scope,
+		ClientID:     input.ClientID,
+		ClientSecret: input.ClientSecret,
+	})
+	if err != nil {
+		responseOAuthError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, v1dto.TokenResponse{
+		AccessToken:  result.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    result.ExpiresIn,
+		RefreshToken: result.RefreshToken,
+		Scope:        strings.Join(result.Scopes, " "),
+	})
+}
+
+func (oh *OAuthHandler) Introspect(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+
+	var input v1dto.ClientTokenInput
+	if err := ctx.ShouldBind(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_request",
+			"error_description": "token is required",
+		})
+		return
+	}
+
+	result, err := oh.service.Introspect(ctx, mapClientTokenInput(input))
+	if err != nil {
+		responseOAuthError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, v1dto.IntrospectionResponse{
+		Active:    result.Active,
+		Sub:       result.Sub,
+		Username:  result.Username,
+		ClientID:  result.ClientID,
+		Scope:     result.Scope,
+		Role:      result.Role,
+		TokenType: result.TokenType,
+		Exp:       result.Exp,
+		Iat:       result.Iat,
+		Jti:       result.Jti,
+	})
+}
+
+func (oh *OAuthHandler) Revoke(ctx *gin.Context) {
+	var input v1dto.ClientTokenInput
+	if err := ctx.ShouldBind(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_request",
+			"error_description": "token is required",
+		})
+		return
+	}
+
+	if err := oh.service.Revoke(ctx, mapClientTokenInput(input)); err != nil {
+		responseOAuthError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// responseOAuthError renders errors in the RFC 6749 format, other errors use the default response
+func responseOAuthError(ctx *gin.Context, err error) {
+	var oauthErr *v1service.OAuthError
+	if errors.As(err, &oauthErr) {
+		if oauthErr.Status == http.StatusUnauthorized {
+			ctx.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		}
+		ctx.JSON(oauthErr.Status, gin.H{
+			"error":             oauthErr.Code,
+			"error_description": oauthErr.Description,
+		})
+		return
+	}
+	utils.ResponseError(ctx, err)
+}
+
+func mapClientTokenInput(input v1dto.ClientTokenInput) v1service.ClientTokenRequest {
+	return v1service.ClientTokenRequest{
+		Token:         input.Token,
+		TokenTypeHint: input.TokenTypeHint,
+		ClientID:      input.ClientID,
+		ClientSecret:  input.ClientSecret,
+	}
+}
+
+func mapAuthorizeInput(input v1dto.AuthorizeInput) v1service.AuthorizeRequest {
+	return v1service.AuthorizeRequest{
+		ClientID:            input.ClientID,
+		RedirectURI:         input.RedirectURI,
+		Scope:               input.Scope,
+		State:               input.State,
+		CodeChallenge:       input.CodeChallenge,
+		CodeChallengeMethod: input.CodeChallengeMethod,
+	}
+}
+
+// getAuthorizingUserUUID only accepts tokens obtained by the user directly, not tokens issued to other OAuth clients
+func getAuthorizingUserUUID(ctx *gin.Context) (uuid.UUID, error) {
+	if ctx.GetString("client_id") != "" {
+		return uuid.Nil, utils.NewError(utils.ForbiddenError, "OAuth client tokens cannot authorize other clients")
+	}
+	return getAuthUserUUID(ctx)
+}
@@ -0,0 +1,102 @@
+package v1handler
+
+import (
+	v1dto "gin/user-management-api/internal/dto/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/internal/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileHandler struct {
+	service v1service.ProfileService
+}
+
+func NewProfileHandler(service v1service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{
+		service: service,
+	}
+}
+
+func (ph *ProfileHandler) GetProfile(ctx *gin.Context) {
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	user, err := ph.service.GetProfile(ctx, userUuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Get profile successfully", v1dto.MapUserToDTO(user))
+}
+
+func (ph *ProfileHandler) UpdateProfile(ctx *gin.Context) {
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	var input v1dto.UpdateProfileInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	user, err := ph.service.UpdateProfile(ctx, userUuid, input.Name, input.Age)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Profile updated successfully", v1dto.MapUserToDTO(user))
+}
+
+func (ph *ProfileHandler) ChangePassword(ctx *gin.Context) {
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	var input v1dto.ChangePasswordInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	if err := ph.service.ChangePassword(ctx, userUuid, input.CurrentPassword, input.NewPassword); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Password changed successfully, other sessions have been signed out")
+}
+
+func (ph *ProfileHandler) CloseAccount(ctx *gin.Context) {
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	var input v1dto.CloseAccountInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	closure, err := ph.service.CloseAccount(ctx, userUuid, input.Password)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Account closed successfully, log in before restore_before to restore it", v1dto.MapAccountClosureToDTO(closure))
+}
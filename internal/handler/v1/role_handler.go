@@ -0,0 +1,153 @@
+package v1handler
+
+import (
+	v1dto "gin/user-management-api/internal/dto/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/internal/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RoleHandler struct {
+	service v1service.RoleService
+}
+
+func NewRoleHandler(service v1service.RoleService) *RoleHandler {
+	return &RoleHandler{
+		service: service,
+	}
+}
+
+func (rh *RoleHandler) GetAllRoles(ctx *gin.Context) {
+	roles, err := rh.service.GetAllRoles(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Get all roles successfully", v1dto.MapRolesToDTO(roles))
+}
+
+func (rh *RoleHandler) GetRoleByID(ctx *gin.Context) {
+	var params v1dto.GetRoleByIDParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	role, permissions, err := rh.service.GetRoleByID(ctx, params.ID)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Find role successfully", v1dto.MapRoleToDTO(role, permissions))
+}
+
+func (rh *RoleHandler) CreateRole(ctx *gin.Context) {
+	var input v1dto.CreateRoleInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	role, permissions, err := rh.service.CreateRole(ctx, input.MapCreateInputToModel(), input.Permissions)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, "Role created successfully", v1dto.MapRoleToDTO(role, permissions))
+}
+
+func (rh *RoleHandler) UpdateRole(ctx *gin.Context) {
+	var params v1dto.GetRoleByIDParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	var input v1dto.UpdateRoleInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	role, permissions, err := rh.service.UpdateRole(ctx, input.MapUpdateInputToModel(params.ID), input.Permissions)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Role updated successfully", v1dto.MapRoleToDTO(role, permissions))
+}
+
+func (rh *RoleHandler) DeleteRole(ctx *gin.Context) {
+	var params v1dto.GetRoleByIDParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	if err := rh.service.DeleteRole(ctx, params.ID); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseStatusCode(ctx, http.StatusOK)
+}
+
+func (rh *RoleHandler) GetAllPermissions(ctx *gin.Context) {
+	permissions, err := rh.service.GetAllPermissions(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Get all permissions successfully", v1dto.MapPermissionsToDTO(permissions))
+}
+
+func (rh *RoleHandler) AssignUserRole(ctx *gin.Context) {
+	var params v1dto.RoleUserParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	uuidUser, err := uuid.Parse(params.Uuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	if err := rh.service.AssignUserRole(ctx, params.ID, uuidUser); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Role assigned successfully")
+}
+
+func (rh *RoleHandler) RemoveUserRole(ctx *gin.Context) {
+	var params v1dto.RoleUserParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	uuidUser, err := uuid.Parse(params.Uuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	if err := rh.service.RemoveUserRole(ctx, params.ID, uuidUser); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Role removed successfully")
+}
@@ -0,0 +1,84 @@
+package v1handler
+
+import (
+	v1dto "gin/user-management-api/internal/dto/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/internal/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SecurityEventHandler struct {
+	service v1service.SecurityEventService
+}
+
+func NewSecurityEventHandler(service v1service.SecurityEventService) *SecurityEventHandler {
+	return &SecurityEventHandler{
+		service: service,
+	}
+}
+
+func (sh *SecurityEventHandler) GetMySecurityEvents(ctx *gin.Context) {
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	var params v1dto.GetMySecurityEventsParams
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	events, total, err := sh.service.GetSecurityEvents(ctx, v1service.SecurityEventFilter{
+		UserUUID: &userUuid,
+		Page:     params.Page,
+		Limit:    params.Limit,
+	})
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	paginationResp := utils.NewPaginationResponse(v1dto.MapSecurityEventsToDTO(events), params.Page, params.Limit, total)
+	utils.ResponseSuccess(ctx, http.StatusOK, "Get security events successfully", paginationResp)
+}
+
+func (sh *SecurityEventHandler) GetSecurityEvents(ctx *gin.Context) {
+	var params v1dto.GetSecurityEventsParams
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	filter := v1service.SecurityEventFilter{
+		EventType: params.Type,
+		Outcome:   params.Outcome,
+		IPAddress: params.IPAddress,
+		From:      params.From,
+		To:        params.To,
+		Page:      params.Page,
+		Limit:     params.Limit,
+	}
+	if params.UserUuid != "" {
+		userUuid, err := uuid.Parse(params.UserUuid)
+		if err != nil {
+			utils.ResponseError(ctx, err)
+			return
+		}
+		filter.UserUUID = &userUuid
+	}
+
+	events, total, err := sh.service.GetSecurityEvents(ctx, filter)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	paginationResp := utils.NewPaginationResponse(v1dto.MapSecurityEventsToDTO(events), params.Page, params.Limit, total)
+	utils.ResponseSuccess(ctx, http.StatusOK, "Get security events successfully", paginationResp)
+}
@@ -0,0 +1,138 @@
+package v1handler
+
+import (
+	v1dto "gin/user-management-api/internal/dto/v1"
+	v1service "gin/user-management-api/internal/service/v1"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/internal/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SessionHandler struct {
+	service v1service.SessionService
+}
+
+func NewSessionHandler(service v1service.SessionService) *SessionHandler {
+	return &SessionHandler{
+		service: service,
+	}
+}
+
+func (sh *SessionHandler) GetMySessions(ctx *gin.Context) {
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	sessions, err := sh.service.GetSessions(ctx, userUuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Get sessions successfully", v1dto.MapSessionsToDTO(sessions, ctx.GetString("session_id")))
+}
+
+func (sh *SessionHandler) RevokeMySession(ctx *gin.Context) {
+	var params v1dto.SessionParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	if err := sh.service.RevokeSession(ctx, userUuid, params.ID); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Session revoked successfully")
+}
+
+func (sh *SessionHandler) RevokeMySessions(ctx *gin.Context) {
+	userUuid, err := getAuthUserUUID(ctx)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	if err := sh.service.RevokeAllSessions(ctx, userUuid); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "All sessions revoked successfully")
+}
+
+func (sh *SessionHandler) GetUserSessions(ctx *gin.Context) {
+	var params v1dto.GetUserByUuidParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	userUuid, err := uuid.Parse(params.Uuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	sessions, err := sh.service.GetSessions(ctx, userUuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Get sessions successfully", v1dto.MapSessionsToDTO(sessions, ctx.GetString("session_id")))
+}
+
+func (sh *SessionHandler) RevokeUserSession(ctx *gin.Context) {
+	var params v1dto.UserSessionParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	userUuid, err := uuid.Parse(params.Uuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	if err := sh.service.RevokeSession(ctx, userUuid, params.ID); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "Session revoked successfully")
+}
+
+func (sh *SessionHandler) RevokeUserSessions(ctx *gin.Context) {
+	var params v1dto.GetUserByUuidParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	userUuid, err := uuid.Parse(params.Uuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	if err := sh.service.RevokeAllSessions(ctx, userUuid); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, "All sessions revoked successfully")
+}
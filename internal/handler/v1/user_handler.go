@@ -111,6 +111,35 @@ func (uh *UserHandler) UpdateUser(ctx *gin.Context) {
 	utils.ResponseSuccess(ctx, http.StatusCreated, "User updated successfully",userDto)
 }
 
+func (uh *UserHandler) UpdateUserStatus(ctx *gin.Context) {
+	var params v1dto.GetUserByUuidParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	uuidUser, err := uuid.Parse(params.Uuid);
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	var input v1dto.UpdateUserStatusInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	user := input.MapUpdateStatusInputToModel(uuidUser)
+	updateUser, err := uh.service.UpdateUser(ctx, user)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+	userDto := v1dto.MapUserToDTO(updateUser)
+	utils.ResponseSuccess(ctx, http.StatusOK, "User status updated successfully",userDto)
+}
+
 func (uh *UserHandler) SortDeleteUser(ctx *gin.Context) {
 	var params v1dto.GetUserByUuidParams
 	if err := ctx.ShouldBindUri(&params); err != nil {
@@ -157,6 +186,29 @@ func (uh *UserHandler) RestoreUser(ctx *gin.Context) {
 	utils.ResponseSuccess(ctx, http.StatusOK, "Resote user successfully",userDto)
 }
 
+func (uh *UserHandler) UnlockUser(ctx *gin.Context) {
+	var params v1dto.GetUserByUuidParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		utils.ResponseValidation(ctx, validation.HandleValidationErrors(err))
+		return
+	}
+
+	uuidUser, err := uuid.Parse(params.Uuid)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	user, err := uh.service.UnlockUser(ctx, uuidUser)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	userDto := v1dto.MapUserToDTO(user)
+	utils.ResponseSuccess(ctx, http.StatusOK, "User unlocked successfully", userDto)
+}
+
 func (uh *UserHandler) DeleteUser(ctx *gin.Context) {
 	var params v1dto.GetUserByUuidParams
 	if err := ctx.ShouldBindUri(&params); err != nil {
@@ -0,0 +1,24 @@
+package v1handler
+
+import (
+	"gin/user-management-api/pkg/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WellKnownHandler struct {
+	tokenService auth.TokenService
+}
+
+func NewWellKnownHandler(tokenService auth.TokenService) *WellKnownHandler {
+	return &WellKnownHandler{
+		tokenService: tokenService,
+	}
+}
+
+// GetJWKS follows RFC 7517 so the key set is returned without the usual response envelope
+func (wh *WellKnownHandler) GetJWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, wh.tokenService.JWKS())
+}
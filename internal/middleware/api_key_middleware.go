@@ -1,30 +1,88 @@
 package middleware
 
 import (
+	"crypto/subtle"
+	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/pkg/loggers"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+const legacyAPIKeyID = "legacy"
+
+// oauthClientPaths authenticate the OAuth client itself, third-party clients never hold an API key
+var oauthClientPaths = []string{
+	"/api/v1/oauth/token",
+	"/api/v1/auth/introspect",
+	"/api/v1/auth/revoke",
+}
+
+var apiKeyAuthenticator auth.APIKeyAuthenticator
+
+func InitApiKeyMiddleware(authenticator auth.APIKeyAuthenticator) {
+	apiKeyAuthenticator = authenticator
+}
 
 func ApiKeyMiddleware() gin.HandlerFunc {
-	expectedKey := os.Getenv("API_KEY")
-	println("Expected API Key:", expectedKey)
-	if expectedKey == "" {
-		expectedKey = "secret-key" // Default value for local development
+	// The single API_KEY value is still accepted so existing callers keep working while they move to managed keys
+	legacyKey := os.Getenv("API_KEY")
+	if legacyKey != "" {
+		loggers.Log.Warn().Msg("API_KEY is deprecated, create a managed key with `make api-key` or via /api/v1/api-keys instead")
 	}
 
 	return func(ctx *gin.Context) {
+		// Public discovery documents such as the JWKS must be readable by other services
+		if strings.HasPrefix(ctx.Request.URL.Path, "/.well-known/") {
+			ctx.Next()
+			return
+		}
+
+		if slices.Contains(oauthClientPaths, ctx.Request.URL.Path) {
+			ctx.Next()
+			return
+		}
+
+		if ctx.GetBool("request_signed") {
+			ctx.Next()
+			return
+		}
+
 		apiKey := ctx.GetHeader("X-API-KEY")
 		if apiKey == "" {
 			ctx.AbortWithStatusJSON(401, gin.H{"error": "API Key is required"})
 			return
 		}
 
-		if apiKey != expectedKey {
+		if legacyKey != "" && subtle.ConstantTimeCompare([]byte(apiKey), []byte(legacyKey)) == 1 {
+			ctx.Set("api_key_id", legacyAPIKeyID)
+			ctx.Set("api_key_name", legacyAPIKeyID)
+			ctx.Next()
+			return
+		}
+
+		key, err := apiKeyAuthenticator.AuthenticateAPIKey(ctx.Request.Context(), apiKey)
+		if err != nil {
 			ctx.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
 			return
 		}
+
+		if !key.Allows(apiKeyScopeForPath(ctx.Request.URL.Path)) {
+			ctx.AbortWithStatusJSON(403, gin.H{"error": "API key is not allowed to access this resource"})
+			return
+		}
+
+		ctx.Set("api_key_id", key.ID)
+		ctx.Set("api_key_name", key.Name)
+		ctx.Set("api_key_scopes", key.Scopes)
 		ctx.Next()
 	}
 }
+
+// apiKeyScopeForPath maps a request to its route group, the first segment after /api/v1
+func apiKeyScopeForPath(path string) string {
+	group, _, _ := strings.Cut(strings.TrimPrefix(path, "/api/v1/"), "/")
+	return group
+}
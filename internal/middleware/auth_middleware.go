@@ -2,9 +2,11 @@ package middleware
 
 import (
 	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/internal/utils"
 	"gin/user-management-api/pkg/cache"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,11 +15,13 @@ import (
 var (
 	jwtService auth.TokenService
 	cacheService cache.RedisCacheService
+	userStatusChecker auth.UserStatusChecker
 )
 
-func InitAuthMiddleware(service auth.TokenService, cache cache.RedisCacheService ){
+func InitAuthMiddleware(service auth.TokenService, cache cache.RedisCacheService, statusChecker auth.UserStatusChecker){
 	jwtService = service
 	cacheService = cache
+	userStatusChecker = statusChecker
 }
 
 func AuthMiddleware() gin.HandlerFunc {
@@ -58,9 +62,45 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Client credential tokens do not belong to a user
+		if payload.UserUUID != "" && userStatusChecker != nil {
+			issuedAt, _ := claims["iat"].(float64)
+			if err := userStatusChecker.CheckUserStatus(ctx.Request.Context(), payload.UserUUID, time.Unix(int64(issuedAt), 0)); err != nil {
+				utils.ResponseError(ctx, err)
+				ctx.Abort()
+				return
+			}
+		}
+
 		ctx.Set("user_uuid", payload.UserUUID)
 		ctx.Set("user_email", payload.Email)
 		ctx.Set("user_role", payload.Role)
+		ctx.Set("user_permissions", payload.Permissions)
+		ctx.Set("session_id", payload.SessionID)
+		ctx.Set("client_id", payload.ClientID)
+		ctx.Set("token_scopes", payload.Scopes)
+		if jti, ok := claims["jti"].(string); ok {
+			ctx.Set("token_jti", jti)
+		}
+		if payload.Actor != nil {
+			ctx.Set("actor_uuid", payload.Actor.UserUUID)
+			ctx.Set("actor_email", payload.Actor.Email)
+		}
+
+		// OAuth client tokens are denied by default, they only reach routes guarded by a permission
+		// and RequirePermission then checks that permission against the granted scopes
+		if payload.ClientID != "" && !hasScopeGuard(ctx) {
+			utils.ResponseError(ctx, utils.NewError(utils.ForbiddenError, "The token scope does not allow access to this resource"))
+			ctx.Abort()
+			return
+		}
+
+		// Impersonation tokens are read-only, only the routes in impersonationAllowedRoutes may change anything
+		if payload.Actor != nil && !impersonationAllowed(ctx) {
+			utils.ResponseError(ctx, utils.NewError(utils.ForbiddenError, "This action is not allowed while impersonating a user"))
+			ctx.Abort()
+			return
+		}
 
 		ctx.Next()
 	}
@@ -31,7 +31,7 @@ func LoggerMiddleware(logger *zerolog.Logger) gin.HandlerFunc {
 		requestBody := make(map[string]any)
 		var formFiles []map[string]any
 		var sensitiveFields = []string{
-			"password", "pass", "new_password",
+			"password", "pass", "new_password", "current_password", "code", "client_secret", "code_verifier", "device_token", "token", "refresh_token",
 		}
 
 		// multipart/form-data
@@ -105,7 +105,7 @@ func LoggerMiddleware(logger *zerolog.Logger) gin.HandlerFunc {
 		responseBodyRaw := customWriter.body.String()
 		var responseBodyParsed interface{}
 
-		if strings.HasPrefix(responseContentType, "image/") {
+		if strings.HasPrefix(responseContentType, "image/") || strings.HasPrefix(responseContentType, "application/zip") {
 			responseBodyParsed = "[BINARY DATA]"
 		} else if strings.HasPrefix(responseContentType, "application/json") ||
 			strings.HasPrefix(strings.TrimSpace(responseBodyRaw), "{") ||
@@ -124,18 +124,27 @@ func LoggerMiddleware(logger *zerolog.Logger) gin.HandlerFunc {
 			logEvent = logger.Warn()
 		}
 
+		query := senitizeQuery(ctx.Request.URL.RawQuery, sensitiveFields)
+		requestURI := ctx.Request.URL.Path
+		if query != "" {
+			requestURI += "?" + query
+		}
+
 		logEvent.
 			Str("trace_id", loggers.GetTraceID(ctx.Request.Context())).
 			Str("method", ctx.Request.Method).
 			Str("path", ctx.Request.URL.Path).
-			Str("query", ctx.Request.URL.RawQuery).
+			Str("query", query).
 			Str("client_ip", ctx.ClientIP()).
 			Str("user_agent", ctx.Request.UserAgent()).
 			Str("referer", ctx.Request.Referer()).
 			Str("protocol", ctx.Request.Proto).
 			Str("host", ctx.Request.Host).
 			Str("remote_addr", ctx.Request.RemoteAddr).
-			Str("request_uri", ctx.Request.RequestURI).
+			Str("user_uuid", ctx.GetString("user_uuid")).
+			Str("actor_uuid", ctx.GetString("actor_uuid")).
+			Str("api_key_id", ctx.GetString("api_key_id")).
+			Str("request_uri", requestURI).
 			Int64("content_length", ctx.Request.ContentLength).
 			Interface("headers", ctx.Request.Header).
 			Interface("request_body", senitizeRequest(requestBody, sensitiveFields)).
@@ -191,3 +200,26 @@ func senitizeRequest(data map[string]any, sensitiveKeys []string) map[string]any
 	}
 	return sensitized
 }
+
+// senitizeQuery masks sensitive query parameters such as the token of emailed download links
+func senitizeQuery(rawQuery string, sensitiveKeys []string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); err == nil {
+			key = name
+		}
+		lowerKey := strings.ToLower(key)
+		for _, s := range sensitiveKeys {
+			if lowerKey == s {
+				params[i] = key + "=******"
+				break
+			}
+		}
+	}
+	return strings.Join(params, "&")
+}
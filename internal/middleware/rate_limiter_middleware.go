@@ -65,30 +65,19 @@ func CleanupClients() {
 // ab -n 20 -c 1 -H "X-API-Key:2a2cc361-9801-4036-8200-3088e14a403e" http://localhost:8080/api/v1/users
 func RateLimiterMiddleware(rateLimiterLogger *zerolog.Logger) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		ip := getClientIP(ctx)
-
-		limiter := getRateLimiter(ip)
-
-		if !limiter.Allow() {
-			if shouldLogRateLimit(ip) {
-				rateLimiterLogger.Warn().
-				Str("method", ctx.Request.Method).
-				Str("path", ctx.Request.URL.Path).
-				Str("query", ctx.Request.URL.RawQuery).
-				Str("client_ip", ctx.ClientIP()).
-				Str("user_agent", ctx.Request.UserAgent()).
-				Str("referer", ctx.Request.Referer()).
-				Str("protocol", ctx.Request.Host).
-				Str("host", ctx.Request.Method).
-				Str("remote_addr", ctx.Request.RemoteAddr).
-				Interface("headers", ctx.Request.Header).
-				Msg("rate limiter execested")
-			}
+		if !allowRequest(ctx, getClientIP(ctx), rateLimiterLogger) {
+			return
+		}
+
+		ctx.Next()
+	}
+}
 
-			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error":   "Too many request",
-				"message": "Bạn đã gửi quá nhiêu request. Hãy thử lại sau",
-			})
+// ApiKeyRateLimiterMiddleware gives every API key its own bucket, it must run after ApiKeyMiddleware
+func ApiKeyRateLimiterMiddleware(rateLimiterLogger *zerolog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		keyID := ctx.GetString("api_key_id")
+		if keyID != "" && !allowRequest(ctx, "api_key:"+keyID, rateLimiterLogger) {
 			return
 		}
 
@@ -96,6 +85,36 @@ func RateLimiterMiddleware(rateLimiterLogger *zerolog.Logger) gin.HandlerFunc {
 	}
 }
 
+func allowRequest(ctx *gin.Context, key string, rateLimiterLogger *zerolog.Logger) bool {
+	limiter := getRateLimiter(key)
+
+	if !limiter.Allow() {
+		if shouldLogRateLimit(key) {
+			rateLimiterLogger.Warn().
+			Str("method", ctx.Request.Method).
+			Str("path", ctx.Request.URL.Path).
+			Str("query", ctx.Request.URL.RawQuery).
+			Str("client_ip", ctx.ClientIP()).
+			Str("api_key_id", ctx.GetString("api_key_id")).
+			Str("user_agent", ctx.Request.UserAgent()).
+			Str("referer", ctx.Request.Referer()).
+			Str("protocol", ctx.Request.Host).
+			Str("host", ctx.Request.Method).
+			Str("remote_addr", ctx.Request.RemoteAddr).
+			Interface("headers", ctx.Request.Header).
+			Msg("rate limiter execested")
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error":   "Too many request",
+			"message": "Bạn đã gửi quá nhiêu request. Hãy thử lại sau",
+		})
+		return false
+	}
+
+	return true
+}
+
 var rateLimitLogCache = sync.Map{}
 
 const rateLimitLogTTL = 10 * time.Second
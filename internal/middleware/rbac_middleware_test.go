@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"errors"
+	"gin/user-management-api/pkg/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// payloadTokens maps the bearer token straight to its payload
+type payloadTokens struct {
+	auth.TokenService
+	payloads map[string]*auth.EncryptedPayload
+}
+
+func (p *payloadTokens) ParseToken(tokenString string) (*jwt.Token, jwt.MapClaims, error) {
+	if _, ok := p.payloads[tokenString]; !ok {
+		return nil, nil, errors.New("unknown token")
+	}
+	return &jwt.Token{Valid: true}, jwt.MapClaims{}, nil
+}
+
+func (p *payloadTokens) DecryptAccessTokenPayload(tokenString string) (*auth.EncryptedPayload, error) {
+	return p.payloads[tokenString], nil
+}
+
+func newAuthRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	InitAuthMiddleware(&payloadTokens{payloads: map[string]*auth.EncryptedPayload{
+		"user":          {UserUUID: "u1", Permissions: []string{string(PermissionUserRead)}},
+		"client":        {ClientID: "c1", Permissions: []string{string(PermissionUserRead), string(PermissionRoleManage)}, Scopes: []string{string(PermissionUserRead)}},
+		"impersonation": {UserUUID: "u1", Permissions: []string{string(PermissionUserRead)}, Actor: &auth.Actor{UserUUID: "admin"}},
+	}}, nil, nil)
+	t.Cleanup(func() { InitAuthMiddleware(nil, nil, nil) })
+
+	// Registered the same ways as internal/routes: inline, through Use on a group and not guarded at all
+	r := gin.New()
+	handler := func(ctx *gin.Context) { ctx.Status(http.StatusNoContent) }
+	api := r.Group("/api/v1", AuthMiddleware())
+	api.GET("/users", RequirePermission(PermissionUserRead), handler)
+	api.GET("/profile", handler)
+	api.GET("/mfa", DenyImpersonation(), handler)
+	api.POST("/impersonation/stop", handler)
+	roles := api.Group("/roles")
+	roles.Use(RequirePermission(PermissionRoleManage))
+	roles.GET("", handler)
+	return r
+}
+
+func request(r *gin.Engine, method, target, token string) int {
+	req := httptest.NewRequest(method, target, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return serve(r, req)
+}
+
+func TestAuthMiddlewareClientTokens(t *testing.T) {
+	r := newAuthRouter(t)
+
+	cases := []struct {
+		name   string
+		target string
+		want   int
+	}{
+		{"guarded route within scope", "/api/v1/users", http.StatusNoContent},
+		{"guarded by the group outside scope", "/api/v1/roles", http.StatusForbidden},
+		{"route without a permission", "/api/v1/profile", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if code := request(r, http.MethodGet, tc.target, "client"); code != tc.want {
+				t.Errorf("status = %d, want %d", code, tc.want)
+			}
+		})
+	}
+
+	// User tokens are not limited by scopes
+	if code := request(r, http.MethodGet, "/api/v1/profile", "user"); code != http.StatusNoContent {
+		t.Errorf("user token status = %d, want %d", code, http.StatusNoContent)
+	}
+}
+
+func TestHasScopeGuard(t *testing.T) {
+	// hasScopeGuard is asked from a middleware in front, like AuthMiddleware does
+	var guarded bool
+	r := gin.New()
+	r.Use(func(ctx *gin.Context) { guarded = hasScopeGuard(ctx) })
+	handler := func(ctx *gin.Context) {}
+	r.GET("/inline", RequirePermission(PermissionUserRead), handler)
+	r.GET("/open", handler)
+	group := r.Group("/group")
+	group.Use(RequirePermission(PermissionUserRead))
+	group.GET("", handler)
+
+	for target, want := range map[string]bool{"/inline": true, "/open": false, "/group": true} {
+		guarded = !want
+		serve(r, httptest.NewRequest(http.MethodGet, target, nil))
+		if guarded != want {
+			t.Errorf("hasScopeGuard(%s) = %v, want %v", target, guarded, want)
+		}
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	cases := []struct {
+		name        string
+		permissions []string
+		clientID    string
+		scopes      []string
+		want        int
+	}{
+		{"user with permission", []string{"users:read"}, "", nil, http.StatusNoContent},
+		{"user without permission", []string{"roles:manage"}, "", nil, http.StatusForbidden},
+		{"client with permission and scope", []string{"users:read"}, "c1", []string{"users:read"}, http.StatusNoContent},
+		{"client with permission outside scope", []string{"users:read"}, "c1", []string{"roles:manage"}, http.StatusForbidden},
+		{"client with scope but no permission", nil, "c1", []string{"users:read"}, http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/", func(ctx *gin.Context) {
+				ctx.Set("user_permissions", tc.permissions)
+				ctx.Set("client_id", tc.clientID)
+				ctx.Set("token_scopes", tc.scopes)
+			}, RequirePermission(PermissionUserRead), func(ctx *gin.Context) { ctx.Status(http.StatusNoContent) })
+
+			if code := serve(r, httptest.NewRequest(http.MethodGet, "/", nil)); code != tc.want {
+				t.Errorf("status = %d, want %d", code, tc.want)
+			}
+		})
+	}
+}
+
+func TestImpersonationTokens(t *testing.T) {
+	r := newAuthRouter(t)
+
+	if code := request(r, http.MethodGet, "/api/v1/profile", "impersonation"); code != http.StatusNoContent {
+		t.Errorf("read status = %d, want %d", code, http.StatusNoContent)
+	}
+	if code := request(r, http.MethodGet, "/api/v1/mfa", "impersonation"); code != http.StatusForbidden {
+		t.Errorf("DenyImpersonation status = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := request(r, http.MethodGet, "/api/v1/mfa", "user"); code != http.StatusNoContent {
+		t.Errorf("DenyImpersonation blocked a user token, status = %d", code)
+	}
+	if code := request(r, http.MethodPost, "/api/v1/impersonation/stop", "impersonation"); code != http.StatusNoContent {
+		t.Errorf("stop status = %d, want %d", code, http.StatusNoContent)
+	}
+}
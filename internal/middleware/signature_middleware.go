@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/loggers"
+	"gin/user-management-api/pkg/signing"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errReplayedRequest = errors.New("request nonce was already used")
+
+// RequestSignatureMiddleware verifies signed server-to-server requests. Requests without the
+// signature headers are left to ApiKeyMiddleware, signed requests do not need an X-API-KEY.
+// They stand in for an API key, so REQUEST_SIGNING_KEY_SCOPES limits the route groups of each
+// signing key like the scopes of a managed key, as "key_id:scope scope,...". Keys that are not
+// listed there may call every route group, like API keys created without scopes.
+func RequestSignatureMiddleware(cache cache.RedisCacheService) gin.HandlerFunc {
+	secrets, err := signing.ParseSecrets(utils.GetEnv("REQUEST_SIGNING_KEYS", ""))
+	if err != nil {
+		loggers.Log.Fatal().Err(err).Msg("Failed to load request signing keys")
+	}
+	scopes, err := parseSigningScopes(utils.GetEnv("REQUEST_SIGNING_KEY_SCOPES", ""))
+	if err != nil {
+		loggers.Log.Fatal().Err(err).Msg("Failed to load request signing key scopes")
+	}
+	maxSkew := time.Duration(utils.GetIntEnv("REQUEST_SIGNING_MAX_SKEW_SEC", 300)) * time.Second
+	maxBody := int64(utils.GetIntEnv("REQUEST_SIGNING_MAX_BODY_BYTES", 1<<20))
+
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader(signing.HeaderSignature) == "" {
+			ctx.Next()
+			return
+		}
+
+		sig, err := signing.FromHeaders(ctx.Request.Header)
+		if err != nil {
+			abortSignature(ctx, err)
+			return
+		}
+
+		if err := sig.CheckTimestamp(time.Now(), maxSkew); err != nil {
+			abortSignature(ctx, err)
+			return
+		}
+
+		secret, ok := secrets[sig.KeyID]
+		if !ok {
+			abortSignature(ctx, signing.ErrUnknownKey)
+			return
+		}
+
+		body, err := signing.ReadBody(ctx.Request, maxBody)
+		if errors.Is(err, signing.ErrBodyTooLarge) {
+			ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body is too large"})
+			return
+		}
+		if err != nil {
+			abortSignature(ctx, err)
+			return
+		}
+
+		if err := sig.Verify(secret, ctx.Request.Method, ctx.Request.URL.EscapedPath(), ctx.Request.URL.RawQuery, body); err != nil {
+			abortSignature(ctx, err)
+			return
+		}
+
+		// A nonce is only remembered once the signature is valid, it has to outlive the accepted skew on both sides
+		count, err := cache.Increment("request_nonce:"+sig.KeyID+":"+sig.Nonce, 2*maxSkew)
+		if err != nil {
+			loggers.Log.Error().Err(err).Msg("Failed to store request nonce")
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify request signature"})
+			return
+		}
+		if count > 1 {
+			abortSignature(ctx, errReplayedRequest)
+			return
+		}
+
+		key := auth.APIKey{ID: "signing:" + sig.KeyID, Name: sig.KeyID, Scopes: scopes[sig.KeyID]}
+		if !key.Allows(apiKeyScopeForPath(ctx.Request.URL.Path)) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Signing key is not allowed to access this resource"})
+			return
+		}
+
+		ctx.Set("request_signed", true)
+		ctx.Set("api_key_id", key.ID)
+		ctx.Set("api_key_name", key.Name)
+		ctx.Set("api_key_scopes", key.Scopes)
+		ctx.Next()
+	}
+}
+
+// parseSigningScopes reads "key_id:scope scope" entries separated by commas
+func parseSigningScopes(value string) (map[string][]string, error) {
+	scopes := make(map[string][]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		keyID, list, ok := strings.Cut(entry, ":")
+		keyID = strings.TrimSpace(keyID)
+		if !ok || keyID == "" || strings.TrimSpace(list) == "" {
+			return nil, fmt.Errorf("signing key scopes %q must be in the form key_id:scope scope", entry)
+		}
+		for _, scope := range strings.Fields(list) {
+			if !slices.Contains(auth.APIKeyScopes, scope) {
+				return nil, fmt.Errorf("signing key %q has an unknown scope %q", keyID, scope)
+			}
+			scopes[keyID] = append(scopes[keyID], scope)
+		}
+	}
+	return scopes, nil
+}
+
+func abortSignature(ctx *gin.Context, err error) {
+	loggers.Log.Warn().
+		Err(err).
+		Str("event", "request_signature_rejected").
+		Str("key_id", ctx.GetHeader(signing.HeaderKeyID)).
+		Str("client_ip", ctx.ClientIP()).
+		Str("path", ctx.Request.URL.Path).
+		Msg("Request signature rejected")
+
+	ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid request signature"})
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/loggers"
+	"gin/user-management-api/pkg/signing"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+const testSigningSecret = "0123456789abcdef0123456789abcdef"
+
+func TestMain(m *testing.M) {
+	logger := zerolog.Nop()
+	loggers.Log = &logger
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// nonceCache only keeps the counters the signature middleware uses
+type nonceCache struct {
+	cache.RedisCacheService
+	counters map[string]int64
+}
+
+func (c *nonceCache) Increment(key string, ttl time.Duration) (int64, error) {
+	c.counters[key]++
+	return c.counters[key], nil
+}
+
+func newSignedRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	t.Setenv("REQUEST_SIGNING_KEYS", "billing:"+testSigningSecret+",reports:"+testSigningSecret)
+	t.Setenv("REQUEST_SIGNING_KEY_SCOPES", "reports:users")
+	t.Setenv("REQUEST_SIGNING_MAX_BODY_BYTES", "64")
+
+	r := gin.New()
+	r.Use(RequestSignatureMiddleware(&nonceCache{counters: make(map[string]int64)}))
+	handler := func(ctx *gin.Context) { ctx.Status(http.StatusNoContent) }
+	r.POST("/api/v1/users", handler)
+	r.POST("/api/v1/roles", handler)
+	return r
+}
+
+func sign(t *testing.T, req *http.Request, keyID string, now time.Time) *http.Request {
+	t.Helper()
+	signer := signing.NewSigner(keyID, []byte(testSigningSecret))
+	signer.Now = func() time.Time { return now }
+	if err := signer.Sign(req); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	return req
+}
+
+func serve(r *gin.Engine, req *http.Request) int {
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestRequestSignatureRejectsReplayedNonce(t *testing.T) {
+	r := newSignedRouter(t)
+
+	req := sign(t, httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(`{}`)), "billing", time.Now())
+	replay := httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(`{}`))
+	replay.Header = req.Header.Clone()
+
+	if code := serve(r, req); code != http.StatusNoContent {
+		t.Fatalf("first request status = %d", code)
+	}
+	if code := serve(r, replay); code != http.StatusUnauthorized {
+		t.Errorf("replayed request status = %d, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+func TestRequestSignatureRejectsStaleTimestamp(t *testing.T) {
+	r := newSignedRouter(t)
+
+	req := sign(t, httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(`{}`)), "billing", time.Now().Add(-10*time.Minute))
+	if code := serve(r, req); code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+func TestRequestSignatureLimitsBody(t *testing.T) {
+	r := newSignedRouter(t)
+
+	body := strings.Repeat("x", 65)
+	req := sign(t, httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(body)), "billing", time.Now())
+	if code := serve(r, req); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestRequestSignatureEnforcesKeyScopes(t *testing.T) {
+	r := newSignedRouter(t)
+
+	allowed := sign(t, httptest.NewRequest(http.MethodPost, "/api/v1/users", nil), "reports", time.Now())
+	if code := serve(r, allowed); code != http.StatusNoContent {
+		t.Errorf("in scope status = %d, want %d", code, http.StatusNoContent)
+	}
+
+	denied := sign(t, httptest.NewRequest(http.MethodPost, "/api/v1/roles", nil), "reports", time.Now())
+	if code := serve(r, denied); code != http.StatusForbidden {
+		t.Errorf("out of scope status = %d, want %d", code, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gin/user-management-api/internal/db"
+	"gin/user-management-api/internal/db/sqlc"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type SqlAccountClosureRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlAccountClosureRepository(db sqlc.Querier) AccountClosureRepository {
+	return &SqlAccountClosureRepository{
+		db: db,
+	}
+}
+
+// Close soft deletes the user and schedules the anonymization in one transaction
+func (cr *SqlAccountClosureRepository) Close(ctx context.Context, userUuid uuid.UUID, purgeAt time.Time) (sqlc.AccountClosure, error) {
+	tx, err := db.DBpool.Begin(ctx)
+	if err != nil {
+		return sqlc.AccountClosure{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+	user, err := qtx.SoftDeleteUser(ctx, userUuid)
+	if err != nil {
+		return sqlc.AccountClosure{}, err
+	}
+
+	closure, err := qtx.CreateAccountClosure(ctx, sqlc.CreateAccountClosureParams{
+		UserID:         user.UserID,
+		ClosurePurgeAt: purgeAt,
+	})
+	if err != nil {
+		return sqlc.AccountClosure{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.AccountClosure{}, err
+	}
+	return closure, nil
+}
+
+func (cr *SqlAccountClosureRepository) FindClosedUserByEmail(ctx context.Context, email string) (sqlc.User, error) {
+	user, err := cr.db.GetClosedUserByEmail(ctx, email)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+	return user, nil
+}
+
+func (cr *SqlAccountClosureRepository) FindClosedUserByUUID(ctx context.Context, userUuid uuid.UUID) (sqlc.User, error) {
+	user, err := cr.db.GetClosedUserByUuid(ctx, userUuid)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+	return user, nil
+}
+
+func (cr *SqlAccountClosureRepository) Restore(ctx context.Context, user sqlc.User) (sqlc.User, error) {
+	tx, err := db.DBpool.Begin(ctx)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+	restored, err := qtx.RestoreUser(ctx, user.UserUuid)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+
+	if err := qtx.DeleteAccountClosure(ctx, user.UserID); err != nil {
+		return sqlc.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.User{}, err
+	}
+	return restored, nil
+}
+
+func (cr *SqlAccountClosureRepository) GetDue(ctx context.Context, limit int32) ([]sqlc.AccountClosure, error) {
+	closures, err := cr.db.ListDueAccountClosures(ctx, limit)
+	if err != nil {
+		return []sqlc.AccountClosure{}, err
+	}
+	return closures, nil
+}
+
+// Anonymize replaces the personal data of a closed account and removes its credentials,
+// it returns false when the account was restored by an administrator in the meantime
+func (cr *SqlAccountClosureRepository) Anonymize(ctx context.Context, userID int32) (bool, error) {
+	tx, err := db.DBpool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+	if err := qtx.DeleteUserInvitations(ctx, userID); err != nil {
+		return false, err
+	}
+
+	if _, err := qtx.AnonymizeClosedUser(ctx, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			tx.Rollback(ctx)
+			return false, cr.db.DeleteAccountClosure(ctx, userID)
+		}
+		return false, err
+	}
+
+	if err := qtx.DeleteUserApiKeys(ctx, userID); err != nil {
+		return false, err
+	}
+	// The directory DN names the person, a later directory login provisions a new account
+	if err := qtx.DeleteUserIdentity(ctx, userID); err != nil {
+		return false, err
+	}
+	if err := qtx.DeleteUserMfa(ctx, userID); err != nil {
+		return false, err
+	}
+	if err := qtx.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return false, err
+	}
+	if err := qtx.DeleteUserOAuthConsents(ctx, userID); err != nil {
+		return false, err
+	}
+	if err := qtx.DeleteUserPasswordHistory(ctx, userID); err != nil {
+		return false, err
+	}
+	if err := qtx.AnonymizeUserSecurityEvents(ctx, &userID); err != nil {
+		return false, err
+	}
+	if err := qtx.AnonymizeUserAuditLogs(ctx, &userID); err != nil {
+		return false, err
+	}
+	if err := qtx.MarkAccountClosureAnonymized(ctx, userID); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"gin/user-management-api/internal/db/sqlc"
+
+	"github.com/google/uuid"
+)
+
+type SqlApiKeyRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlApiKeyRepository(db sqlc.Querier) ApiKeyRepository {
+	return &SqlApiKeyRepository{
+		db: db,
+	}
+}
+
+func (ar *SqlApiKeyRepository) Create(ctx context.Context, keyParams sqlc.CreateApiKeyParams) (sqlc.ApiKey, error) {
+	key, err := ar.db.CreateApiKey(ctx, keyParams)
+	if err != nil {
+		return sqlc.ApiKey{}, err
+	}
+	return key, nil
+}
+
+func (ar *SqlApiKeyRepository) FindByHash(ctx context.Context, hash string) (sqlc.ApiKey, error) {
+	key, err := ar.db.GetApiKeyByHash(ctx, hash)
+	if err != nil {
+		return sqlc.ApiKey{}, err
+	}
+	return key, nil
+}
+
+func (ar *SqlApiKeyRepository) FindByUUID(ctx context.Context, keyUuid uuid.UUID) (sqlc.ApiKey, error) {
+	key, err := ar.db.GetApiKeyByUuid(ctx, keyUuid)
+	if err != nil {
+		return sqlc.ApiKey{}, err
+	}
+	return key, nil
+}
+
+func (ar *SqlApiKeyRepository) FindOwnerUUID(ctx context.Context, ownerID int32) (uuid.UUID, error) {
+	ownerUuid, err := ar.db.GetApiKeyOwnerUuid(ctx, ownerID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return ownerUuid, nil
+}
+
+func (ar *SqlApiKeyRepository) GetAll(ctx context.Context) ([]sqlc.ApiKey, error) {
+	keys, err := ar.db.ListApiKeys(ctx)
+	if err != nil {
+		return []sqlc.ApiKey{}, err
+	}
+	return keys, nil
+}
+
+func (ar *SqlApiKeyRepository) Rotate(ctx context.Context, keyUuid uuid.UUID, prefix, hash string) (sqlc.ApiKey, error) {
+	key, err := ar.db.RotateApiKey(ctx, sqlc.RotateApiKeyParams{
+		ApiKeyUuid:   keyUuid,
+		ApiKeyPrefix: prefix,
+		ApiKeyHash:   hash,
+	})
+	if err != nil {
+		return sqlc.ApiKey{}, err
+	}
+	return key, nil
+}
+
+func (ar *SqlApiKeyRepository) Revoke(ctx context.Context, keyUuid uuid.UUID) (sqlc.ApiKey, error) {
+	key, err := ar.db.RevokeApiKey(ctx, keyUuid)
+	if err != nil {
+		return sqlc.ApiKey{}, err
+	}
+	return key, nil
+}
+
+func (ar *SqlApiKeyRepository) TouchLastUsed(ctx context.Context, keyID int32) error {
+	return ar.db.TouchApiKeyLastUsed(ctx, keyID)
+}
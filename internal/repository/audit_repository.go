@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"gin/user-management-api/internal/db/sqlc"
+)
+
+type SqlAuditRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlAuditRepository(db sqlc.Querier) AuditRepository {
+	return &SqlAuditRepository{
+		db: db,
+	}
+}
+
+func (ar *SqlAuditRepository) Create(ctx context.Context, auditParams sqlc.CreateAuditLogParams) error {
+	return ar.db.CreateAuditLog(ctx, auditParams)
+}
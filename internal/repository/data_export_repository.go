@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"gin/user-management-api/internal/db/sqlc"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type SqlDataExportRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlDataExportRepository(db sqlc.Querier) DataExportRepository {
+	return &SqlDataExportRepository{
+		db: db,
+	}
+}
+
+func (er *SqlDataExportRepository) Create(ctx context.Context, userID int32) (sqlc.DataExport, error) {
+	export, err := er.db.CreateDataExport(ctx, userID)
+	if err != nil {
+		return sqlc.DataExport{}, err
+	}
+	return export, nil
+}
+
+func (er *SqlDataExportRepository) FindByUUID(ctx context.Context, exportUuid uuid.UUID) (sqlc.DataExport, error) {
+	export, err := er.db.GetDataExportByUuid(ctx, exportUuid)
+	if err != nil {
+		return sqlc.DataExport{}, err
+	}
+	return export, nil
+}
+
+func (er *SqlDataExportRepository) FindByTokenHash(ctx context.Context, hash string) (sqlc.DataExport, error) {
+	export, err := er.db.GetDataExportByTokenHash(ctx, &hash)
+	if err != nil {
+		return sqlc.DataExport{}, err
+	}
+	return export, nil
+}
+
+func (er *SqlDataExportRepository) Complete(ctx context.Context, exportID int32, hash string, archive []byte, expiresAt time.Time) (sqlc.DataExport, error) {
+	export, err := er.db.CompleteDataExport(ctx, sqlc.CompleteDataExportParams{
+		ExportID:        exportID,
+		ExportTokenHash: &hash,
+		ExportArchive:   archive,
+		ExportExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		return sqlc.DataExport{}, err
+	}
+	return export, nil
+}
+
+func (er *SqlDataExportRepository) Fail(ctx context.Context, exportID int32, reason string) error {
+	return er.db.FailDataExport(ctx, sqlc.FailDataExportParams{
+		ExportID:    exportID,
+		ExportError: reason,
+	})
+}
+
+func (er *SqlDataExportRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	return er.db.DeleteExpiredDataExports(ctx, before)
+}
+
+func (er *SqlDataExportRepository) GetApiKeys(ctx context.Context, userID int32) ([]sqlc.ApiKey, error) {
+	keys, err := er.db.ListApiKeysByOwner(ctx, userID)
+	if err != nil {
+		return []sqlc.ApiKey{}, err
+	}
+	return keys, nil
+}
+
+func (er *SqlDataExportRepository) GetAuditLogs(ctx context.Context, userID int32) ([]sqlc.AuditLog, error) {
+	logs, err := er.db.ListAuditLogsByUser(ctx, &userID)
+	if err != nil {
+		return []sqlc.AuditLog{}, err
+	}
+	return logs, nil
+}
+
+func (er *SqlDataExportRepository) GetOAuthConsents(ctx context.Context, userID int32) ([]sqlc.OauthConsent, error) {
+	consents, err := er.db.ListOAuthConsentsByUser(ctx, userID)
+	if err != nil {
+		return []sqlc.OauthConsent{}, err
+	}
+	return consents, nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"gin/user-management-api/internal/db"
+	"gin/user-management-api/internal/db/sqlc"
+)
+
+type SqlIdentityRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlIdentityRepository(db sqlc.Querier) IdentityRepository {
+	return &SqlIdentityRepository{
+		db: db,
+	}
+}
+
+func (ir *SqlIdentityRepository) FindByUserID(ctx context.Context, userID int32) (sqlc.UserIdentity, error) {
+	identity, err := ir.db.GetUserIdentity(ctx, userID)
+	if err != nil {
+		return sqlc.UserIdentity{}, err
+	}
+	return identity, nil
+}
+
+func (ir *SqlIdentityRepository) FindUser(ctx context.Context, provider, subject string) (sqlc.User, error) {
+	user, err := ir.db.GetUserByIdentity(ctx, sqlc.GetUserByIdentityParams{
+		IdentityProvider: provider,
+		IdentitySubject:  subject,
+	})
+	if err != nil {
+		return sqlc.User{}, err
+	}
+	return user, nil
+}
+
+// Provision creates the user together with its link to the directory in one transaction
+func (ir *SqlIdentityRepository) Provision(ctx context.Context, userParams sqlc.CreateUserParams, provider, subject string) (sqlc.User, error) {
+	tx, err := db.DBpool.Begin(ctx)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+	user, err := qtx.CreateUser(ctx, userParams)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+
+	if _, err := qtx.CreateUserIdentity(ctx, sqlc.CreateUserIdentityParams{
+		UserID:           user.UserID,
+		IdentityProvider: provider,
+		IdentitySubject:  subject,
+	}); err != nil {
+		return sqlc.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.User{}, err
+	}
+	return user, nil
+}
+
+// Sync copies the name and level from the directory and records when it happened
+func (ir *SqlIdentityRepository) Sync(ctx context.Context, userID int32, fullname string, level int32) (sqlc.User, error) {
+	tx, err := db.DBpool.Begin(ctx)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+	user, err := qtx.UpdateIdentityUser(ctx, sqlc.UpdateIdentityUserParams{
+		UserID:       userID,
+		UserFullname: fullname,
+		UserLevel:    level,
+	})
+	if err != nil {
+		return sqlc.User{}, err
+	}
+
+	if err := qtx.TouchUserIdentity(ctx, userID); err != nil {
+		return sqlc.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.User{}, err
+	}
+	return user, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"gin/user-management-api/internal/db/sqlc"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,10 +14,109 @@ type UserRepository interface {
 	CountUsers(ctx context.Context, search string, deleted bool) (int64, error)
 	Create(ctx context.Context, userParams sqlc.CreateUserParams) (sqlc.User, error)
 	FindByUUID(ctx context.Context, userUuid uuid.UUID) (sqlc.User, error)
-	Update(ctx context.Context, userParams sqlc.UpdateUserByUuidParams) (sqlc.User, error)
+	Update(ctx context.Context, userParams sqlc.UpdateUserByUuidParams, historySize int32) (sqlc.User, error)
 	SoftDelete(ctx context.Context, userUuid uuid.UUID) (sqlc.User, error)
 	Restore(ctx context.Context, userUuid uuid.UUID) (sqlc.User, error)
 	Delete(ctx context.Context, userUuid uuid.UUID) (sqlc.User, error)
 	GetByEmail(ctx context.Context, email string) (sqlc.User, error)
-	UpdatePassword(ctx context.Context, input sqlc.UpdatePasswordParams) (sqlc.User, error)
+	UpdatePassword(ctx context.Context, input sqlc.UpdatePasswordParams, historySize int32) (sqlc.User, error)
+	UpdatePasswordHash(ctx context.Context, userID int32, passwordHash string) error
+	GetPasswordHistory(ctx context.Context, userID, limit int32) ([]string, error)
+	VerifyEmail(ctx context.Context, userUuid uuid.UUID) (sqlc.User, error)
+	DeletePending(ctx context.Context, userUuid uuid.UUID) error
+	ReplacePending(ctx context.Context, userParams sqlc.CreateUserParams) (sqlc.User, error)
+	UpdateEmail(ctx context.Context, userUuid uuid.UUID, email string) (sqlc.User, error)
+}
+
+type RoleRepository interface {
+	GetAll(ctx context.Context) ([]sqlc.Role, error)
+	FindByID(ctx context.Context, roleID int32) (sqlc.Role, error)
+	Create(ctx context.Context, roleParams sqlc.CreateRoleParams, permissions []string) (sqlc.Role, error)
+	Update(ctx context.Context, roleParams sqlc.UpdateRoleParams, permissions []string) (sqlc.Role, error)
+	Delete(ctx context.Context, roleID int32) (sqlc.Role, error)
+	GetPermissions(ctx context.Context, roleID int32) ([]sqlc.Permission, error)
+	GetAllPermissions(ctx context.Context) ([]sqlc.Permission, error)
+	GetPermissionCodesByUserID(ctx context.Context, userID int32) ([]string, error)
+	AssignUserRole(ctx context.Context, userID, roleID int32) error
+	RemoveUserRole(ctx context.Context, userID, roleID int32) (int64, error)
+}
+
+type MfaRepository interface {
+	FindByUserID(ctx context.Context, userID int32) (sqlc.UserMfa, error)
+	SaveSecret(ctx context.Context, userID int32, secret string) (sqlc.UserMfa, error)
+	Enable(ctx context.Context, userID int32, codeHashes []string) (sqlc.UserMfa, error)
+	Disable(ctx context.Context, userID int32) error
+	ReplaceRecoveryCodes(ctx context.Context, userID int32, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID int32, codeHash string) (bool, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID int32) (int64, error)
+}
+
+type OAuthRepository interface {
+	CreateClient(ctx context.Context, clientParams sqlc.CreateOAuthClientParams) (sqlc.OauthClient, error)
+	FindClientByClientID(ctx context.Context, clientID string) (sqlc.OauthClient, error)
+	GetAllClients(ctx context.Context) ([]sqlc.OauthClient, error)
+	UpdateClientSecret(ctx context.Context, clientID string, secretHash *string) (sqlc.OauthClient, error)
+	DeleteClient(ctx context.Context, clientID string) (int64, error)
+	FindConsent(ctx context.Context, userID, oauthClientID int32) (sqlc.OauthConsent, error)
+	SaveConsent(ctx context.Context, userID, oauthClientID int32, scopes []string) (sqlc.OauthConsent, error)
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, auditParams sqlc.CreateAuditLogParams) error
+}
+
+type ApiKeyRepository interface {
+	Create(ctx context.Context, keyParams sqlc.CreateApiKeyParams) (sqlc.ApiKey, error)
+	FindByHash(ctx context.Context, hash string) (sqlc.ApiKey, error)
+	FindByUUID(ctx context.Context, keyUuid uuid.UUID) (sqlc.ApiKey, error)
+	FindOwnerUUID(ctx context.Context, ownerID int32) (uuid.UUID, error)
+	GetAll(ctx context.Context) ([]sqlc.ApiKey, error)
+	Rotate(ctx context.Context, keyUuid uuid.UUID, prefix, hash string) (sqlc.ApiKey, error)
+	Revoke(ctx context.Context, keyUuid uuid.UUID) (sqlc.ApiKey, error)
+	TouchLastUsed(ctx context.Context, keyID int32) error
+}
+
+type SecurityEventRepository interface {
+	Create(ctx context.Context, eventParams sqlc.CreateSecurityEventParams) error
+	GetAll(ctx context.Context, filter sqlc.ListSecurityEventsParams) ([]sqlc.SecurityEvent, error)
+	Count(ctx context.Context, filter sqlc.ListSecurityEventsParams) (int64, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitationParams sqlc.CreateInvitationParams) (sqlc.UserInvitation, error)
+	FindByTokenHash(ctx context.Context, hash string) (sqlc.UserInvitation, error)
+	FindByUUID(ctx context.Context, invitationUuid uuid.UUID) (sqlc.UserInvitation, error)
+	GetPending(ctx context.Context) ([]sqlc.UserInvitation, error)
+	Renew(ctx context.Context, invitationUuid uuid.UUID, hash string, expiresAt time.Time) (sqlc.UserInvitation, error)
+	Revoke(ctx context.Context, invitationUuid uuid.UUID) (sqlc.UserInvitation, error)
+	Accept(ctx context.Context, invitationID int32, userParams sqlc.CreateUserParams) (sqlc.User, error)
+}
+
+type DataExportRepository interface {
+	Create(ctx context.Context, userID int32) (sqlc.DataExport, error)
+	FindByUUID(ctx context.Context, exportUuid uuid.UUID) (sqlc.DataExport, error)
+	FindByTokenHash(ctx context.Context, hash string) (sqlc.DataExport, error)
+	Complete(ctx context.Context, exportID int32, hash string, archive []byte, expiresAt time.Time) (sqlc.DataExport, error)
+	Fail(ctx context.Context, exportID int32, reason string) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+	GetApiKeys(ctx context.Context, userID int32) ([]sqlc.ApiKey, error)
+	GetAuditLogs(ctx context.Context, userID int32) ([]sqlc.AuditLog, error)
+	GetOAuthConsents(ctx context.Context, userID int32) ([]sqlc.OauthConsent, error)
+}
+
+type AccountClosureRepository interface {
+	Close(ctx context.Context, userUuid uuid.UUID, purgeAt time.Time) (sqlc.AccountClosure, error)
+	FindClosedUserByEmail(ctx context.Context, email string) (sqlc.User, error)
+	FindClosedUserByUUID(ctx context.Context, userUuid uuid.UUID) (sqlc.User, error)
+	Restore(ctx context.Context, user sqlc.User) (sqlc.User, error)
+	GetDue(ctx context.Context, limit int32) ([]sqlc.AccountClosure, error)
+	Anonymize(ctx context.Context, userID int32) (bool, error)
+}
+
+type IdentityRepository interface {
+	FindByUserID(ctx context.Context, userID int32) (sqlc.UserIdentity, error)
+	FindUser(ctx context.Context, provider, subject string) (sqlc.User, error)
+	Provision(ctx context.Context, userParams sqlc.CreateUserParams, provider, subject string) (sqlc.User, error)
+	Sync(ctx context.Context, userID int32, fullname string, level int32) (sqlc.User, error)
 }
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"gin/user-management-api/internal/db"
+	"gin/user-management-api/internal/db/sqlc"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SqlInvitationRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlInvitationRepository(db sqlc.Querier) InvitationRepository {
+	return &SqlInvitationRepository{
+		db: db,
+	}
+}
+
+func (ir *SqlInvitationRepository) Create(ctx context.Context, invitationParams sqlc.CreateInvitationParams) (sqlc.UserInvitation, error) {
+	invitation, err := ir.db.CreateInvitation(ctx, invitationParams)
+	if err != nil {
+		return sqlc.UserInvitation{}, err
+	}
+	return invitation, nil
+}
+
+func (ir *SqlInvitationRepository) FindByTokenHash(ctx context.Context, hash string) (sqlc.UserInvitation, error) {
+	invitation, err := ir.db.GetInvitationByTokenHash(ctx, hash)
+	if err != nil {
+		return sqlc.UserInvitation{}, err
+	}
+	return invitation, nil
+}
+
+func (ir *SqlInvitationRepository) FindByUUID(ctx context.Context, invitationUuid uuid.UUID) (sqlc.UserInvitation, error) {
+	invitation, err := ir.db.GetInvitationByUuid(ctx, invitationUuid)
+	if err != nil {
+		return sqlc.UserInvitation{}, err
+	}
+	return invitation, nil
+}
+
+func (ir *SqlInvitationRepository) GetPending(ctx context.Context) ([]sqlc.UserInvitation, error) {
+	invitations, err := ir.db.ListPendingInvitations(ctx)
+	if err != nil {
+		return []sqlc.UserInvitation{}, err
+	}
+	return invitations, nil
+}
+
+func (ir *SqlInvitationRepository) Renew(ctx context.Context, invitationUuid uuid.UUID, hash string, expiresAt time.Time) (sqlc.UserInvitation, error) {
+	invitation, err := ir.db.RenewInvitation(ctx, sqlc.RenewInvitationParams{
+		InvitationUuid:      invitationUuid,
+		InvitationTokenHash: hash,
+		InvitationExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return sqlc.UserInvitation{}, err
+	}
+	return invitation, nil
+}
+
+func (ir *SqlInvitationRepository) Revoke(ctx context.Context, invitationUuid uuid.UUID) (sqlc.UserInvitation, error) {
+	invitation, err := ir.db.RevokeInvitation(ctx, invitationUuid)
+	if err != nil {
+		return sqlc.UserInvitation{}, err
+	}
+	return invitation, nil
+}
+
+// Accept marks the invitation as used and creates the account in one transaction
+func (ir *SqlInvitationRepository) Accept(ctx context.Context, invitationID int32, userParams sqlc.CreateUserParams) (sqlc.User, error) {
+	tx, err := db.DBpool.Begin(ctx)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+	if _, err := qtx.AcceptInvitation(ctx, invitationID); err != nil {
+		return sqlc.User{}, err
+	}
+
+	user, err := qtx.CreateUser(ctx, userParams)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.User{}, err
+	}
+	return user, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"gin/user-management-api/internal/db"
+	"gin/user-management-api/internal/db/sqlc"
+)
+
+type SqlMfaRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlMfaRepository(db sqlc.Querier) MfaRepository {
+	return &SqlMfaRepository{
+		db: db,
+	}
+}
+
+func (mr *SqlMfaRepository) FindByUserID(ctx context.Context, userID int32) (sqlc.UserMfa, error) {
+	mfa, err := mr.db.GetUserMfa(ctx, userID)
+	if err != nil {
+		return sqlc.UserMfa{}, err
+	}
+	return mfa, nil
+}
+
+func (mr *SqlMfaRepository) SaveSecret(ctx context.Context, userID int32, secret string) (sqlc.UserMfa, error) {
+	mfa, err := mr.db.UpsertUserMfaSecret(ctx, sqlc.UpsertUserMfaSecretParams{
+		UserID:    userID,
+		MfaSecret: secret,
+	})
+	if err != nil {
+		return sqlc.UserMfa{}, err
+	}
+	return mfa, nil
+}
+
+func (mr *SqlMfaRepository) Enable(ctx context.Context, userID int32, codeHashes []string) (sqlc.UserMfa, error) {
+	tx, err := db.DBpool.Begin(ctx)
+	if err != nil {
+		return sqlc.UserMfa{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+	mfa, err := qtx.EnableUserMfa(ctx, userID)
+	if err != nil {
+		return sqlc.UserMfa{}, err
+	}
+
+	if err := qtx.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return sqlc.UserMfa{}, err
+	}
+
+	if err := qtx.CreateRecoveryCodes(ctx, sqlc.CreateRecoveryCodesParams{
+		UserID:     userID,
+		CodeHashes: codeHashes,
+	}); err != nil {
+		return sqlc.UserMfa{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.UserMfa{}, err
+	}
+	return mfa, nil
+}
+
+func (mr *SqlMfaRepository) Disable(ctx context.Context, userID int32) error {
+	tx, err := db.DBpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+	if err := qtx.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteUserMfa(ctx, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (mr *SqlMfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID int32, codeHashes []string) error {
+	tx, err := db.DBpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+	if err := qtx.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := qtx.CreateRecoveryCodes(ctx, sqlc.CreateRecoveryCodesParams{
+		UserID:     userID,
+		CodeHashes: codeHashes,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (mr *SqlMfaRepository) UseRecoveryCode(ctx context.Context, userID int32, codeHash string) (bool, error) {
+	rows, err := mr.db.UseRecoveryCode(ctx, sqlc.UseRecoveryCodeParams{
+		UserID:           userID,
+		RecoveryCodeHash: codeHash,
+	})
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (mr *SqlMfaRepository) CountUnusedRecoveryCodes(ctx context.Context, userID int32) (int64, error) {
+	count, err := mr.db.CountUnusedRecoveryCodes(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
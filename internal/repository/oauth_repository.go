@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"gin/user-management-api/internal/db/sqlc"
+)
+
+type SqlOAuthRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlOAuthRepository(db sqlc.Querier) OAuthRepository {
+	return &SqlOAuthRepository{
+		db: db,
+	}
+}
+
+func (or *SqlOAuthRepository) CreateClient(ctx context.Context, clientParams sqlc.CreateOAuthClientParams) (sqlc.OauthClient, error) {
+	client, err := or.db.CreateOAuthClient(ctx, clientParams)
+	if err != nil {
+		return sqlc.OauthClient{}, err
+	}
+	return client, nil
+}
+
+func (or *SqlOAuthRepository) FindClientByClientID(ctx context.Context, clientID string) (sqlc.OauthClient, error) {
+	client, err := or.db.GetOAuthClientByClientID(ctx, clientID)
+	if err != nil {
+		return sqlc.OauthClient{}, err
+	}
+	return client, nil
+}
+
+func (or *SqlOAuthRepository) GetAllClients(ctx context.Context) ([]sqlc.OauthClient, error) {
+	clients, err := or.db.ListOAuthClients(ctx)
+	if err != nil {
+		return []sqlc.OauthClient{}, err
+	}
+	return clients, nil
+}
+
+func (or *SqlOAuthRepository) UpdateClientSecret(ctx context.Context, clientID string, secretHash *string) (sqlc.OauthClient, error) {
+	client, err := or.db.UpdateOAuthClientSecret(ctx, sqlc.UpdateOAuthClientSecretParams{
+		ClientID:         clientID,
+		ClientSecretHash: secretHash,
+	})
+	if err != nil {
+		return sqlc.OauthClient{}, err
+	}
+	return client, nil
+}
+
+func (or *SqlOAuthRepository) DeleteClient(ctx context.Context, clientID string) (int64, error) {
+	rows, err := or.db.DeleteOAuthClient(ctx, clientID)
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
+func (or *SqlOAuthRepository) FindConsent(ctx context.Context, userID, oauthClientID int32) (sqlc.OauthConsent, error) {
+	consent, err := or.db.GetOAuthConsent(ctx, sqlc.GetOAuthConsentParams{
+		UserID:        userID,
+		OauthClientID: oauthClientID,
+	})
+	if err != nil {
+		return sqlc.OauthConsent{}, err
+	}
+	return consent, nil
+}
+
+func (or *SqlOAuthRepository) SaveConsent(ctx context.Context, userID, oauthClientID int32, scopes []string) (sqlc.OauthConsent, error) {
+	consent, err := or.db.UpsertOAuthConsent(ctx, sqlc.UpsertOAuthConsentParams{
+		UserID:        userID,
+		OauthClientID: oauthClientID,
+		ConsentScopes: scopes,
+	})
+	if err != nil {
+		return sqlc.OauthConsent{}, err
+	}
+	return consent, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"gin/user-management-api/internal/db"
+	"gin/user-management-api/internal/db/sqlc"
+)
+
+type SqlRoleRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlRoleRepository(db sqlc.Querier) RoleRepository {
+	return &SqlRoleRepository{
+		db: db,
+	}
+}
+
+func (rr *SqlRoleRepository) GetAll(ctx context.Context) ([]sqlc.Role, error) {
+	roles, err := rr.db.ListRoles(ctx)
+	if err != nil {
+		return []sqlc.Role{}, err
+	}
+	return roles, nil
+}
+
+func (rr *SqlRoleRepository) FindByID(ctx context.Context, roleID int32) (sqlc.Role, error) {
+	role, err := rr.db.GetRoleByID(ctx, roleID)
+	if err != nil {
+		return sqlc.Role{}, err
+	}
+	return role, nil
+}
+
+func (rr *SqlRoleRepository) Create(ctx context.Context, roleParams sqlc.CreateRoleParams, permissions []string) (sqlc.Role, error) {
+	tx, err := db.DBpool.Begin(ctx)
+	if err != nil {
+		return sqlc.Role{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+	role, err := qtx.CreateRole(ctx, roleParams)
+	if err != nil {
+		return sqlc.Role{}, err
+	}
+
+	if len(permissions) > 0 {
+		if _, err := qtx.AddRolePermissions(ctx, sqlc.AddRolePermissionsParams{
+			RoleID:          role.RoleID,
+			PermissionCodes: permissions,
+		}); err != nil {
+			return sqlc.Role{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.Role{}, err
+	}
+	return role, nil
+}
+
+// Update replaces the role permissions only when permissions is not nil
+func (rr *SqlRoleRepository) Update(ctx context.Context, roleParams sqlc.UpdateRoleParams, permissions []string) (sqlc.Role, error) {
+	tx, err := db.DBpool.Begin(ctx)
+	if err != nil {
+		return sqlc.Role{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+	role, err := qtx.UpdateRole(ctx, roleParams)
+	if err != nil {
+		return sqlc.Role{}, err
+	}
+
+	if permissions != nil {
+		if err := qtx.DeleteRolePermissions(ctx, role.RoleID); err != nil {
+			return sqlc.Role{}, err
+		}
+
+		if _, err := qtx.AddRolePermissions(ctx, sqlc.AddRolePermissionsParams{
+			RoleID:          role.RoleID,
+			PermissionCodes: permissions,
+		}); err != nil {
+			return sqlc.Role{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.Role{}, err
+	}
+	return role, nil
+}
+
+func (rr *SqlRoleRepository) Delete(ctx context.Context, roleID int32) (sqlc.Role, error) {
+	role, err := rr.db.DeleteRole(ctx, roleID)
+	if err != nil {
+		return sqlc.Role{}, err
+	}
+	return role, nil
+}
+
+func (rr *SqlRoleRepository) GetPermissions(ctx context.Context, roleID int32) ([]sqlc.Permission, error) {
+	permissions, err := rr.db.GetPermissionsByRoleID(ctx, roleID)
+	if err != nil {
+		return []sqlc.Permission{}, err
+	}
+	return permissions, nil
+}
+
+func (rr *SqlRoleRepository) GetAllPermissions(ctx context.Context) ([]sqlc.Permission, error) {
+	permissions, err := rr.db.ListPermissions(ctx)
+	if err != nil {
+		return []sqlc.Permission{}, err
+	}
+	return permissions, nil
+}
+
+func (rr *SqlRoleRepository) GetPermissionCodesByUserID(ctx context.Context, userID int32) ([]string, error) {
+	codes, err := rr.db.GetPermissionCodesByUserID(ctx, userID)
+	if err != nil {
+		return []string{}, err
+	}
+	return codes, nil
+}
+
+func (rr *SqlRoleRepository) AssignUserRole(ctx context.Context, userID, roleID int32) error {
+	return rr.db.AssignUserRole(ctx, sqlc.AssignUserRoleParams{
+		UserID: userID,
+		RoleID: roleID,
+	})
+}
+
+func (rr *SqlRoleRepository) RemoveUserRole(ctx context.Context, userID, roleID int32) (int64, error) {
+	rows, err := rr.db.RemoveUserRole(ctx, sqlc.RemoveUserRoleParams{
+		UserID: userID,
+		RoleID: roleID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
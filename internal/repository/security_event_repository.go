@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"gin/user-management-api/internal/db/sqlc"
+	"time"
+)
+
+type SqlSecurityEventRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlSecurityEventRepository(db sqlc.Querier) SecurityEventRepository {
+	return &SqlSecurityEventRepository{
+		db: db,
+	}
+}
+
+func (sr *SqlSecurityEventRepository) Create(ctx context.Context, eventParams sqlc.CreateSecurityEventParams) error {
+	return sr.db.CreateSecurityEvent(ctx, eventParams)
+}
+
+func (sr *SqlSecurityEventRepository) GetAll(ctx context.Context, filter sqlc.ListSecurityEventsParams) ([]sqlc.SecurityEvent, error) {
+	events, err := sr.db.ListSecurityEvents(ctx, filter)
+	if err != nil {
+		return []sqlc.SecurityEvent{}, err
+	}
+	return events, nil
+}
+
+func (sr *SqlSecurityEventRepository) Count(ctx context.Context, filter sqlc.ListSecurityEventsParams) (int64, error) {
+	return sr.db.CountSecurityEvents(ctx, sqlc.CountSecurityEventsParams{
+		UserID:      filter.UserID,
+		EventType:   filter.EventType,
+		Outcome:     filter.Outcome,
+		IpAddress:   filter.IpAddress,
+		CreatedFrom: filter.CreatedFrom,
+		CreatedTo:   filter.CreatedTo,
+	})
+}
+
+func (sr *SqlSecurityEventRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	return sr.db.DeleteSecurityEventsBefore(ctx, before)
+}
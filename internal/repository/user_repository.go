@@ -2,11 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"gin/user-management-api/internal/db"
 	"gin/user-management-api/internal/db/sqlc"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 type SqlUserRepository struct {
@@ -106,6 +108,7 @@ func (ur *SqlUserRepository) GetAllV2(ctx context.Context, search, orderBy, sort
 			&i.UserCreatedAt,
 			&i.UserUpdatedAt,
 			&i.UserDeletedAt,
+			&i.UserPasswordChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -133,11 +136,39 @@ func (ur *SqlUserRepository) FindByUUID(ctx context.Context, userUuid uuid.UUID)
 	return user, nil
 }
 
-func (ur *SqlUserRepository) Update(ctx context.Context, userParams sqlc.UpdateUserByUuidParams) (sqlc.User, error) {
-	user, err := ur.db.UpdateUserByUuid(ctx, userParams)
+func (ur *SqlUserRepository) Update(ctx context.Context, userParams sqlc.UpdateUserByUuidParams, historySize int32) (sqlc.User, error) {
+	if userParams.UserPassword == nil {
+		user, err := ur.db.UpdateUserByUuid(ctx, userParams)
+		if err != nil {
+			return sqlc.User{}, err
+		}
+		return user, nil
+	}
+
+	tx, err := db.DBpool.Begin(ctx)
 	if err != nil {
 		return sqlc.User{}, err
 	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+	previous, err := qtx.GetUserByUuid(ctx, userParams.UserUuid)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+
+	user, err := qtx.UpdateUserByUuid(ctx, userParams)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+
+	if err := recordPasswordHistory(ctx, qtx, previous, historySize); err != nil {
+		return sqlc.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.User{}, err
+	}
 	return user, nil
 }
 
@@ -184,10 +215,108 @@ func (ur *SqlUserRepository) GetByEmail(ctx context.Context, email string) (sqlc
 	return user, nil
 }
 
-func (ur *SqlUserRepository) UpdatePassword(ctx context.Context, input sqlc.UpdatePasswordParams) (sqlc.User, error) {
-	user, err := ur.db.UpdatePassword(ctx, input)
+// UpdatePassword moves the current hash into password_history and keeps only the last historySize entries
+func (ur *SqlUserRepository) UpdatePassword(ctx context.Context, input sqlc.UpdatePasswordParams, historySize int32) (sqlc.User, error) {
+	tx, err := db.DBpool.Begin(ctx)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+	previous, err := findPasswordOwner(ctx, qtx, input.UserUuid)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+
+	user, err := qtx.UpdatePassword(ctx, input)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+
+	if err := recordPasswordHistory(ctx, qtx, previous, historySize); err != nil {
+		return sqlc.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.User{}, err
+	}
+	return user, nil
+}
+
+// findPasswordOwner also finds accounts in their closure grace period, like the UpdatePassword query,
+// an expired password is changed during the login that restores them
+func findPasswordOwner(ctx context.Context, q sqlc.Querier, userUuid uuid.UUID) (sqlc.User, error) {
+	user, err := q.GetUserByUuid(ctx, userUuid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return q.GetClosedUserByUuid(ctx, userUuid)
+	}
+	return user, err
+}
+
+// UpdatePasswordHash replaces the stored hash of the same password, so history and expiry are left untouched
+func (ur *SqlUserRepository) UpdatePasswordHash(ctx context.Context, userID int32, passwordHash string) error {
+	return ur.db.UpdatePasswordHash(ctx, sqlc.UpdatePasswordHashParams{
+		UserPassword: passwordHash,
+		UserID:       userID,
+	})
+}
+
+func (ur *SqlUserRepository) GetPasswordHistory(ctx context.Context, userID, limit int32) ([]string, error) {
+	if limit <= 0 {
+		return []string{}, nil
+	}
+
+	return ur.db.ListPasswordHistoryHashes(ctx, sqlc.ListPasswordHistoryHashesParams{
+		UserID: userID,
+		Limit:  limit,
+	})
+}
+
+func recordPasswordHistory(ctx context.Context, qtx *sqlc.Queries, previous sqlc.User, historySize int32) error {
+	if historySize > 0 {
+		if err := qtx.CreatePasswordHistory(ctx, sqlc.CreatePasswordHistoryParams{
+			UserID:       previous.UserID,
+			PasswordHash: previous.UserPassword,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return qtx.PrunePasswordHistory(ctx, sqlc.PrunePasswordHistoryParams{
+		UserID: previous.UserID,
+		Keep:   historySize,
+	})
+}
+
+func (ur *SqlUserRepository) UpdateEmail(ctx context.Context, userUuid uuid.UUID, email string) (sqlc.User, error) {
+	user, err := ur.db.UpdateUserEmail(ctx, sqlc.UpdateUserEmailParams{
+		UserEmail: email,
+		UserUuid:  userUuid,
+	})
+	if err != nil {
+		return sqlc.User{}, err
+	}
+	return user, nil
+}
+
+func (ur *SqlUserRepository) VerifyEmail(ctx context.Context, userUuid uuid.UUID) (sqlc.User, error) {
+	user, err := ur.db.VerifyUserEmail(ctx, userUuid)
 	if err != nil {
 		return sqlc.User{}, err
 	}
 	return user, nil
 }
+
+func (ur *SqlUserRepository) DeletePending(ctx context.Context, userUuid uuid.UUID) error {
+	return ur.db.DeletePendingUser(ctx, userUuid)
+}
+
+func (ur *SqlUserRepository) ReplacePending(ctx context.Context, userParams sqlc.CreateUserParams) (sqlc.User, error) {
+	return ur.db.ReplacePendingUser(ctx, sqlc.ReplacePendingUserParams{
+		UserPassword: userParams.UserPassword,
+		UserFullname: userParams.UserFullname,
+		UserAge:      userParams.UserAge,
+		UserEmail:    userParams.UserEmail,
+	})
+}
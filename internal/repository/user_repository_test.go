@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gin/user-management-api/internal/db/sqlc"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// fakeQuerier answers GetUserByUuid like the real query, deleted rows are not found
+type fakeQuerier struct {
+	sqlc.Querier
+	users  map[uuid.UUID]sqlc.User
+	closed map[uuid.UUID]bool
+}
+
+func (q *fakeQuerier) GetUserByUuid(ctx context.Context, userUuid uuid.UUID) (sqlc.User, error) {
+	user, ok := q.users[userUuid]
+	if !ok || user.UserDeletedAt.Valid {
+		return sqlc.User{}, pgx.ErrNoRows
+	}
+	return user, nil
+}
+
+func (q *fakeQuerier) GetClosedUserByUuid(ctx context.Context, userUuid uuid.UUID) (sqlc.User, error) {
+	user, ok := q.users[userUuid]
+	if !ok || !user.UserDeletedAt.Valid || !q.closed[userUuid] {
+		return sqlc.User{}, pgx.ErrNoRows
+	}
+	return user, nil
+}
+
+func TestFindPasswordOwner(t *testing.T) {
+	active := sqlc.User{UserID: 1, UserUuid: uuid.New(), UserPassword: "active-hash"}
+	// Closed with an expired password, the login that restores it has to change the password first
+	closed := sqlc.User{UserID: 2, UserUuid: uuid.New(), UserPassword: "closed-hash", UserDeletedAt: pgtype.Timestamptz{Valid: true}}
+	deleted := sqlc.User{UserID: 3, UserUuid: uuid.New(), UserDeletedAt: pgtype.Timestamptz{Valid: true}}
+
+	q := &fakeQuerier{
+		users:  map[uuid.UUID]sqlc.User{active.UserUuid: active, closed.UserUuid: closed, deleted.UserUuid: deleted},
+		closed: map[uuid.UUID]bool{closed.UserUuid: true},
+	}
+
+	for _, want := range []sqlc.User{active, closed} {
+		got, err := findPasswordOwner(context.Background(), q, want.UserUuid)
+		if err != nil || got.UserPassword != want.UserPassword {
+			t.Errorf("findPasswordOwner(user %d) = %q, %v, want %q", want.UserID, got.UserPassword, err, want.UserPassword)
+		}
+	}
+
+	if _, err := findPasswordOwner(context.Background(), q, deleted.UserUuid); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("deleted account error = %v, want ErrNoRows", err)
+	}
+}
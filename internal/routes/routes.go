@@ -15,22 +15,26 @@ type Route interface {
 	Register(r *gin.RouterGroup)
 }
 
-func RegisterRoutes(r *gin.Engine, authService auth.TokenService, cacheService cache.RedisCacheService, routes ...Route) {
+func RegisterRoutes(r *gin.Engine, authService auth.TokenService, cacheService cache.RedisCacheService, apiKeyAuthenticator auth.APIKeyAuthenticator, userStatusChecker auth.UserStatusChecker, routes ...Route) {
 	httpLogger := utils.NewLoggerWithPath("http.log", "info")
 	recoveryLogger := utils.NewLoggerWithPath("recovery.log", "warning")
 	rateLimiterLogger := utils.NewLoggerWithPath("rate_limiter.log", "warning")
 
+	middleware.InitApiKeyMiddleware(apiKeyAuthenticator)
+
 	r.Use(gzip.Gzip(gzip.DefaultCompression))
 	r.Use(
 		middleware.RateLimiterMiddleware(rateLimiterLogger),
 		middleware.TraceMiddleware(),
 		middleware.LoggerMiddleware(httpLogger),
 		middleware.RecoveryMiddleware(recoveryLogger),
+		middleware.RequestSignatureMiddleware(cacheService),
 		middleware.ApiKeyMiddleware(),
+		middleware.ApiKeyRateLimiterMiddleware(rateLimiterLogger),
 	)
 	v1api := r.Group("/api/v1")
 
-	middleware.InitAuthMiddleware(authService, cacheService)
+	middleware.InitAuthMiddleware(authService, cacheService, userStatusChecker)
 
 	protected := v1api.Group("")
 	protected.Use(
@@ -39,8 +43,10 @@ func RegisterRoutes(r *gin.Engine, authService auth.TokenService, cacheService c
 
 	for _, route := range routes {
 		switch route.(type) {
-		case *v1routes.AuthRoutes:
+		case *v1routes.AuthRoutes, *v1routes.OAuthRoutes, *v1routes.InvitationRoutes, *v1routes.DataExportRoutes:
 			route.Register(v1api)
+		case *v1routes.WellKnownRoutes:
+			route.Register(&r.RouterGroup)
 		default:
 			route.Register(protected)
 		}
@@ -0,0 +1,28 @@
+package v1routes
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiKeyRoutes struct {
+	handler *v1handler.ApiKeyHandler
+}
+
+func NewApiKeyRoutes(handler *v1handler.ApiKeyHandler) *ApiKeyRoutes {
+	return &ApiKeyRoutes{
+		handler: handler,
+	}
+}
+
+func (ar *ApiKeyRoutes) Register(r *gin.RouterGroup) {
+	keys := r.Group("/api-keys", middleware.DenyImpersonation(), middleware.RequirePermission(middleware.PermissionAPIKeyManage))
+	{
+		keys.GET("", ar.handler.GetAllAPIKeys)
+		keys.POST("", ar.handler.CreateAPIKey)
+		keys.POST("/:uuid/rotate", ar.handler.RotateAPIKey)
+		keys.DELETE("/:uuid", ar.handler.RevokeAPIKey)
+	}
+}
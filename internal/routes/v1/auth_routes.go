@@ -2,6 +2,7 @@ package v1routes
 
 import (
 	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,10 +20,30 @@ func NewAuthRoutes(handler *v1handler.AuthHandler) *AuthRoutes {
 func (ar *AuthRoutes) Register(r *gin.RouterGroup) {
 	auth := r.Group("/auth")
 	{
+		auth.POST("/register", ar.handler.Register)
+		auth.POST("/verify-email", ar.handler.VerifyEmail)
+		auth.POST("/verify-email/resend", ar.handler.ResendVerificationEmail)
 		auth.POST("/login", ar.handler.Login)
+		auth.POST("/magic-link", ar.handler.RequestMagicLink)
+		auth.POST("/magic-link/consume", ar.handler.ConsumeMagicLink)
 		auth.POST("/logout", ar.handler.Logout)
 		auth.POST("/resfresh", ar.handler.RefreshToken)
 		auth.POST("/forgot-password", ar.handler.RequestForgotPassword)
 		auth.POST("/reset-password", ar.handler.ResetPassword)
+		auth.POST("/change-expired-password", ar.handler.ChangeExpiredPassword)
+		auth.POST("/report-sign-in", ar.handler.ReportSignIn)
+		auth.POST("/email-change", middleware.AuthMiddleware(), middleware.DenyImpersonation(), ar.handler.RequestEmailChange)
+		auth.POST("/email-change/confirm", ar.handler.ConfirmEmailChange)
+		auth.POST("/email-change/cancel", ar.handler.CancelEmailChange)
+		auth.POST("/mfa/verify", ar.handler.VerifyMfa)
+		auth.POST("/mfa/setup", ar.handler.SetupMfa)
+	}
+
+	mfa := auth.Group("/mfa", middleware.AuthMiddleware(), middleware.DenyImpersonation())
+	{
+		mfa.POST("/enroll", ar.handler.EnrollMfa)
+		mfa.POST("/enroll/confirm", ar.handler.ConfirmMfa)
+		mfa.POST("/disable", ar.handler.DisableMfa)
+		mfa.POST("/recovery-codes", ar.handler.RegenerateRecoveryCodes)
 	}
 }
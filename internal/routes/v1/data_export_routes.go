@@ -0,0 +1,29 @@
+package v1routes
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DataExportRoutes struct {
+	handler *v1handler.DataExportHandler
+}
+
+func NewDataExportRoutes(handler *v1handler.DataExportHandler) *DataExportRoutes {
+	return &DataExportRoutes{
+		handler: handler,
+	}
+}
+
+// Register is mounted on the public group, the emailed download link carries its own token
+func (dr *DataExportRoutes) Register(r *gin.RouterGroup) {
+	r.GET("/exports/download", dr.handler.DownloadExport)
+
+	exports := r.Group("/me/exports", middleware.AuthMiddleware())
+	{
+		exports.POST("", middleware.DenyImpersonation(), dr.handler.RequestExport)
+		exports.GET("/:uuid", dr.handler.GetExport)
+	}
+}
@@ -0,0 +1,28 @@
+package v1routes
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImpersonationRoutes struct {
+	handler *v1handler.ImpersonationHandler
+}
+
+func NewImpersonationRoutes(handler *v1handler.ImpersonationHandler) *ImpersonationRoutes {
+	return &ImpersonationRoutes{
+		handler: handler,
+	}
+}
+
+func (ir *ImpersonationRoutes) Register(r *gin.RouterGroup) {
+	r.POST("/users/:uuid/impersonate",
+		middleware.DenyImpersonation(),
+		middleware.RequireRole(middleware.RoleAdministrator),
+		middleware.RequirePermission(middleware.PermissionUserImpersonate),
+		ir.handler.StartImpersonation,
+	)
+	r.POST("/impersonation/stop", ir.handler.StopImpersonation)
+}
@@ -0,0 +1,31 @@
+package v1routes
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvitationRoutes struct {
+	handler *v1handler.InvitationHandler
+}
+
+func NewInvitationRoutes(handler *v1handler.InvitationHandler) *InvitationRoutes {
+	return &InvitationRoutes{
+		handler: handler,
+	}
+}
+
+// Register is mounted on the public group, accepting an invitation happens before the invitee has an account
+func (ir *InvitationRoutes) Register(r *gin.RouterGroup) {
+	r.POST("/auth/accept-invite", ir.handler.AcceptInvitation)
+
+	invitations := r.Group("/invitations", middleware.AuthMiddleware(), middleware.DenyImpersonation(), middleware.RequirePermission(middleware.PermissionUserCreate))
+	{
+		invitations.GET("", ir.handler.GetPendingInvitations)
+		invitations.POST("", ir.handler.CreateInvitation)
+		invitations.POST("/:uuid/resend", ir.handler.ResendInvitation)
+		invitations.DELETE("/:uuid", ir.handler.RevokeInvitation)
+	}
+}
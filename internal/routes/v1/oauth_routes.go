@@ -0,0 +1,42 @@
+package v1routes
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OAuthRoutes struct {
+	handler *v1handler.OAuthHandler
+}
+
+func NewOAuthRoutes(handler *v1handler.OAuthHandler) *OAuthRoutes {
+	return &OAuthRoutes{
+		handler: handler,
+	}
+}
+
+func (or *OAuthRoutes) Register(r *gin.RouterGroup) {
+	r.POST("/auth/introspect", or.handler.Introspect)
+	r.POST("/auth/revoke", or.handler.Revoke)
+
+	oauth := r.Group("/oauth")
+	{
+		oauth.POST("/token", or.handler.Token)
+
+		authorize := oauth.Group("/authorize", middleware.AuthMiddleware())
+		{
+			authorize.GET("", or.handler.GetAuthorization)
+			authorize.POST("", middleware.DenyImpersonation(), or.handler.Authorize)
+		}
+
+		clients := oauth.Group("/clients", middleware.AuthMiddleware(), middleware.DenyImpersonation(), middleware.RequirePermission(middleware.PermissionOAuthManage))
+		{
+			clients.GET("", or.handler.GetAllClients)
+			clients.POST("", or.handler.CreateClient)
+			clients.POST("/:client_id/secret", or.handler.RotateClientSecret)
+			clients.DELETE("/:client_id", or.handler.DeleteClient)
+		}
+	}
+}
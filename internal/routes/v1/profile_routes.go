@@ -0,0 +1,28 @@
+package v1routes
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileRoutes struct {
+	handler *v1handler.ProfileHandler
+}
+
+func NewProfileRoutes(handler *v1handler.ProfileHandler) *ProfileRoutes {
+	return &ProfileRoutes{
+		handler: handler,
+	}
+}
+
+func (pr *ProfileRoutes) Register(r *gin.RouterGroup) {
+	me := r.Group("/me")
+	{
+		me.GET("", pr.handler.GetProfile)
+		me.PATCH("", pr.handler.UpdateProfile)
+		me.DELETE("", middleware.DenyImpersonation(), pr.handler.CloseAccount)
+		me.PUT("/password", middleware.DenyImpersonation(), pr.handler.ChangePassword)
+	}
+}
@@ -0,0 +1,33 @@
+package v1routes
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleRoutes struct {
+	handler *v1handler.RoleHandler
+}
+
+func NewRoleRoutes(handler *v1handler.RoleHandler) *RoleRoutes {
+	return &RoleRoutes{
+		handler: handler,
+	}
+}
+
+func (rr *RoleRoutes) Register(r *gin.RouterGroup) {
+	roles := r.Group("/roles")
+	roles.Use(middleware.RequirePermission(middleware.PermissionRoleManage))
+	{
+		roles.GET("/", rr.handler.GetAllRoles)
+		roles.GET("/permissions", rr.handler.GetAllPermissions)
+		roles.POST("/", rr.handler.CreateRole)
+		roles.GET("/:id", rr.handler.GetRoleByID)
+		roles.PUT("/:id", rr.handler.UpdateRole)
+		roles.DELETE("/:id", rr.handler.DeleteRole)
+		roles.POST("/:id/users/:uuid", rr.handler.AssignUserRole)
+		roles.DELETE("/:id/users/:uuid", rr.handler.RemoveUserRole)
+	}
+}
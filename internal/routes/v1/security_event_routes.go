@@ -0,0 +1,23 @@
+package v1routes
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SecurityEventRoutes struct {
+	handler *v1handler.SecurityEventHandler
+}
+
+func NewSecurityEventRoutes(handler *v1handler.SecurityEventHandler) *SecurityEventRoutes {
+	return &SecurityEventRoutes{
+		handler: handler,
+	}
+}
+
+func (sr *SecurityEventRoutes) Register(r *gin.RouterGroup) {
+	r.GET("/me/security-events", sr.handler.GetMySecurityEvents)
+	r.GET("/security-events", middleware.RequirePermission(middleware.PermissionSecurityEventRead), sr.handler.GetSecurityEvents)
+}
@@ -0,0 +1,35 @@
+package v1routes
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionRoutes struct {
+	handler *v1handler.SessionHandler
+}
+
+func NewSessionRoutes(handler *v1handler.SessionHandler) *SessionRoutes {
+	return &SessionRoutes{
+		handler: handler,
+	}
+}
+
+func (sr *SessionRoutes) Register(r *gin.RouterGroup) {
+	me := r.Group("/me/sessions")
+	{
+		me.GET("", sr.handler.GetMySessions)
+		me.DELETE("", sr.handler.RevokeMySessions)
+		me.DELETE("/:id", sr.handler.RevokeMySession)
+	}
+
+	users := r.Group("/users/:uuid/sessions")
+	users.Use(middleware.RequirePermission(middleware.PermissionUserSessions))
+	{
+		users.GET("", sr.handler.GetUserSessions)
+		users.DELETE("", sr.handler.RevokeUserSessions)
+		users.DELETE("/:id", sr.handler.RevokeUserSession)
+	}
+}
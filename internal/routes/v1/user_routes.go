@@ -2,6 +2,7 @@ package v1routes
 
 import (
 	v1handler "gin/user-management-api/internal/handler/v1"
+	"gin/user-management-api/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,13 +21,15 @@ func NewUserRoutes(handler *v1handler.UserHandler) *UserRoutes {
 func (ur *UserRoutes) Register(r *gin.RouterGroup) {
 	users := r.Group("/users")
 	{
-		users.GET("/", ur.handler.GetAllUsers)
-		users.GET("/soft-deleted", ur.handler.GetUserSoftDeleted)
-		users.POST("/", ur.handler.CreateUser)
-		users.GET("/:uuid", ur.handler.GetUserByUUID)
-		users.PUT("/:uuid", ur.handler.UpdateUser)
-		users.DELETE("/:uuid", ur.handler.SortDeleteUser)
-		users.PATCH("/:uuid/restore", ur.handler.RestoreUser)
-		users.DELETE("/:uuid/trash", ur.handler.DeleteUser)
+		users.GET("/", middleware.RequirePermission(middleware.PermissionUserRead), ur.handler.GetAllUsers)
+		users.GET("/soft-deleted", middleware.RequirePermission(middleware.PermissionUserRead), ur.handler.GetUserSoftDeleted)
+		users.POST("/", middleware.RequirePermission(middleware.PermissionUserCreate), ur.handler.CreateUser)
+		users.GET("/:uuid", middleware.RequirePermission(middleware.PermissionUserRead), ur.handler.GetUserByUUID)
+		users.PUT("/:uuid", middleware.DenyImpersonation(), middleware.RequirePermission(middleware.PermissionUserUpdate), ur.handler.UpdateUser)
+		users.PATCH("/:uuid/status", middleware.RequirePermission(middleware.PermissionUserUpdateStatus), ur.handler.UpdateUserStatus)
+		users.POST("/:uuid/unlock", middleware.RequirePermission(middleware.PermissionUserUpdateStatus), ur.handler.UnlockUser)
+		users.DELETE("/:uuid", middleware.DenyImpersonation(), middleware.RequirePermission(middleware.PermissionUserDelete), ur.handler.SortDeleteUser)
+		users.PATCH("/:uuid/restore", middleware.RequirePermission(middleware.PermissionUserRestore), ur.handler.RestoreUser)
+		users.DELETE("/:uuid/trash", middleware.DenyImpersonation(), middleware.RequireRole(middleware.RoleAdministrator), middleware.RequirePermission(middleware.PermissionUserTrash), ur.handler.DeleteUser)
 	}
 }
@@ -0,0 +1,24 @@
+package v1routes
+
+import (
+	v1handler "gin/user-management-api/internal/handler/v1"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WellKnownRoutes struct {
+	handler *v1handler.WellKnownHandler
+}
+
+func NewWellKnownRoutes(handler *v1handler.WellKnownHandler) *WellKnownRoutes {
+	return &WellKnownRoutes{
+		handler: handler,
+	}
+}
+
+func (wr *WellKnownRoutes) Register(r *gin.RouterGroup) {
+	wellKnown := r.Group("/.well-known")
+	{
+		wellKnown.GET("/jwks.json", wr.handler.GetJWKS)
+	}
+}
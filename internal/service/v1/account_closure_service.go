@@ -0,0 +1,78 @@
+package v1service
+
+import (
+	"context"
+	"errors"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/loggers"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// AccountClosureBatchSize limits how many accounts the worker anonymizes per run
+const AccountClosureBatchSize = 100
+
+// ACCOUNT_CLOSURE_GRACE_DAYS is how long a closed account can be restored by logging in
+func AccountClosureGracePeriod() time.Duration {
+	days := utils.GetIntEnv("ACCOUNT_CLOSURE_GRACE_DAYS", 30)
+	if days < 0 {
+		days = 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// AnonymizeClosedAccounts replaces the personal data of accounts past their grace period, it is run by the worker
+func AnonymizeClosedAccounts(ctx context.Context, repo repository.AccountClosureRepository, limit int32) (int, error) {
+	closures, err := repo.GetDue(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	anonymized := 0
+	for _, closure := range closures {
+		ok, err := repo.Anonymize(ctx, closure.UserID)
+		if err != nil {
+			return anonymized, err
+		}
+		if ok {
+			anonymized++
+		}
+	}
+	return anonymized, nil
+}
+
+// findChallengeUser loads the user of a login challenge, closed accounts stay closed until the login completes
+func (as *authService) findChallengeUser(ctx context.Context, userUuid uuid.UUID, closed bool) (sqlc.User, error) {
+	if !closed {
+		return as.findUserByUUID(ctx, userUuid)
+	}
+
+	user, err := as.closureRepo.FindClosedUserByUUID(ctx, userUuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.User{}, utils.NewError(utils.NotFoundError, "User not found")
+		}
+		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "Failed to get user", err)
+	}
+	return user, nil
+}
+
+// restoreClosedAccount undoes a closure when its owner logs in during the grace period
+func (as *authService) restoreClosedAccount(ctx *gin.Context, user sqlc.User) (sqlc.User, error) {
+	restored, err := as.closureRepo.Restore(ctx.Request.Context(), user)
+	if err != nil {
+		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "Failed to restore account", err)
+	}
+
+	if err := as.cacheService.Clear("users:*"); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to clear cache")
+	}
+
+	as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventAccountRestored, Outcome: SecurityOutcomeSuccess, UserID: &restored.UserID, Email: restored.UserEmail})
+	return restored, nil
+}
@@ -0,0 +1,282 @@
+package v1service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/loggers"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	// Revoked or rotated keys can still be accepted by other instances until their memory entry expires
+	APIKeyMemoryTTL   = 30 * time.Second
+	APIKeyCacheTTL    = 5 * time.Minute
+	APIKeyTouchPeriod = time.Minute
+)
+
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+type cachedAPIKey struct {
+	KeyID     int32       `json:"key_id"`
+	OwnerUUID string      `json:"owner_uuid"`
+	Key       auth.APIKey `json:"key"`
+}
+
+type memoryAPIKey struct {
+	entry     cachedAPIKey
+	expiresAt time.Time
+}
+
+type apiKeyService struct {
+	apiKeyRepo   repository.ApiKeyRepository
+	userRepo     repository.UserRepository
+	cacheService cache.RedisCacheService
+	// ownerStatus refuses keys of deleted, inactive or banned owners like their access tokens
+	ownerStatus auth.UserStatusChecker
+	memory      sync.Map
+	touchedAt   sync.Map
+}
+
+func NewApiKeyService(apiKeyRepo repository.ApiKeyRepository, userRepo repository.UserRepository, cacheService cache.RedisCacheService) ApiKeyService {
+	return &apiKeyService{
+		apiKeyRepo:   apiKeyRepo,
+		userRepo:     userRepo,
+		cacheService: cacheService,
+		ownerStatus:  NewUserStatusService(userRepo, cacheService),
+	}
+}
+
+func apiKeyCacheKey(hash string) string {
+	return "api_key:" + hash
+}
+
+func (aks *apiKeyService) GetAllAPIKeys(ctx *gin.Context) ([]sqlc.ApiKey, error) {
+	keys, err := aks.apiKeyRepo.GetAll(ctx.Request.Context())
+	if err != nil {
+		return nil, utils.WrapError(utils.InternalServerError, "failed to get api keys", err)
+	}
+	return keys, nil
+}
+
+// CreateAPIKey returns the plain key, only its hash is stored so it cannot be shown again
+func (aks *apiKeyService) CreateAPIKey(ctx *gin.Context, name string, scopes []string, expiresAt *time.Time) (sqlc.ApiKey, string, error) {
+	context := ctx.Request.Context()
+
+	ownerUuid, err := uuid.Parse(ctx.GetString("user_uuid"))
+	if err != nil {
+		return sqlc.ApiKey{}, "", utils.NewError(utils.UnauthorizedError, "Invalid user in access token")
+	}
+
+	owner, err := aks.userRepo.FindByUUID(context, ownerUuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.ApiKey{}, "", utils.NewError(utils.NotFoundError, "user not found")
+		}
+		return sqlc.ApiKey{}, "", utils.WrapError(utils.InternalServerError, "failed to get user", err)
+	}
+
+	plainKey, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return sqlc.ApiKey{}, "", utils.WrapError(utils.InternalServerError, "failed to generate api key", err)
+	}
+
+	if scopes == nil {
+		scopes = []string{}
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(auth.APIKeyScopes, scope) {
+			return sqlc.ApiKey{}, "", utils.NewError(utils.BadRequestError, fmt.Sprintf("Unknown scope: %s", scope))
+		}
+	}
+
+	params := sqlc.CreateApiKeyParams{
+		ApiKeyOwnerID: owner.UserID,
+		ApiKeyName:    name,
+		ApiKeyPrefix:  prefix,
+		ApiKeyHash:    hash,
+		ApiKeyScopes:  scopes,
+	}
+	if expiresAt != nil {
+		params.ApiKeyExpiresAt = pgtype.Timestamptz{Time: *expiresAt, Valid: true}
+	}
+
+	key, err := aks.apiKeyRepo.Create(context, params)
+	if err != nil {
+		return sqlc.ApiKey{}, "", utils.WrapError(utils.InternalServerError, "failed to create api key", err)
+	}
+
+	return key, plainKey, nil
+}
+
+func (aks *apiKeyService) RotateAPIKey(ctx *gin.Context, keyUuid uuid.UUID) (sqlc.ApiKey, string, error) {
+	context := ctx.Request.Context()
+
+	current, err := aks.findKey(context, keyUuid)
+	if err != nil {
+		return sqlc.ApiKey{}, "", err
+	}
+
+	plainKey, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return sqlc.ApiKey{}, "", utils.WrapError(utils.InternalServerError, "failed to generate api key", err)
+	}
+
+	key, err := aks.apiKeyRepo.Rotate(context, keyUuid, prefix, hash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.ApiKey{}, "", utils.NewError(utils.BadRequestError, "api key is revoked")
+		}
+		return sqlc.ApiKey{}, "", utils.WrapError(utils.InternalServerError, "failed to rotate api key", err)
+	}
+
+	aks.forget(current.ApiKeyHash)
+	return key, plainKey, nil
+}
+
+func (aks *apiKeyService) RevokeAPIKey(ctx *gin.Context, keyUuid uuid.UUID) error {
+	context := ctx.Request.Context()
+
+	if _, err := aks.findKey(context, keyUuid); err != nil {
+		return err
+	}
+
+	key, err := aks.apiKeyRepo.Revoke(context, keyUuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewError(utils.BadRequestError, "api key is already revoked")
+		}
+		return utils.WrapError(utils.InternalServerError, "failed to revoke api key", err)
+	}
+
+	aks.forget(key.ApiKeyHash)
+	return nil
+}
+
+// AuthenticateAPIKey looks the key up in memory, then Redis, then the database
+func (aks *apiKeyService) AuthenticateAPIKey(ctx context.Context, plainKey string) (auth.APIKey, error) {
+	hash := auth.HashAPIKey(plainKey)
+
+	entry, err := aks.lookup(ctx, hash)
+	if err != nil {
+		return auth.APIKey{}, err
+	}
+
+	if entry.Key.Expired() {
+		aks.forget(hash)
+		return auth.APIKey{}, ErrInvalidAPIKey
+	}
+
+	// The status is cached apart from the key, so banning or deleting the owner takes effect right away
+	if err := aks.ownerStatus.CheckUserStatus(ctx, entry.OwnerUUID, time.Now()); err != nil {
+		return auth.APIKey{}, ErrInvalidAPIKey
+	}
+
+	aks.touch(entry.KeyID)
+	return entry.Key, nil
+}
+
+func (aks *apiKeyService) lookup(ctx context.Context, hash string) (cachedAPIKey, error) {
+	if val, ok := aks.memory.Load(hash); ok {
+		if m := val.(memoryAPIKey); time.Now().Before(m.expiresAt) {
+			return m.entry, nil
+		}
+		aks.memory.Delete(hash)
+	}
+
+	var entry cachedAPIKey
+	if err := aks.cacheService.Get(apiKeyCacheKey(hash), &entry); err == nil && entry.Key.ID != "" && entry.OwnerUUID != "" {
+		aks.remember(hash, entry)
+		return entry, nil
+	}
+
+	key, err := aks.apiKeyRepo.FindByHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return cachedAPIKey{}, ErrInvalidAPIKey
+		}
+		return cachedAPIKey{}, err
+	}
+
+	if key.ApiKeyRevokedAt.Valid {
+		return cachedAPIKey{}, ErrInvalidAPIKey
+	}
+
+	ownerUuid, err := aks.apiKeyRepo.FindOwnerUUID(ctx, key.ApiKeyOwnerID)
+	if err != nil {
+		return cachedAPIKey{}, err
+	}
+
+	entry = cachedAPIKey{
+		KeyID:     key.ApiKeyID,
+		OwnerUUID: ownerUuid.String(),
+		Key: auth.APIKey{
+			ID:      key.ApiKeyUuid.String(),
+			Name:    key.ApiKeyName,
+			OwnerID: key.ApiKeyOwnerID,
+			Scopes:  key.ApiKeyScopes,
+		},
+	}
+	if key.ApiKeyExpiresAt.Valid {
+		expiresAt := key.ApiKeyExpiresAt.Time
+		entry.Key.ExpiresAt = &expiresAt
+	}
+
+	if err := aks.cacheService.Set(apiKeyCacheKey(hash), entry, APIKeyCacheTTL); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to cache api key")
+	}
+	aks.remember(hash, entry)
+	return entry, nil
+}
+
+func (aks *apiKeyService) remember(hash string, entry cachedAPIKey) {
+	aks.memory.Store(hash, memoryAPIKey{entry: entry, expiresAt: time.Now().Add(APIKeyMemoryTTL)})
+}
+
+func (aks *apiKeyService) forget(hash string) {
+	aks.memory.Delete(hash)
+	if err := aks.cacheService.Delete(apiKeyCacheKey(hash)); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to delete cached api key")
+	}
+}
+
+// touch updates last_used_at at most once per APIKeyTouchPeriod so busy keys do not write on every request
+func (aks *apiKeyService) touch(keyID int32) {
+	now := time.Now()
+	if val, ok := aks.touchedAt.Load(keyID); ok && now.Sub(val.(time.Time)) < APIKeyTouchPeriod {
+		return
+	}
+	aks.touchedAt.Store(keyID, now)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := aks.apiKeyRepo.TouchLastUsed(ctx, keyID); err != nil {
+			loggers.Log.Warn().Err(err).Int32("api_key_id", keyID).Msg("Failed to update api key last used")
+		}
+	}()
+}
+
+func (aks *apiKeyService) findKey(ctx context.Context, keyUuid uuid.UUID) (sqlc.ApiKey, error) {
+	key, err := aks.apiKeyRepo.FindByUUID(ctx, keyUuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.ApiKey{}, utils.NewError(utils.NotFoundError, "api key not found")
+		}
+		return sqlc.ApiKey{}, utils.WrapError(utils.InternalServerError, "failed to get api key", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,263 @@
+package v1service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/directory"
+	"gin/user-management-api/pkg/hasher"
+	"gin/user-management-api/pkg/loggers"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	AuthMethodPassword = "password"
+	AuthMethodLDAP     = "ldap"
+
+	IdentityProviderLDAP = "ldap"
+
+	// unusablePassword is stored for directory accounts, no hash ever verifies against it
+	unusablePassword = "!"
+)
+
+var (
+	// ErrUnknownAccount lets the next authenticator try the email
+	ErrUnknownAccount  = errors.New("unknown account")
+	ErrInvalidPassword = errors.New("invalid password")
+)
+
+// Authentication is the outcome of a password check, User is also set with ErrInvalidPassword when the account is known
+type Authentication struct {
+	User   sqlc.User
+	Method string
+	// Closed accounts are in their closure grace period and are restored by the login
+	Closed bool
+}
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, email, password string) (Authentication, error)
+}
+
+// localAuthenticator checks the argon2id or legacy bcrypt hash stored in the users table
+type localAuthenticator struct {
+	userRepo     repository.UserRepository
+	closureRepo  repository.AccountClosureRepository
+	identityRepo repository.IdentityRepository
+}
+
+func NewLocalAuthenticator(userRepo repository.UserRepository, closureRepo repository.AccountClosureRepository, identityRepo repository.IdentityRepository) Authenticator {
+	return &localAuthenticator{
+		userRepo:     userRepo,
+		closureRepo:  closureRepo,
+		identityRepo: identityRepo,
+	}
+}
+
+func (la *localAuthenticator) Authenticate(ctx context.Context, email, password string) (Authentication, error) {
+	result := Authentication{Method: AuthMethodPassword}
+
+	user, err := la.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if user, err = la.closureRepo.FindClosedUserByEmail(ctx, email); err != nil {
+			return Authentication{}, ErrUnknownAccount
+		}
+		result.Closed = true
+	}
+
+	// Passwords of directory accounts are checked by the directory
+	if _, err := la.identityRepo.FindByUserID(ctx, user.UserID); err == nil {
+		return Authentication{}, ErrUnknownAccount
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return Authentication{}, utils.WrapError(utils.InternalServerError, "Failed to get user identity", err)
+	}
+
+	result.User = user
+	if ok, _ := hasher.Verify(user.UserPassword, password); !ok {
+		return result, ErrInvalidPassword
+	}
+	return result, nil
+}
+
+// ldapAuthenticator binds against the directory and provisions the account on its first login
+type ldapAuthenticator struct {
+	client       directory.Client
+	userRepo     repository.UserRepository
+	closureRepo  repository.AccountClosureRepository
+	identityRepo repository.IdentityRepository
+	cacheService cache.RedisCacheService
+}
+
+func NewLDAPAuthenticator(client directory.Client, userRepo repository.UserRepository, closureRepo repository.AccountClosureRepository, identityRepo repository.IdentityRepository, cacheService cache.RedisCacheService) Authenticator {
+	return &ldapAuthenticator{
+		client:       client,
+		userRepo:     userRepo,
+		closureRepo:  closureRepo,
+		identityRepo: identityRepo,
+		cacheService: cacheService,
+	}
+}
+
+func (la *ldapAuthenticator) Authenticate(ctx context.Context, email, password string) (Authentication, error) {
+	entry, err := la.client.Authenticate(email, password)
+	if err != nil {
+		if errors.Is(err, directory.ErrUserNotFound) {
+			return Authentication{}, ErrUnknownAccount
+		}
+		if errors.Is(err, directory.ErrInvalidCredentials) {
+			result := Authentication{Method: AuthMethodLDAP}
+			if user, err := la.userRepo.GetByEmail(ctx, email); err == nil {
+				result.User = user
+			}
+			return result, ErrInvalidPassword
+		}
+		return Authentication{}, utils.WrapError(utils.InternalServerError, "Failed to reach the directory", err)
+	}
+
+	level, ok := ldapLevelForGroups(entry.Groups)
+	if !ok {
+		return Authentication{}, utils.NewError(utils.ForbiddenError, "Your directory account is not allowed to sign in")
+	}
+
+	fullname := entry.Name
+	if fullname == "" {
+		fullname = email
+	}
+
+	user, err := la.identityRepo.FindUser(ctx, IdentityProviderLDAP, entry.DN)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		user, err = la.provision(ctx, entry, email, fullname, level)
+		if err != nil {
+			return Authentication{}, err
+		}
+	case err != nil:
+		return Authentication{}, utils.WrapError(utils.InternalServerError, "Failed to get user", err)
+	case user.UserDeletedAt.Valid:
+		return la.deletedAccount(ctx, user)
+	case user.UserFullname != fullname || user.UserLevel != level:
+		user, err = la.identityRepo.Sync(ctx, user.UserID, fullname, level)
+		if err != nil {
+			return Authentication{}, utils.WrapError(utils.InternalServerError, "Failed to sync directory account", err)
+		}
+		la.clearUserCache(user)
+	}
+
+	return Authentication{User: user, Method: AuthMethodLDAP}, nil
+}
+
+// deletedAccount lets a closed account be restored by the login like a local one,
+// accounts deleted by an administrator stay refused
+func (la *ldapAuthenticator) deletedAccount(ctx context.Context, user sqlc.User) (Authentication, error) {
+	closed, err := la.closureRepo.FindClosedUserByUUID(ctx, user.UserUuid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Authentication{}, utils.NewError(utils.AccountInactiveError, "Your account is inactive. Please contact support")
+	}
+	if err != nil {
+		return Authentication{}, utils.WrapError(utils.InternalServerError, "Failed to get closed account", err)
+	}
+	return Authentication{User: closed, Method: AuthMethodLDAP, Closed: true}, nil
+}
+
+func (la *ldapAuthenticator) provision(ctx context.Context, entry directory.Entry, email, fullname string, level int32) (sqlc.User, error) {
+	if entry.Email != "" {
+		email = utils.NormalizeString(entry.Email)
+	}
+
+	user, err := la.identityRepo.Provision(ctx, sqlc.CreateUserParams{
+		UserEmail:    email,
+		UserPassword: unusablePassword,
+		UserFullname: fullname,
+		UserStatus:   UserStatusActive,
+		UserLevel:    level,
+	}, IdentityProviderLDAP, entry.DN)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return sqlc.User{}, utils.NewError(utils.ConflictError, "An account with this email already exists")
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return sqlc.User{}, utils.NewError(utils.InternalServerError, "Directory group is mapped to a level that does not match any role")
+		}
+		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "Failed to provision directory account", err)
+	}
+
+	loggers.Log.Info().
+		Str("event", "directory_account_provisioned").
+		Str("user_uuid", user.UserUuid.String()).
+		Str("dn", entry.DN).
+		Msg("Account provisioned from the directory")
+
+	la.clearUserCache(user)
+	return user, nil
+}
+
+func (la *ldapAuthenticator) clearUserCache(user sqlc.User) {
+	if err := la.cacheService.Delete(fmt.Sprintf("permissions:user:%s", user.UserUuid)); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to delete permissions cache")
+	}
+	if err := la.cacheService.Clear("users:*"); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to clear cache")
+	}
+}
+
+// LDAP_GROUP_LEVELS maps group DNs to levels as "<group dn>:<level>;...", the first group the user is in wins,
+// users in no mapped group get LDAP_DEFAULT_LEVEL or are refused when it is 0
+func ldapLevelForGroups(groups []string) (int32, bool) {
+	for _, mapping := range strings.Split(utils.GetEnv("LDAP_GROUP_LEVELS", ""), ";") {
+		sep := strings.LastIndex(mapping, ":")
+		if sep <= 0 {
+			continue
+		}
+
+		groupDN := strings.TrimSpace(mapping[:sep])
+		level, err := strconv.Atoi(strings.TrimSpace(mapping[sep+1:]))
+		if err != nil || level <= 0 {
+			loggers.Log.Warn().Str("mapping", mapping).Msg("Ignoring invalid LDAP_GROUP_LEVELS entry")
+			continue
+		}
+
+		for _, group := range groups {
+			if strings.EqualFold(strings.TrimSpace(group), groupDN) {
+				return int32(level), true
+			}
+		}
+	}
+
+	if level := utils.GetIntEnv("LDAP_DEFAULT_LEVEL", 0); level > 0 {
+		return int32(level), true
+	}
+	return 0, false
+}
+
+// CheckLocalPassword refuses flows that prove only the mailbox, such as magic links and password resets,
+// and changes of data the directory owns, for accounts whose password is checked by a directory
+func (as *authService) CheckLocalPassword(ctx context.Context, user sqlc.User) error {
+	_, err := as.identityRepo.FindByUserID(ctx, user.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return utils.WrapError(utils.InternalServerError, "Failed to get user identity", err)
+	}
+	return utils.NewError(utils.ForbiddenError, "This account signs in with its directory password")
+}
+
+// authenticate asks each authenticator in turn until one knows the email
+func (as *authService) authenticate(ctx context.Context, email, password string) (Authentication, error) {
+	for _, authenticator := range as.authenticators {
+		result, err := authenticator.Authenticate(ctx, email, password)
+		if errors.Is(err, ErrUnknownAccount) {
+			continue
+		}
+		return result, err
+	}
+	return Authentication{}, ErrUnknownAccount
+}
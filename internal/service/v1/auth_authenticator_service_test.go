@@ -0,0 +1,410 @@
+package v1service
+
+import (
+	"context"
+	"errors"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/directory"
+	"gin/user-management-api/pkg/directory/directorytest"
+	"gin/user-management-api/pkg/loggers"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog"
+)
+
+func TestMain(m *testing.M) {
+	logger := zerolog.Nop()
+	loggers.Log = &logger
+	os.Exit(m.Run())
+}
+
+const (
+	adminsGroup = "cn=admins,ou=groups,dc=example,dc=org"
+	staffGroup  = "cn=staff,ou=groups,dc=example,dc=org"
+	aliceDN     = "uid=alice,ou=people,dc=example,dc=org"
+)
+
+// fakeIdentityRepo keeps provisioned directory accounts in memory
+type fakeIdentityRepo struct {
+	users       map[string]sqlc.User
+	identities  map[int32]sqlc.UserIdentity
+	provisioned []sqlc.CreateUserParams
+	synced      int
+}
+
+func newFakeIdentityRepo() *fakeIdentityRepo {
+	return &fakeIdentityRepo{
+		users:      make(map[string]sqlc.User),
+		identities: make(map[int32]sqlc.UserIdentity),
+	}
+}
+
+func (r *fakeIdentityRepo) FindByUserID(ctx context.Context, userID int32) (sqlc.UserIdentity, error) {
+	identity, ok := r.identities[userID]
+	if !ok {
+		return sqlc.UserIdentity{}, pgx.ErrNoRows
+	}
+	return identity, nil
+}
+
+func (r *fakeIdentityRepo) FindUser(ctx context.Context, provider, subject string) (sqlc.User, error) {
+	user, ok := r.users[provider+":"+subject]
+	if !ok {
+		return sqlc.User{}, pgx.ErrNoRows
+	}
+	return user, nil
+}
+
+func (r *fakeIdentityRepo) Provision(ctx context.Context, userParams sqlc.CreateUserParams, provider, subject string) (sqlc.User, error) {
+	r.provisioned = append(r.provisioned, userParams)
+
+	user := sqlc.User{
+		UserID:       int32(len(r.provisioned)),
+		UserUuid:     uuid.New(),
+		UserEmail:    userParams.UserEmail,
+		UserPassword: userParams.UserPassword,
+		UserFullname: userParams.UserFullname,
+		UserStatus:   userParams.UserStatus,
+		UserLevel:    userParams.UserLevel,
+	}
+	r.users[provider+":"+subject] = user
+	r.identities[user.UserID] = sqlc.UserIdentity{UserID: user.UserID, IdentityProvider: provider, IdentitySubject: subject}
+	return user, nil
+}
+
+func (r *fakeIdentityRepo) Sync(ctx context.Context, userID int32, fullname string, level int32) (sqlc.User, error) {
+	r.synced++
+
+	identity := r.identities[userID]
+	key := identity.IdentityProvider + ":" + identity.IdentitySubject
+	user := r.users[key]
+	user.UserFullname = fullname
+	user.UserLevel = level
+	r.users[key] = user
+	return user, nil
+}
+
+// fakeUserRepo only answers email lookups, other calls panic on the nil interface
+type fakeUserRepo struct {
+	repository.UserRepository
+	users map[string]sqlc.User
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (sqlc.User, error) {
+	user, ok := r.users[email]
+	if !ok {
+		return sqlc.User{}, pgx.ErrNoRows
+	}
+	return user, nil
+}
+
+// fakeClosureRepo only knows the closed accounts it was given
+type fakeClosureRepo struct {
+	repository.AccountClosureRepository
+	closed map[uuid.UUID]sqlc.User
+}
+
+func (r *fakeClosureRepo) FindClosedUserByUUID(ctx context.Context, userUuid uuid.UUID) (sqlc.User, error) {
+	user, ok := r.closed[userUuid]
+	if !ok {
+		return sqlc.User{}, pgx.ErrNoRows
+	}
+	return user, nil
+}
+
+type fakeCache struct {
+	cache.RedisCacheService
+}
+
+func (fakeCache) Delete(keys ...string) error { return nil }
+
+func (fakeCache) Clear(pattern string) error { return nil }
+
+type stubAuthenticator struct {
+	result Authentication
+	err    error
+	calls  int
+}
+
+func (s *stubAuthenticator) Authenticate(ctx context.Context, email, password string) (Authentication, error) {
+	s.calls++
+	return s.result, s.err
+}
+
+func newTestDirectory() *directorytest.Directory {
+	dir := directorytest.New(directory.Config{
+		BaseDN:         "ou=people,dc=example,dc=org",
+		UserFilter:     "(&(objectClass=person)(mail=%s))",
+		EmailAttribute: "mail",
+		NameAttribute:  "cn",
+		GroupAttribute: "memberOf",
+		Timeout:        time.Second,
+	})
+	dir.AddUser(aliceDN, "alice-secret", map[string][]string{
+		"mail":     {"Alice@Example.org"},
+		"cn":       {"Alice Example"},
+		"memberOf": {adminsGroup},
+	})
+	return dir
+}
+
+func newTestLDAPAuthenticator(dir *directorytest.Directory, identityRepo *fakeIdentityRepo, userRepo *fakeUserRepo) Authenticator {
+	return newTestLDAPAuthenticatorWithClosures(dir, identityRepo, userRepo, &fakeClosureRepo{closed: map[uuid.UUID]sqlc.User{}})
+}
+
+func newTestLDAPAuthenticatorWithClosures(dir *directorytest.Directory, identityRepo *fakeIdentityRepo, userRepo *fakeUserRepo, closureRepo *fakeClosureRepo) Authenticator {
+	if userRepo == nil {
+		userRepo = &fakeUserRepo{users: map[string]sqlc.User{}}
+	}
+	return NewLDAPAuthenticator(dir.Client(), userRepo, closureRepo, identityRepo, fakeCache{})
+}
+
+func assertErrorCode(t *testing.T, err error, code utils.ErrorCode) {
+	t.Helper()
+
+	var appErr *utils.AppError
+	if !errors.As(err, &appErr) || appErr.Code != code {
+		t.Fatalf("error = %v, want code %v", err, code)
+	}
+}
+
+func TestLdapLevelForGroups(t *testing.T) {
+	tests := []struct {
+		name         string
+		mapping      string
+		defaultLevel string
+		groups       []string
+		level        int32
+		ok           bool
+	}{
+		{"first mapping wins", adminsGroup + ":1;" + staffGroup + ":2", "", []string{staffGroup, adminsGroup}, 1, true},
+		{"group dn is case insensitive", staffGroup + ":2", "", []string{"CN=Staff,OU=Groups,DC=Example,DC=Org"}, 2, true},
+		{"invalid entries are skipped", adminsGroup + ":x;" + adminsGroup + ":0;" + staffGroup + ":2", "", []string{adminsGroup, staffGroup}, 2, true},
+		{"unmapped user gets the default", adminsGroup + ":1", "3", []string{"cn=other,dc=example,dc=org"}, 3, true},
+		{"unmapped user is refused without default", adminsGroup + ":1", "", []string{"cn=other,dc=example,dc=org"}, 0, false},
+		{"no groups at all", "", "0", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LDAP_GROUP_LEVELS", tt.mapping)
+			t.Setenv("LDAP_DEFAULT_LEVEL", tt.defaultLevel)
+
+			level, ok := ldapLevelForGroups(tt.groups)
+			if level != tt.level || ok != tt.ok {
+				t.Errorf("ldapLevelForGroups() = %d, %v, want %d, %v", level, ok, tt.level, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLDAPAuthenticatorProvisionsThenSyncs(t *testing.T) {
+	t.Setenv("LDAP_GROUP_LEVELS", adminsGroup+":1;"+staffGroup+":2")
+	t.Setenv("LDAP_DEFAULT_LEVEL", "")
+
+	dir := newTestDirectory()
+	identityRepo := newFakeIdentityRepo()
+	authenticator := newTestLDAPAuthenticator(dir, identityRepo, nil)
+
+	result, err := authenticator.Authenticate(context.Background(), "alice@example.org", "alice-secret")
+	if err != nil {
+		t.Fatalf("first login error = %v", err)
+	}
+	if result.Method != AuthMethodLDAP {
+		t.Errorf("Method = %q, want %q", result.Method, AuthMethodLDAP)
+	}
+	if len(identityRepo.provisioned) != 1 {
+		t.Fatalf("provisioned %d accounts, want 1", len(identityRepo.provisioned))
+	}
+
+	params := identityRepo.provisioned[0]
+	if params.UserEmail != "alice@example.org" || params.UserFullname != "Alice Example" || params.UserLevel != 1 || params.UserStatus != UserStatusActive {
+		t.Errorf("provisioned %+v", params)
+	}
+	if params.UserPassword != unusablePassword {
+		t.Errorf("directory account got a usable password")
+	}
+
+	// Nothing changed in the directory, the account is used as is
+	if _, err := authenticator.Authenticate(context.Background(), "alice@example.org", "alice-secret"); err != nil {
+		t.Fatalf("second login error = %v", err)
+	}
+	if len(identityRepo.provisioned) != 1 || identityRepo.synced != 0 {
+		t.Errorf("provisioned %d, synced %d, want 1, 0", len(identityRepo.provisioned), identityRepo.synced)
+	}
+
+	// Renamed and moved to another group
+	dir.AddUser(aliceDN, "alice-secret", map[string][]string{
+		"mail":     {"alice@example.org"},
+		"cn":       {"Alice Renamed"},
+		"memberOf": {staffGroup},
+	})
+	result, err = authenticator.Authenticate(context.Background(), "alice@example.org", "alice-secret")
+	if err != nil {
+		t.Fatalf("third login error = %v", err)
+	}
+	if identityRepo.synced != 1 {
+		t.Errorf("synced %d times, want 1", identityRepo.synced)
+	}
+	if result.User.UserFullname != "Alice Renamed" || result.User.UserLevel != 2 {
+		t.Errorf("synced user = %q level %d", result.User.UserFullname, result.User.UserLevel)
+	}
+}
+
+func TestLDAPAuthenticatorInvalidPassword(t *testing.T) {
+	t.Setenv("LDAP_GROUP_LEVELS", adminsGroup+":1")
+
+	known := sqlc.User{UserID: 7, UserEmail: "alice@example.org"}
+	userRepo := &fakeUserRepo{users: map[string]sqlc.User{known.UserEmail: known}}
+	authenticator := newTestLDAPAuthenticator(newTestDirectory(), newFakeIdentityRepo(), userRepo)
+
+	result, err := authenticator.Authenticate(context.Background(), "alice@example.org", "wrong")
+	if !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("error = %v, want ErrInvalidPassword", err)
+	}
+	if result.User.UserID != known.UserID {
+		t.Errorf("known user is not returned for the lockout counters")
+	}
+}
+
+func TestLDAPAuthenticatorRefusesUnmappedGroups(t *testing.T) {
+	t.Setenv("LDAP_GROUP_LEVELS", staffGroup+":2")
+	t.Setenv("LDAP_DEFAULT_LEVEL", "0")
+
+	identityRepo := newFakeIdentityRepo()
+	authenticator := newTestLDAPAuthenticator(newTestDirectory(), identityRepo, nil)
+
+	_, err := authenticator.Authenticate(context.Background(), "alice@example.org", "alice-secret")
+	assertErrorCode(t, err, utils.ForbiddenError)
+	if len(identityRepo.provisioned) != 0 {
+		t.Errorf("refused user was provisioned")
+	}
+}
+
+func TestLDAPAuthenticatorDeletedAccounts(t *testing.T) {
+	t.Setenv("LDAP_GROUP_LEVELS", adminsGroup+":1")
+
+	identityRepo := newFakeIdentityRepo()
+	closureRepo := &fakeClosureRepo{closed: map[uuid.UUID]sqlc.User{}}
+	authenticator := newTestLDAPAuthenticatorWithClosures(newTestDirectory(), identityRepo, nil, closureRepo)
+
+	if _, err := authenticator.Authenticate(context.Background(), "alice@example.org", "alice-secret"); err != nil {
+		t.Fatalf("first login error = %v", err)
+	}
+	key := IdentityProviderLDAP + ":" + aliceDN
+	user := identityRepo.users[key]
+	user.UserDeletedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	identityRepo.users[key] = user
+
+	// Deleted by an administrator, the login is refused instead of provisioning a second account
+	_, err := authenticator.Authenticate(context.Background(), "alice@example.org", "alice-secret")
+	assertErrorCode(t, err, utils.AccountInactiveError)
+	if len(identityRepo.provisioned) != 1 {
+		t.Errorf("deleted account was provisioned again")
+	}
+
+	// Closed by its owner, the login goes on to restore it
+	closureRepo.closed[user.UserUuid] = user
+	result, err := authenticator.Authenticate(context.Background(), "alice@example.org", "alice-secret")
+	if err != nil {
+		t.Fatalf("closed account login error = %v", err)
+	}
+	if !result.Closed || result.User.UserID != user.UserID {
+		t.Errorf("closed account is not handed over for restore: %+v", result)
+	}
+}
+
+func TestAuthenticateFallsThroughUnknownAccounts(t *testing.T) {
+	t.Setenv("LDAP_GROUP_LEVELS", adminsGroup+":1")
+
+	local := &stubAuthenticator{result: Authentication{User: sqlc.User{UserID: 42}, Method: AuthMethodPassword}}
+	as := &authService{authenticators: []Authenticator{
+		newTestLDAPAuthenticator(newTestDirectory(), newFakeIdentityRepo(), nil),
+		local,
+	}}
+
+	result, err := as.authenticate(context.Background(), "bob@example.org", "secret")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if local.calls != 1 || result.User.UserID != 42 {
+		t.Errorf("unknown directory user did not reach the next authenticator")
+	}
+
+	local.err = ErrUnknownAccount
+	if _, err := as.authenticate(context.Background(), "bob@example.org", "secret"); !errors.Is(err, ErrUnknownAccount) {
+		t.Errorf("error = %v, want ErrUnknownAccount when nobody knows the email", err)
+	}
+
+	// A directory user with a wrong password must not be tried against the local hash
+	local.calls = 0
+	if _, err := as.authenticate(context.Background(), "alice@example.org", "wrong"); !errors.Is(err, ErrInvalidPassword) {
+		t.Errorf("error = %v, want ErrInvalidPassword", err)
+	}
+	if local.calls != 0 {
+		t.Errorf("next authenticator was asked after the directory refused the password")
+	}
+}
+
+// counterCache keeps the lockout counters in memory, nothing is ever locked or delayed
+type counterCache struct {
+	fakeCache
+	counters map[string]int64
+}
+
+func (c *counterCache) Get(key string, dest any) error { return errors.New("cache miss") }
+
+func (c *counterCache) Set(key string, value any, ttl time.Duration) error { return nil }
+
+func (c *counterCache) Exited(key string) (bool, error) { return false, nil }
+
+func (c *counterCache) TTL(key string) (time.Duration, error) { return 0, nil }
+
+func (c *counterCache) Increment(key string, ttl time.Duration) (int64, error) {
+	c.counters[key]++
+	return c.counters[key], nil
+}
+
+func TestConfirmPasswordUsesDirectory(t *testing.T) {
+	t.Setenv("LDAP_GROUP_LEVELS", adminsGroup+":1")
+
+	identityRepo := newFakeIdentityRepo()
+	authenticator := newTestLDAPAuthenticator(newTestDirectory(), identityRepo, nil)
+	cacheService := &counterCache{counters: make(map[string]int64)}
+	as := &authService{identityRepo: identityRepo, authenticators: []Authenticator{authenticator}, cacheService: cacheService}
+
+	result, err := authenticator.Authenticate(context.Background(), "alice@example.org", "alice-secret")
+	if err != nil {
+		t.Fatalf("login error = %v", err)
+	}
+	user := result.User
+
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+
+	if err := as.ConfirmPassword(ctx, user, "alice-secret", SecurityEventAccountClosed); err != nil {
+		t.Fatalf("directory password error = %v", err)
+	}
+	if len(cacheService.counters) != 0 {
+		t.Errorf("a correct password was counted as a failure")
+	}
+
+	assertErrorCode(t, as.ConfirmPassword(ctx, user, "wrong", SecurityEventAccountClosed), utils.UnauthorizedError)
+	if cacheService.counters[loginFailAccountKey(user.UserEmail)] != 1 {
+		t.Errorf("wrong password was not counted towards the lockout")
+	}
+
+	// The local hash of a directory account is never the one to change
+	assertErrorCode(t, as.CheckLocalPassword(context.Background(), user), utils.ForbiddenError)
+}
@@ -0,0 +1,192 @@
+package v1service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/hasher"
+	"gin/user-management-api/pkg/loggers"
+	"gin/user-management-api/pkg/mail"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var SignInReportTTL = 7 * 24 * time.Hour
+
+// SignInReport is stored under sign_in_report:<token> so the "this wasn't me" link can undo a sign-in
+type SignInReport struct {
+	UserUUID    string `json:"user_uuid"`
+	Fingerprint string `json:"fingerprint"`
+	Network     string `json:"network"`
+}
+
+// KNOWN_DEVICE_TTL_DAYS is how long a device or network is remembered after its last sign-in
+func knownDeviceTTL() time.Duration {
+	days := utils.GetIntEnv("KNOWN_DEVICE_TTL_DAYS", 90)
+	if days <= 0 {
+		days = 90
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func knownDeviceKey(userUUID, fingerprint string) string {
+	return "known_device:" + userUUID + ":" + fingerprint
+}
+
+func knownNetworkKey(userUUID, network string) string {
+	return "known_network:" + userUUID + ":" + network
+}
+
+// knownDevicesMarkerKey never expires, it tells a first sign-in apart from a sign-in on a new device
+func knownDevicesMarkerKey(userUUID string) string {
+	return "known_devices:" + userUUID
+}
+
+func deviceFingerprint(userAgent string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(userAgent))))
+	return hex.EncodeToString(sum[:16])
+}
+
+// ipNetwork groups addresses by /24 for IPv4 and /48 for IPv6 so a new address from the same provider is not reported
+func ipNetwork(ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ip
+	}
+
+	bits := 48
+	if addr.Unmap().Is4() {
+		addr = addr.Unmap()
+		bits = 24
+	}
+
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ip
+	}
+	return prefix.String()
+}
+
+// checkNewDevice remembers the device and network of a successful sign-in and emails the user when one of them is new
+func (as *authService) checkNewDevice(ctx *gin.Context, user sqlc.User) {
+	userUUID := user.UserUuid.String()
+	ip := as.getClientIP(ctx)
+	fingerprint := deviceFingerprint(ctx.Request.UserAgent())
+	network := ipNetwork(ip)
+	ttl := knownDeviceTTL()
+
+	seenBefore, err := as.cacheService.Exited(knownDevicesMarkerKey(userUUID))
+	if err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to check known devices")
+		return
+	}
+
+	knownDevice, _ := as.cacheService.Exited(knownDeviceKey(userUUID, fingerprint))
+	knownNetwork, _ := as.cacheService.Exited(knownNetworkKey(userUUID, network))
+
+	now := time.Now()
+	if err := as.cacheService.Set(knownDeviceKey(userUUID, fingerprint), now, ttl); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to remember device")
+	}
+	if err := as.cacheService.Set(knownNetworkKey(userUUID, network), now, ttl); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to remember network")
+	}
+	if !seenBefore {
+		as.cacheService.Set(knownDevicesMarkerKey(userUUID), now, 0)
+		return
+	}
+
+	if knownDevice && knownNetwork {
+		return
+	}
+
+	loggers.Log.Warn().
+		Str("event", "new_device_sign_in").
+		Str("user_uuid", userUUID).
+		Str("client_ip", ip).
+		Bool("new_device", !knownDevice).
+		Bool("new_network", !knownNetwork).
+		Msg("Sign-in from a new device or network")
+
+	as.sendNewSignInEmail(ctx, user, SignInReport{UserUUID: userUUID, Fingerprint: fingerprint, Network: network}, ip)
+}
+
+func (as *authService) sendNewSignInEmail(ctx *gin.Context, user sqlc.User, report SignInReport, ip string) {
+	token, err := utils.GenerateRandomString(32)
+	if err != nil {
+		loggers.Log.Error().Err(err).Msg("Failed to generate sign-in report token")
+		return
+	}
+
+	if err := as.cacheService.Set("sign_in_report:"+token, report, SignInReportTTL); err != nil {
+		loggers.Log.Error().Err(err).Msg("Failed to store sign-in report token")
+		return
+	}
+
+	reportLink := fmt.Sprintf("view-to-report-sign-in?token=%s", token)
+	mailContent := &mail.Email{
+		To: []mail.Address{
+			{Email: user.UserEmail, Name: user.UserFullname},
+		},
+		Subject:  "New sign-in to your account",
+		Text:     fmt.Sprintf("Hi %s, \n\n Your account was signed in from a new device or location. \n Time: %s \n IP address: %s \n Device: %s \n\n If this was you, you can ignore this email. If it wasn't, click the link below to sign out everywhere and reset your password: \n%s\n\n The link will expire in %d days. \n\n Best regard, \n Code With HuyDo", user.UserFullname, time.Now().UTC().Format("2006-01-02 15:04:05 MST"), ip, ctx.Request.UserAgent(), reportLink, int(SignInReportTTL.Hours()/24)),
+		Category: "security",
+	}
+
+	if err := as.rabbitmq.Publish(ctx.Request.Context(), "auth_email_queue", mailContent); err != nil {
+		loggers.Log.Error().Err(err).Msg("Failed to send new sign-in email")
+	}
+}
+
+// ReportSignIn handles the "this wasn't me" link: every session is revoked, the password is replaced
+// with a random one and a reset link is sent, so the old password cannot be used again
+func (as *authService) ReportSignIn(ctx *gin.Context, token string) error {
+	context := ctx.Request.Context()
+
+	var report SignInReport
+	if err := as.cacheService.Get("sign_in_report:"+token, &report); err != nil || report.UserUUID == "" {
+		return utils.NewError(utils.NotFoundError, "Invalid or expired token")
+	}
+	as.cacheService.Delete("sign_in_report:" + token)
+
+	userUuid, err := uuid.Parse(report.UserUUID)
+	if err != nil {
+		return utils.WrapError(utils.InternalServerError, "Uuid is invalid", err)
+	}
+
+	user, err := as.userRepo.FindByUUID(context, userUuid)
+	if err != nil {
+		return utils.NewError(utils.NotFoundError, "User not found")
+	}
+
+	if err := as.tokenService.RevokeAllSessions(report.UserUUID); err != nil {
+		return utils.WrapError(utils.InternalServerError, "Unable to revoke sessions", err)
+	}
+
+	as.cacheService.Delete(knownDeviceKey(report.UserUUID, report.Fingerprint), knownNetworkKey(report.UserUUID, report.Network))
+
+	randomPassword, err := utils.GenerateRandomString(32)
+	if err != nil {
+		return utils.WrapError(utils.InternalServerError, "Failed to generate password", err)
+	}
+	hashPassword, err := hasher.Hash(randomPassword)
+	if err != nil {
+		return utils.WrapError(utils.InternalServerError, "Failed to hash password", err)
+	}
+	if err := as.userRepo.UpdatePasswordHash(context, user.UserID, hashPassword); err != nil {
+		return utils.WrapError(utils.InternalServerError, "Failed to reset password", err)
+	}
+
+	if err := as.sendPasswordResetEmail(ctx, user); err != nil {
+		return err
+	}
+
+	as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventSignInReported, Outcome: SecurityOutcomeSuccess, UserID: &user.UserID, Email: user.UserEmail})
+	return nil
+}
@@ -0,0 +1,212 @@
+package v1service
+
+import (
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/loggers"
+	"gin/user-management-api/pkg/mail"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	EmailChangeTTL      = 24 * time.Hour
+	EmailChangeCooldown = 2 * time.Minute
+)
+
+// EmailChange is stored under email_change:<token> until it is confirmed, cancelled or expires
+type EmailChange struct {
+	UserUUID    string `json:"user_uuid"`
+	OldEmail    string `json:"old_email"`
+	NewEmail    string `json:"new_email"`
+	CancelToken string `json:"cancel_token"`
+}
+
+func emailChangeKey(token string) string {
+	return "email_change:" + token
+}
+
+func emailChangeCancelKey(cancelToken string) string {
+	return "email_change_cancel:" + cancelToken
+}
+
+// emailChangeUserKey points at the pending request so a new request replaces the previous one
+func emailChangeUserKey(userUUID string) string {
+	return "email_change_user:" + userUUID
+}
+
+// RequestEmailChange emails a confirmation link to the new address and a cancel link to the current one
+func (as *authService) RequestEmailChange(ctx *gin.Context, userUuid uuid.UUID, newEmail, password string) error {
+	context := ctx.Request.Context()
+	newEmail = utils.NormalizeString(newEmail)
+
+	user, err := as.findUserByUUID(context, userUuid)
+	if err != nil {
+		return err
+	}
+
+	// Email của tài khoản directory do directory quản lý
+	if err := as.CheckLocalPassword(context, user); err != nil {
+		return err
+	}
+
+	// Mật khẩu sai được tính vào lockout như khi đăng nhập, một access token bị lộ không dùng để dò mật khẩu được
+	if err := as.ConfirmPassword(ctx, user, password, SecurityEventEmailChangeRequest); err != nil {
+		return err
+	}
+
+	if newEmail == user.UserEmail {
+		return utils.NewError(utils.BadRequestError, "New email must be different from the current email")
+	}
+
+	rateLimitKey := fmt.Sprintf("email_change:ratelimit:%s", user.UserUuid)
+	if exists, err := as.cacheService.Exited(rateLimitKey); err == nil && exists {
+		return utils.NewError(utils.TooManyRequestsError, "Please wait before requesting another email change")
+	}
+
+	if _, err := as.userRepo.GetByEmail(context, newEmail); err == nil {
+		return utils.NewError(utils.ConflictError, "Email already exists")
+	}
+
+	token, err := utils.GenerateRandomString(32)
+	if err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to generate confirmation token")
+	}
+	cancelToken, err := utils.GenerateRandomString(32)
+	if err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to generate cancel token")
+	}
+
+	as.discardEmailChange(user.UserUuid.String())
+
+	change := EmailChange{
+		UserUUID:    user.UserUuid.String(),
+		OldEmail:    user.UserEmail,
+		NewEmail:    newEmail,
+		CancelToken: cancelToken,
+	}
+	if err := as.cacheService.Set(emailChangeKey(token), change, EmailChangeTTL); err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to store email change")
+	}
+	if err := as.cacheService.Set(emailChangeCancelKey(cancelToken), token, EmailChangeTTL); err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to store email change")
+	}
+	if err := as.cacheService.Set(emailChangeUserKey(change.UserUUID), token, EmailChangeTTL); err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to store email change")
+	}
+	if err := as.cacheService.Set(rateLimitKey, "1", EmailChangeCooldown); err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to store rate limit email change")
+	}
+
+	confirmMail := &mail.Email{
+		To: []mail.Address{
+			{Email: newEmail, Name: user.UserFullname},
+		},
+		Subject: "Confirm your new email address",
+		Text:    fmt.Sprintf("Hi %s, \n\n Please click the link below to use this address for your account: \n%s\n\n The link will expire in 24 hours. \n\n Best regard, \n Code With HuyDo", user.UserFullname, fmt.Sprintf("view-to-confirm-email-change?token=%s", token)),
+	}
+	if err := as.rabbitmq.Publish(context, "auth_email_queue", confirmMail); err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to send confirmation email")
+	}
+
+	noticeMail := &mail.Email{
+		To: []mail.Address{
+			{Email: user.UserEmail, Name: user.UserFullname},
+		},
+		Subject: "Your email address is being changed",
+		Text:    fmt.Sprintf("Hi %s, \n\n A request was made to change the email address of your account to %s. \n If this wasn't you, click the link below to cancel it and consider changing your password: \n%s\n\n Best regard, \n Code With HuyDo", user.UserFullname, newEmail, fmt.Sprintf("view-to-cancel-email-change?token=%s", cancelToken)),
+	}
+	if err := as.rabbitmq.Publish(context, "auth_email_queue", noticeMail); err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to send email change notice")
+	}
+
+	as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventEmailChangeRequest, Outcome: SecurityOutcomeSuccess, UserID: &user.UserID, Email: user.UserEmail})
+	return nil
+}
+
+// ConfirmEmailChange swaps the address and signs out every session, tokens still carry the old email
+func (as *authService) ConfirmEmailChange(ctx *gin.Context, token string) error {
+	context := ctx.Request.Context()
+
+	var change EmailChange
+	if err := as.cacheService.Get(emailChangeKey(token), &change); err != nil || change.UserUUID == "" {
+		return utils.NewError(utils.NotFoundError, "Invalid or expired token")
+	}
+	as.discardEmailChange(change.UserUUID)
+
+	userUuid, err := uuid.Parse(change.UserUUID)
+	if err != nil {
+		return utils.WrapError(utils.InternalServerError, "Uuid is invalid", err)
+	}
+
+	current, err := as.findUserByUUID(context, userUuid)
+	if err != nil {
+		return err
+	}
+	if current.UserEmail != change.OldEmail {
+		return utils.NewError(utils.NotFoundError, "Invalid or expired token")
+	}
+
+	user, err := as.userRepo.UpdateEmail(context, userUuid, change.NewEmail)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return utils.NewError(utils.ConflictError, "Email already exists")
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewError(utils.NotFoundError, "User not found")
+		}
+		return utils.WrapError(utils.InternalServerError, "Failed to change email", err)
+	}
+
+	if err := as.tokenService.RevokeAllSessions(change.UserUUID); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to revoke sessions after email change")
+	}
+
+	if err := as.cacheService.Clear("users:*"); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to clear cache")
+	}
+
+	as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventEmailChange, Outcome: SecurityOutcomeSuccess, UserID: &user.UserID, Email: user.UserEmail})
+	return nil
+}
+
+func (as *authService) CancelEmailChange(ctx *gin.Context, cancelToken string) error {
+	var token string
+	if err := as.cacheService.Get(emailChangeCancelKey(cancelToken), &token); err != nil || token == "" {
+		return utils.NewError(utils.NotFoundError, "Invalid or expired token")
+	}
+
+	var change EmailChange
+	if err := as.cacheService.Get(emailChangeKey(token), &change); err != nil || change.UserUUID == "" {
+		as.cacheService.Delete(emailChangeCancelKey(cancelToken))
+		return utils.NewError(utils.NotFoundError, "Invalid or expired token")
+	}
+	as.discardEmailChange(change.UserUUID)
+
+	as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventEmailChange, Outcome: SecurityOutcomeFailure, Reason: "cancelled", UserID: as.securityEventUserID(ctx, change.UserUUID), Email: change.OldEmail})
+	return nil
+}
+
+// discardEmailChange removes the pending request of the user together with both of its links
+func (as *authService) discardEmailChange(userUUID string) {
+	var token string
+	if err := as.cacheService.Get(emailChangeUserKey(userUUID), &token); err != nil || token == "" {
+		return
+	}
+
+	keys := []string{emailChangeUserKey(userUUID), emailChangeKey(token)}
+	var change EmailChange
+	if err := as.cacheService.Get(emailChangeKey(token), &change); err == nil && change.CancelToken != "" {
+		keys = append(keys, emailChangeCancelKey(change.CancelToken))
+	}
+
+	if err := as.cacheService.Delete(keys...); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to delete email change")
+	}
+}
@@ -0,0 +1,173 @@
+package v1service
+
+import (
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/loggers"
+	"gin/user-management-api/pkg/mail"
+	"math"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	LoginFailureWindow   = 15 * time.Minute
+	MaxLoginAttempt      = 5
+	MaxLoginAttemptPerIP = 20
+	AccountLockDuration  = 15 * time.Minute
+	LoginFreeAttempts    = 2
+	LoginMaxDelay        = 30 * time.Second
+)
+
+func loginFailIPKey(ip string) string {
+	return "login_fail:ip:" + ip
+}
+
+func loginFailAccountKey(email string) string {
+	return "login_fail:account:" + email
+}
+
+func loginDelayIPKey(ip string) string {
+	return "login_delay:ip:" + ip
+}
+
+func loginDelayAccountKey(email string) string {
+	return "login_delay:account:" + email
+}
+
+func loginLockKey(email string) string {
+	return "login_lock:" + email
+}
+
+// loginDelay doubles the wait for every failure past the free attempts
+func loginDelay(failures int64) time.Duration {
+	extra := failures - int64(LoginFreeAttempts)
+	if extra <= 0 {
+		return 0
+	}
+
+	delay := time.Duration(math.Pow(2, float64(extra-1))) * time.Second
+	if delay > LoginMaxDelay || delay <= 0 {
+		return LoginMaxDelay
+	}
+	return delay
+}
+
+func (as *authService) checkLoginAllowed(ip, email string) error {
+	var ipFailures int64
+	if err := as.cacheService.Get(loginFailIPKey(ip), &ipFailures); err == nil && ipFailures >= int64(MaxLoginAttemptPerIP) {
+		return utils.NewError(utils.TooManyRequestsError, "Too many login attempts from this address. Please try again later")
+	}
+
+	locked, err := as.cacheService.Exited(loginLockKey(email))
+	if err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to check account lock")
+	}
+	if locked {
+		remaining, _ := as.cacheService.TTL(loginLockKey(email))
+		return utils.NewError(utils.ForbiddenError, fmt.Sprintf("Account is temporarily locked. Please try again in %d minutes", int(math.Ceil(remaining.Minutes()))))
+	}
+
+	for _, key := range []string{loginDelayIPKey(ip), loginDelayAccountKey(email)} {
+		remaining, err := as.cacheService.TTL(key)
+		if err == nil && remaining > 0 {
+			return utils.NewError(utils.TooManyRequestsError, fmt.Sprintf("Too many login attempts. Please wait %d seconds before retrying", int(math.Ceil(remaining.Seconds()))))
+		}
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts unknown emails too, so a lockout does not reveal whether the account exists
+func (as *authService) recordLoginFailure(ctx *gin.Context, ip, email string, user *sqlc.User) {
+	ipFailures, err := as.cacheService.Increment(loginFailIPKey(ip), LoginFailureWindow)
+	if err != nil {
+		loggers.Log.Error().Err(err).Msg("Failed to record login failure")
+		return
+	}
+	if delay := loginDelay(ipFailures); delay > 0 {
+		as.cacheService.Set(loginDelayIPKey(ip), ipFailures, delay)
+	}
+
+	accountFailures, err := as.cacheService.Increment(loginFailAccountKey(email), LoginFailureWindow)
+	if err != nil {
+		loggers.Log.Error().Err(err).Msg("Failed to record login failure")
+		return
+	}
+
+	if accountFailures < int64(MaxLoginAttempt) {
+		if delay := loginDelay(accountFailures); delay > 0 {
+			as.cacheService.Set(loginDelayAccountKey(email), accountFailures, delay)
+		}
+		return
+	}
+
+	if err := as.cacheService.Set(loginLockKey(email), accountFailures, AccountLockDuration); err != nil {
+		loggers.Log.Error().Err(err).Msg("Failed to lock account")
+		return
+	}
+	as.cacheService.Delete(loginFailAccountKey(email), loginDelayAccountKey(email))
+
+	loggers.Log.Warn().
+		Str("event", "account_locked").
+		Str("email", email).
+		Str("client_ip", ip).
+		Int64("failures", accountFailures).
+		Msg("Account locked after too many failed logins")
+
+	event := SecurityEvent{Type: SecurityEventAccountLocked, Outcome: SecurityOutcomeFailure, Reason: "too_many_failed_logins", Email: email}
+	if user != nil {
+		event.UserID = &user.UserID
+	}
+	as.recordSecurityEvent(ctx, event)
+
+	if user != nil {
+		as.sendAccountLockedEmail(ctx, *user, ip)
+	}
+}
+
+func (as *authService) clearLoginFailures(ip, email string) {
+	if err := as.cacheService.Delete(loginFailAccountKey(email), loginDelayAccountKey(email), loginDelayIPKey(ip)); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to clear login failures")
+	}
+}
+
+func (as *authService) sendAccountLockedEmail(ctx *gin.Context, user sqlc.User, ip string) {
+	mailContent := &mail.Email{
+		To: []mail.Address{
+			{Email: user.UserEmail},
+		},
+		Subject:  "Your account has been temporarily locked",
+		Text:     fmt.Sprintf("Hi %s, \n\n Your account was locked for %d minutes after %d failed sign-in attempts, the last one from IP %s. \n If this was not you, please reset your password. \n\n Best regard, \n Code With HuyDo", user.UserEmail, int(AccountLockDuration.Minutes()), MaxLoginAttempt, ip),
+		Category: "security",
+	}
+
+	if err := as.rabbitmq.Publish(ctx.Request.Context(), "auth_email_queue", mailContent); err != nil {
+		loggers.Log.Error().Err(err).Msg("Failed to send account locked email")
+	}
+}
+
+// ConfirmPassword counts wrong passwords of a signed in user towards the login lockout,
+// a leaked access token must not allow guessing the password through profile changes.
+// The password goes through the authenticators like a login, so directory accounts confirm with their directory password
+func (as *authService) ConfirmPassword(ctx *gin.Context, user sqlc.User, password, eventType string) error {
+	ip := as.getClientIP(ctx)
+	if err := as.checkLoginAllowed(ip, user.UserEmail); err != nil {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: eventType, Outcome: SecurityOutcomeFailure, Reason: "throttled", UserID: &user.UserID, Email: user.UserEmail})
+		return err
+	}
+
+	result, err := as.authenticate(ctx.Request.Context(), user.UserEmail, password)
+	if err != nil && !errors.Is(err, ErrInvalidPassword) && !errors.Is(err, ErrUnknownAccount) {
+		return err
+	}
+	if err != nil || result.User.UserID != user.UserID {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: eventType, Outcome: SecurityOutcomeFailure, Reason: "invalid_password", UserID: &user.UserID, Email: user.UserEmail})
+		as.recordLoginFailure(ctx, ip, user.UserEmail, &user)
+		return utils.NewError(utils.UnauthorizedError, "Password is incorrect")
+	}
+	return nil
+}
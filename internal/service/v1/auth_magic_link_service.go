@@ -0,0 +1,172 @@
+package v1service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/mail"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	MagicLinkTTL      = 15 * time.Minute
+	MagicLinkCooldown = 2 * time.Minute
+)
+
+// MagicLink is stored under magic:<token> until it is consumed or expires
+type MagicLink struct {
+	UserUUID        string `json:"user_uuid"`
+	DeviceName      string `json:"device_name"`
+	DeviceTokenHash string `json:"device_token_hash"`
+}
+
+var magicKey []byte
+
+// InitMagicLinkSecret loads MAGIC_LINK_SECRET, the HMAC key sign-in links are signed with.
+// There is no default, anyone knowing a shared default could sign links for any token
+func InitMagicLinkSecret() error {
+	secret := utils.GetEnv("MAGIC_LINK_SECRET", "")
+	if len(secret) < 32 {
+		return errors.New("MAGIC_LINK_SECRET is required and must be at least 32 bytes long")
+	}
+
+	magicKey = []byte(secret)
+	return nil
+}
+
+func magicLinkKey() []byte {
+	return magicKey
+}
+
+func signMagicToken(token string) string {
+	mac := hmac.New(sha256.New, magicLinkKey())
+	mac.Write([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// splitMagicToken rejects links whose signature does not match before touching Redis
+func splitMagicToken(signed string) (string, bool) {
+	token, signature, ok := strings.Cut(signed, ".")
+	if !ok || token == "" {
+		return "", false
+	}
+	return token, hmac.Equal([]byte(signature), []byte(signMagicToken(token)))
+}
+
+func hashDeviceToken(deviceToken string) string {
+	sum := sha256.Sum256([]byte(deviceToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// RequestMagicLink returns a device token that must be presented together with the emailed link,
+// so a link forwarded or intercepted from the mailbox cannot be used on another device
+func (as *authService) RequestMagicLink(ctx *gin.Context, email, deviceName string) (string, error) {
+	context := ctx.Request.Context()
+	email = utils.NormalizeString(email)
+
+	rateLimitKey := fmt.Sprintf("magic:ratelimit:%s", email)
+	if exists, err := as.cacheService.Exited(rateLimitKey); err == nil && exists {
+		return "", utils.NewError(utils.TooManyRequestsError, "Please wait before requesting another sign-in link")
+	}
+
+	if err := as.cacheService.Set(rateLimitKey, "1", MagicLinkCooldown); err != nil {
+		return "", utils.NewError(utils.InternalServerError, "Failed to store rate limit sign-in link")
+	}
+
+	deviceToken, err := utils.GenerateRandomString(32)
+	if err != nil {
+		return "", utils.NewError(utils.InternalServerError, "Failed to generate device token")
+	}
+
+	// Không báo lỗi khi email không tồn tại để tránh dò tài khoản
+	user, err := as.userRepo.GetByEmail(context, email)
+	if err != nil || user.UserStatus == UserStatusPendingVerification {
+		return deviceToken, nil
+	}
+	if err := as.CheckLocalPassword(context, user); err != nil {
+		return deviceToken, nil
+	}
+
+	token, err := utils.GenerateRandomString(30)
+	if err != nil {
+		return "", utils.NewError(utils.InternalServerError, "Failed to generate sign-in link")
+	}
+
+	link := MagicLink{
+		UserUUID:        user.UserUuid.String(),
+		DeviceName:      deviceName,
+		DeviceTokenHash: hashDeviceToken(deviceToken),
+	}
+	if err := as.cacheService.Set("magic:"+token, link, MagicLinkTTL); err != nil {
+		return "", utils.NewError(utils.InternalServerError, "Failed to store sign-in link")
+	}
+
+	magicLink := fmt.Sprintf("view-to-magic-link?token=%s.%s", token, signMagicToken(token))
+	mailContent := &mail.Email{
+		To: []mail.Address{
+			{Email: user.UserEmail, Name: user.UserFullname},
+		},
+		Subject: "Your sign-in link",
+		Text:    fmt.Sprintf("Hi %s, \n\n Click the link below to sign in: \n%s\n\n The link can be used once, only on the device that requested it, and will expire in 15 minutes. \n\n Best regard, \n Code With HuyDo", user.UserFullname, magicLink),
+	}
+
+	if err := as.rabbitmq.Publish(context, "auth_email_queue", mailContent); err != nil {
+		return "", utils.NewError(utils.InternalServerError, "Failed to send sign-in link")
+	}
+
+	return deviceToken, nil
+}
+
+func (as *authService) ConsumeMagicLink(ctx *gin.Context, signedToken, deviceToken string) (LoginResult, error) {
+	context := ctx.Request.Context()
+
+	token, ok := splitMagicToken(signedToken)
+	if !ok {
+		return LoginResult{}, utils.NewError(utils.UnauthorizedError, "Invalid or expired sign-in link")
+	}
+
+	// Link chỉ dùng được một lần, GETDEL để hai request cùng lúc không cùng đăng nhập được.
+	// Link mở trên thiết bị khác cũng bị huỷ
+	var link MagicLink
+	if err := as.cacheService.GetDel("magic:"+token, &link); err != nil || link.UserUUID == "" {
+		return LoginResult{}, utils.NewError(utils.UnauthorizedError, "Invalid or expired sign-in link")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(link.DeviceTokenHash), []byte(hashDeviceToken(deviceToken))) != 1 {
+		return LoginResult{}, utils.NewError(utils.UnauthorizedError, "Sign-in link was requested from another device")
+	}
+
+	userUuid, err := uuid.Parse(link.UserUUID)
+	if err != nil {
+		return LoginResult{}, utils.NewError(utils.UnauthorizedError, "Invalid or expired sign-in link")
+	}
+
+	user, err := as.findUserByUUID(context, userUuid)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	// The account may have been linked to the directory after the link was sent
+	if err := as.CheckLocalPassword(context, user); err != nil {
+		return LoginResult{}, err
+	}
+
+	challenge, err := as.createMfaChallenge(context, user, link.DeviceName, false)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	if challenge.MfaStatus != "" {
+		return challenge, nil
+	}
+
+	return as.completeLogin(ctx, user, link.DeviceName, "magic_link", false)
+}
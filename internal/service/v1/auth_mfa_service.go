@@ -0,0 +1,492 @@
+package v1service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/hasher"
+	"gin/user-management-api/pkg/loggers"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	MfaStatusRequired      = "mfa_required"
+	MfaStatusSetupRequired = "mfa_setup_required"
+)
+
+var (
+	MfaChallengeTTL   = 5 * time.Minute
+	MaxMfaAttempt     = 5
+	RecoveryCodeCount = 10
+	// User levels that must complete MFA before tokens are issued (1 - Administrator)
+	MfaRequiredLevels = []int32{1}
+)
+
+type MfaChallenge struct {
+	UserUUID   string    `json:"user_uuid"`
+	DeviceName string    `json:"device_name"`
+	Setup      bool      `json:"setup"`
+	Closed     bool      `json:"closed,omitempty"`
+	Attempts   int       `json:"attempts"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type MfaEnrollment struct {
+	Secret string
+	URL    string
+	QRCode string
+}
+
+var mfaKey []byte
+
+// InitMfaEncryptKey loads MFA_ENCRYPT_KEY, the AES key the TOTP secrets are stored with.
+// There is no default, a key shared by every deployment would not protect the secrets
+func InitMfaEncryptKey() error {
+	key := utils.GetEnv("MFA_ENCRYPT_KEY", "")
+	switch len(key) {
+	case 16, 24, 32:
+	case 0:
+		return errors.New("MFA_ENCRYPT_KEY is required")
+	default:
+		return errors.New("MFA_ENCRYPT_KEY must be 16, 24 or 32 bytes long")
+	}
+
+	mfaKey = []byte(key)
+	return nil
+}
+
+func mfaEncryptKey() []byte {
+	return mfaKey
+}
+
+func isMfaRequiredLevel(level int32) bool {
+	for _, l := range MfaRequiredLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(utils.NormalizeString(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	hashes := make([]string, 0, RecoveryCodeCount)
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+
+	for i := 0; i < RecoveryCodeCount; i++ {
+		raw, err := utils.GenerateRandomBytes(10)
+		if err != nil {
+			return nil, nil, err
+		}
+		encoded := strings.ToLower(encoding.EncodeToString(raw))
+		code := encoded[:8] + "-" + encoded[8:16]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// createMfaChallenge returns an empty result when the user can receive tokens right away
+func (as *authService) createMfaChallenge(ctx context.Context, user sqlc.User, deviceName string, closed bool) (LoginResult, error) {
+	mfa, err := as.mfaRepo.FindByUserID(ctx, user.UserID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return LoginResult{}, utils.WrapError(utils.InternalServerError, "Failed to get MFA settings", err)
+	}
+
+	challenge := MfaChallenge{
+		UserUUID:   user.UserUuid.String(),
+		DeviceName: deviceName,
+		Closed:     closed,
+		ExpiresAt:  time.Now().Add(MfaChallengeTTL),
+	}
+	status := MfaStatusRequired
+
+	if err != nil || !mfa.MfaEnabled {
+		if !isMfaRequiredLevel(user.UserLevel) {
+			return LoginResult{}, nil
+		}
+		challenge.Setup = true
+		status = MfaStatusSetupRequired
+	}
+
+	token, err := utils.GenerateRandomString(32)
+	if err != nil {
+		return LoginResult{}, utils.WrapError(utils.InternalServerError, "Failed to generate MFA token", err)
+	}
+
+	if err := as.cacheService.Set("mfa_challenge:"+token, challenge, MfaChallengeTTL); err != nil {
+		return LoginResult{}, utils.WrapError(utils.InternalServerError, "Failed to store MFA challenge", err)
+	}
+
+	return LoginResult{
+		MfaStatus: status,
+		MfaToken:  token,
+		ExpiresIn: int(MfaChallengeTTL.Seconds()),
+	}, nil
+}
+
+func (as *authService) getMfaChallenge(token string) (MfaChallenge, error) {
+	var challenge MfaChallenge
+	if err := as.cacheService.Get("mfa_challenge:"+token, &challenge); err != nil || challenge.UserUUID == "" {
+		return MfaChallenge{}, utils.NewError(utils.UnauthorizedError, "MFA token is invalid or expired")
+	}
+	return challenge, nil
+}
+
+// consumeMfaChallenge takes the challenge out of the cache so concurrent requests cannot each spend the same attempt
+func (as *authService) consumeMfaChallenge(token string) (MfaChallenge, error) {
+	var challenge MfaChallenge
+	if err := as.cacheService.GetDel("mfa_challenge:"+token, &challenge); err != nil || challenge.UserUUID == "" {
+		return MfaChallenge{}, utils.NewError(utils.UnauthorizedError, "MFA token is invalid or expired")
+	}
+	return challenge, nil
+}
+
+// restoreMfaChallenge puts a consumed challenge back for the rest of its lifetime
+func (as *authService) restoreMfaChallenge(token string, challenge MfaChallenge) {
+	if ttl := time.Until(challenge.ExpiresAt); ttl > 0 {
+		if err := as.cacheService.Set("mfa_challenge:"+token, challenge, ttl); err != nil {
+			loggers.Log.Warn().Err(err).Msg("Failed to restore MFA challenge")
+		}
+	}
+}
+
+// recordMfaFailure gets a consumed challenge, it only goes back to the cache while attempts are left
+func (as *authService) recordMfaFailure(token string, challenge MfaChallenge) error {
+	challenge.Attempts++
+	if challenge.Attempts >= MaxMfaAttempt {
+		return utils.NewError(utils.TooManyRequestsError, "Too many invalid MFA codes. Please login again")
+	}
+
+	as.restoreMfaChallenge(token, challenge)
+	return utils.NewError(utils.UnauthorizedError, "Invalid MFA code")
+}
+
+// validateTotp rejects codes that were already accepted for the user within the skew window
+func (as *authService) validateTotp(user sqlc.User, encryptedSecret, code string) (bool, error) {
+	secret, err := utils.DecryptAES(encryptedSecret, mfaEncryptKey())
+	if err != nil {
+		return false, utils.WrapError(utils.InternalServerError, "Failed to decrypt MFA secret", err)
+	}
+
+	if !totp.Validate(code, string(secret)) {
+		return false, nil
+	}
+
+	// SetNX: hai request cùng mã chỉ một request được chấp nhận
+	usedKey := fmt.Sprintf("mfa_used:%s:%s", user.UserUuid, code)
+	first, err := as.cacheService.SetNX(usedKey, "1", 90*time.Second)
+	if err != nil {
+		return false, utils.WrapError(utils.InternalServerError, "Failed to check MFA code", err)
+	}
+
+	return first, nil
+}
+
+func (as *authService) verifyMfaCode(ctx context.Context, user sqlc.User, mfa sqlc.UserMfa, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	valid, err := as.validateTotp(user, mfa.MfaSecret, code)
+	if err != nil || valid {
+		return valid, err
+	}
+
+	if !mfa.MfaEnabled {
+		return false, nil
+	}
+
+	used, err := as.mfaRepo.UseRecoveryCode(ctx, user.UserID, hashRecoveryCode(code))
+	if err != nil {
+		return false, utils.WrapError(utils.InternalServerError, "Failed to check recovery code", err)
+	}
+	return used, nil
+}
+
+func (as *authService) newMfaEnrollment(ctx context.Context, user sqlc.User) (MfaEnrollment, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      utils.GetEnv("MFA_ISSUER", "User Management API"),
+		AccountName: user.UserEmail,
+	})
+	if err != nil {
+		return MfaEnrollment{}, utils.WrapError(utils.InternalServerError, "Failed to generate MFA secret", err)
+	}
+
+	encrypted, err := utils.EncryptAES([]byte(key.Secret()), mfaEncryptKey())
+	if err != nil {
+		return MfaEnrollment{}, utils.WrapError(utils.InternalServerError, "Failed to encrypt MFA secret", err)
+	}
+
+	if _, err := as.mfaRepo.SaveSecret(ctx, user.UserID, encrypted); err != nil {
+		return MfaEnrollment{}, utils.WrapError(utils.InternalServerError, "Failed to save MFA secret", err)
+	}
+
+	img, err := key.Image(200, 200)
+	if err != nil {
+		return MfaEnrollment{}, utils.WrapError(utils.InternalServerError, "Failed to generate QR code", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return MfaEnrollment{}, utils.WrapError(utils.InternalServerError, "Failed to encode QR code", err)
+	}
+
+	return MfaEnrollment{
+		Secret: key.Secret(),
+		URL:    key.URL(),
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+func (as *authService) enableMfa(ctx context.Context, user sqlc.User) ([]string, error) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, utils.WrapError(utils.InternalServerError, "Failed to generate recovery codes", err)
+	}
+
+	if _, err := as.mfaRepo.Enable(ctx, user.UserID, hashes); err != nil {
+		return nil, utils.WrapError(utils.InternalServerError, "Failed to enable MFA", err)
+	}
+	return codes, nil
+}
+
+func (as *authService) findUserByUUID(ctx context.Context, userUuid uuid.UUID) (sqlc.User, error) {
+	user, err := as.userRepo.FindByUUID(ctx, userUuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.User{}, utils.NewError(utils.NotFoundError, "User not found")
+		}
+		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "Failed to get user", err)
+	}
+	return user, nil
+}
+
+func (as *authService) VerifyMfa(ctx *gin.Context, mfaToken, code string) (LoginResult, error) {
+	context := ctx.Request.Context()
+
+	challenge, err := as.consumeMfaChallenge(mfaToken)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	// Until a code has been checked the challenge goes back to the cache on every return
+	checked := false
+	defer func() {
+		if !checked {
+			as.restoreMfaChallenge(mfaToken, challenge)
+		}
+	}()
+
+	userUuid, err := uuid.Parse(challenge.UserUUID)
+	if err != nil {
+		return LoginResult{}, utils.WrapError(utils.InternalServerError, "Uuid is invalid", err)
+	}
+
+	user, err := as.findChallengeUser(context, userUuid, challenge.Closed)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	// Wrong codes count towards the login lockout, a new challenge per login does not buy more guesses
+	ip := as.getClientIP(ctx)
+	if err := as.checkLoginAllowed(ip, user.UserEmail); err != nil {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventLogin, Outcome: SecurityOutcomeFailure, Reason: "throttled", UserID: &user.UserID, Email: user.UserEmail})
+		return LoginResult{}, err
+	}
+
+	mfa, err := as.mfaRepo.FindByUserID(context, user.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return LoginResult{}, utils.NewError(utils.BadRequestError, "MFA setup has not been started")
+		}
+		return LoginResult{}, utils.WrapError(utils.InternalServerError, "Failed to get MFA settings", err)
+	}
+
+	if !challenge.Setup && !mfa.MfaEnabled {
+		return LoginResult{}, utils.NewError(utils.BadRequestError, "MFA is not enabled")
+	}
+
+	valid, err := as.verifyMfaCode(context, user, mfa, code)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	checked = true
+	if !valid {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventLogin, Outcome: SecurityOutcomeFailure, Reason: "invalid_mfa_code", UserID: &user.UserID, Email: user.UserEmail})
+		as.recordLoginFailure(ctx, ip, user.UserEmail, &user)
+		return LoginResult{}, as.recordMfaFailure(mfaToken, challenge)
+	}
+
+	var recoveryCodes []string
+	if !mfa.MfaEnabled {
+		if recoveryCodes, err = as.enableMfa(context, user); err != nil {
+			return LoginResult{}, err
+		}
+	}
+
+	result, err := as.completeLogin(ctx, user, challenge.DeviceName, "mfa", challenge.Closed)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	result.RecoveryCodes = recoveryCodes
+
+	return result, nil
+}
+
+func (as *authService) SetupMfa(ctx *gin.Context, mfaToken string) (MfaEnrollment, error) {
+	context := ctx.Request.Context()
+
+	challenge, err := as.getMfaChallenge(mfaToken)
+	if err != nil {
+		return MfaEnrollment{}, err
+	}
+
+	if !challenge.Setup {
+		return MfaEnrollment{}, utils.NewError(utils.BadRequestError, "MFA is already enabled")
+	}
+
+	userUuid, err := uuid.Parse(challenge.UserUUID)
+	if err != nil {
+		return MfaEnrollment{}, utils.WrapError(utils.InternalServerError, "Uuid is invalid", err)
+	}
+
+	// Closed accounts enrol during the login that restores them, like VerifyMfa
+	user, err := as.findChallengeUser(context, userUuid, challenge.Closed)
+	if err != nil {
+		return MfaEnrollment{}, err
+	}
+
+	return as.newMfaEnrollment(context, user)
+}
+
+func (as *authService) EnrollMfa(ctx *gin.Context, userUuid uuid.UUID) (MfaEnrollment, error) {
+	context := ctx.Request.Context()
+
+	user, err := as.findUserByUUID(context, userUuid)
+	if err != nil {
+		return MfaEnrollment{}, err
+	}
+
+	mfa, err := as.mfaRepo.FindByUserID(context, user.UserID)
+	if err == nil && mfa.MfaEnabled {
+		return MfaEnrollment{}, utils.NewError(utils.ConflictError, "MFA is already enabled")
+	}
+
+	return as.newMfaEnrollment(context, user)
+}
+
+func (as *authService) ConfirmMfa(ctx *gin.Context, userUuid uuid.UUID, code string) ([]string, error) {
+	context := ctx.Request.Context()
+
+	user, err := as.findUserByUUID(context, userUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	mfa, err := as.mfaRepo.FindByUserID(context, user.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewError(utils.BadRequestError, "MFA enrollment has not been started")
+		}
+		return nil, utils.WrapError(utils.InternalServerError, "Failed to get MFA settings", err)
+	}
+
+	if mfa.MfaEnabled {
+		return nil, utils.NewError(utils.ConflictError, "MFA is already enabled")
+	}
+
+	valid, err := as.validateTotp(user, mfa.MfaSecret, strings.TrimSpace(code))
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, utils.NewError(utils.BadRequestError, "Invalid MFA code")
+	}
+
+	return as.enableMfa(context, user)
+}
+
+func (as *authService) DisableMfa(ctx *gin.Context, userUuid uuid.UUID, password, code string) error {
+	context := ctx.Request.Context()
+
+	user, err := as.findUserByUUID(context, userUuid)
+	if err != nil {
+		return err
+	}
+
+	if isMfaRequiredLevel(user.UserLevel) {
+		return utils.NewError(utils.ForbiddenError, "MFA is required for your role")
+	}
+
+	if ok, _ := hasher.Verify(user.UserPassword, password); !ok {
+		return utils.NewError(utils.UnauthorizedError, "Invalid password")
+	}
+
+	mfa, err := as.mfaRepo.FindByUserID(context, user.UserID)
+	if err != nil || !mfa.MfaEnabled {
+		return utils.NewError(utils.BadRequestError, "MFA is not enabled")
+	}
+
+	valid, err := as.verifyMfaCode(context, user, mfa, code)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return utils.NewError(utils.UnauthorizedError, "Invalid MFA code")
+	}
+
+	if err := as.mfaRepo.Disable(context, user.UserID); err != nil {
+		return utils.WrapError(utils.InternalServerError, "Failed to disable MFA", err)
+	}
+	return nil
+}
+
+func (as *authService) RegenerateRecoveryCodes(ctx *gin.Context, userUuid uuid.UUID, code string) ([]string, error) {
+	context := ctx.Request.Context()
+
+	user, err := as.findUserByUUID(context, userUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	mfa, err := as.mfaRepo.FindByUserID(context, user.UserID)
+	if err != nil || !mfa.MfaEnabled {
+		return nil, utils.NewError(utils.BadRequestError, "MFA is not enabled")
+	}
+
+	valid, err := as.validateTotp(user, mfa.MfaSecret, strings.TrimSpace(code))
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, utils.NewError(utils.UnauthorizedError, "Invalid MFA code")
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, utils.WrapError(utils.InternalServerError, "Failed to generate recovery codes", err)
+	}
+
+	if err := as.mfaRepo.ReplaceRecoveryCodes(context, user.UserID, hashes); err != nil {
+		return nil, utils.WrapError(utils.InternalServerError, "Failed to save recovery codes", err)
+	}
+	return codes, nil
+}
@@ -0,0 +1,112 @@
+package v1service
+
+import (
+	"encoding/json"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/utils"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+	"github.com/redis/go-redis/v9"
+)
+
+// memoryCache stores JSON like the redis cache so challenges survive a round trip
+type memoryCache struct {
+	fakeCache
+	values map[string][]byte
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: make(map[string][]byte)}
+}
+
+func (c *memoryCache) Get(key string, dest any) error {
+	data, ok := c.values[key]
+	if !ok {
+		return redis.Nil
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (c *memoryCache) GetDel(key string, dest any) error {
+	if err := c.Get(key, dest); err != nil {
+		return err
+	}
+	delete(c.values, key)
+	return nil
+}
+
+func (c *memoryCache) Set(key string, value any, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	c.values[key] = data
+	return nil
+}
+
+func (c *memoryCache) SetNX(key string, value any, ttl time.Duration) (bool, error) {
+	if _, ok := c.values[key]; ok {
+		return false, nil
+	}
+	return true, c.Set(key, value, ttl)
+}
+
+func TestValidateTotpRejectsReplayedCode(t *testing.T) {
+	previous := mfaKey
+	mfaKey = []byte("0123456789abcdef")
+	t.Cleanup(func() { mfaKey = previous })
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "test", AccountName: "alice@example.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := utils.EncryptAES([]byte(key.Secret()), mfaEncryptKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := totp.GenerateCode(key.Secret(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	as := &authService{cacheService: newMemoryCache()}
+	user := sqlc.User{UserUuid: uuid.New()}
+
+	if valid, err := as.validateTotp(user, encrypted, code); err != nil || !valid {
+		t.Fatalf("first use = %v, %v", valid, err)
+	}
+	if valid, err := as.validateTotp(user, encrypted, code); err != nil || valid {
+		t.Errorf("replayed code = %v, %v, want rejected", valid, err)
+	}
+}
+
+func TestMfaChallengeIsConsumedOnce(t *testing.T) {
+	as := &authService{cacheService: newMemoryCache()}
+	challenge := MfaChallenge{UserUUID: uuid.NewString(), ExpiresAt: time.Now().Add(MfaChallengeTTL)}
+	as.cacheService.Set("mfa_challenge:token", challenge, MfaChallengeTTL)
+
+	got, err := as.consumeMfaChallenge("token")
+	if err != nil {
+		t.Fatalf("consume error = %v", err)
+	}
+	// A concurrent request sees no challenge while the first one checks its code
+	_, err = as.consumeMfaChallenge("token")
+	assertErrorCode(t, err, utils.UnauthorizedError)
+
+	// A wrong code puts the challenge back with one more attempt
+	assertErrorCode(t, as.recordMfaFailure("token", got), utils.UnauthorizedError)
+	got, err = as.consumeMfaChallenge("token")
+	if err != nil || got.Attempts != 1 {
+		t.Fatalf("after failure = %+v, %v", got, err)
+	}
+
+	// The last attempt does not come back
+	got.Attempts = MaxMfaAttempt - 1
+	assertErrorCode(t, as.recordMfaFailure("token", got), utils.TooManyRequestsError)
+	if _, err := as.consumeMfaChallenge("token"); err == nil {
+		t.Errorf("challenge survived the last attempt")
+	}
+}
@@ -0,0 +1,106 @@
+package v1service
+
+import (
+	"context"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/hasher"
+	"gin/user-management-api/pkg/loggers"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const PasswordStatusExpired = "password_expired"
+
+var PasswordChangeTTL = 10 * time.Minute
+
+type PasswordChangeChallenge struct {
+	UserUUID   string    `json:"user_uuid"`
+	DeviceName string    `json:"device_name"`
+	Closed     bool      `json:"closed,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// rehashPassword upgrades bcrypt hashes or outdated argon2id parameters after a successful login
+func (as *authService) rehashPassword(ctx context.Context, user sqlc.User, password string) {
+	if !hasher.NeedsRehash(user.UserPassword) {
+		return
+	}
+
+	hashPassword, err := hasher.Hash(password)
+	if err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to rehash password")
+		return
+	}
+
+	if err := as.userRepo.UpdatePasswordHash(ctx, user.UserID, hashPassword); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to store rehashed password")
+	}
+}
+
+func (as *authService) createPasswordChangeChallenge(user sqlc.User, deviceName string, closed bool) (LoginResult, error) {
+	token, err := utils.GenerateRandomString(32)
+	if err != nil {
+		return LoginResult{}, utils.WrapError(utils.InternalServerError, "Failed to generate password change token", err)
+	}
+
+	challenge := PasswordChangeChallenge{
+		UserUUID:   user.UserUuid.String(),
+		DeviceName: deviceName,
+		Closed:     closed,
+		ExpiresAt:  time.Now().Add(PasswordChangeTTL),
+	}
+
+	if err := as.cacheService.Set("password_change:"+token, challenge, PasswordChangeTTL); err != nil {
+		return LoginResult{}, utils.WrapError(utils.InternalServerError, "Failed to store password change token", err)
+	}
+
+	return LoginResult{
+		PasswordStatus: PasswordStatusExpired,
+		PasswordToken:  token,
+		ExpiresIn:      int(PasswordChangeTTL.Seconds()),
+	}, nil
+}
+
+// ChangeExpiredPassword finishes a login that was stopped because the password is too old
+func (as *authService) ChangeExpiredPassword(ctx *gin.Context, token, password string) (LoginResult, error) {
+	context := ctx.Request.Context()
+
+	// GETDEL để token chỉ đổi mật khẩu được một lần kể cả khi có hai request cùng lúc
+	var challenge PasswordChangeChallenge
+	if err := as.cacheService.GetDel("password_change:"+token, &challenge); err != nil || challenge.UserUUID == "" {
+		return LoginResult{}, utils.NewError(utils.UnauthorizedError, "Password change token is invalid or expired")
+	}
+
+	userUuid, err := uuid.Parse(challenge.UserUUID)
+	if err != nil {
+		return LoginResult{}, utils.WrapError(utils.InternalServerError, "Uuid is invalid", err)
+	}
+
+	user, err := as.findChallengeUser(context, userUuid, challenge.Closed)
+	if err != nil {
+		return LoginResult{}, utils.NewError(utils.UnauthorizedError, "User not found")
+	}
+
+	if err := updatePassword(context, as.userRepo, user, password); err != nil {
+		// Mật khẩu mới bị từ chối thì trả lại token để người dùng thử lại
+		if ttl := time.Until(challenge.ExpiresAt); ttl > 0 {
+			if err := as.cacheService.Set("password_change:"+token, challenge, ttl); err != nil {
+				loggers.Log.Warn().Err(err).Msg("Failed to restore password change token")
+			}
+		}
+		return LoginResult{}, err
+	}
+
+	result, err := as.createMfaChallenge(context, user, challenge.DeviceName, challenge.Closed)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	if result.MfaStatus != "" {
+		return result, nil
+	}
+
+	return as.completeLogin(ctx, user, challenge.DeviceName, "password_change", challenge.Closed)
+}
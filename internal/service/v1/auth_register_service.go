@@ -0,0 +1,190 @@
+package v1service
+
+import (
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/hasher"
+	"gin/user-management-api/pkg/loggers"
+	"gin/user-management-api/pkg/mail"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	EmailVerificationTTL      = 24 * time.Hour
+	EmailVerificationCooldown = 10 * time.Minute
+	// Self registered accounts start as members (3 - Member)
+	RegisterDefaultLevel int32 = 3
+)
+
+func (as *authService) Register(ctx *gin.Context, userParams sqlc.CreateUserParams) (sqlc.User, error) {
+	context := ctx.Request.Context()
+
+	userParams.UserEmail = utils.NormalizeString(userParams.UserEmail)
+	userParams.UserStatus = UserStatusPendingVerification
+	userParams.UserLevel = RegisterDefaultLevel
+
+	hashedPassword, err := hasher.Hash(userParams.UserPassword)
+	if err != nil {
+		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "failed to hash password", err)
+	}
+	userParams.UserPassword = hashedPassword
+
+	user, err := as.userRepo.Create(context, userParams)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+			return sqlc.User{}, utils.WrapError(utils.InternalServerError, "failed to create a new user", err)
+		}
+		if user, err = as.replacePendingUser(ctx, userParams); err != nil {
+			return sqlc.User{}, err
+		}
+	}
+
+	if err := as.sendVerificationEmail(ctx, user); err != nil {
+		// Không gửi được email thì xoá tài khoản vừa tạo, nếu không email này không đăng ký lại được
+		if err := as.userRepo.DeletePending(context, user.UserUuid); err != nil {
+			loggers.Log.Error().Err(err).Str("user_uuid", user.UserUuid.String()).Msg("Failed to delete unverified registration")
+		}
+		return sqlc.User{}, err
+	}
+
+	if err := as.cacheService.Clear("users:*"); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to clear cache")
+	}
+
+	return user, nil
+}
+
+// replacePendingUser lets an unverified email register again, otherwise a typo in the password or an expired link
+// would keep the address taken forever. Verified accounts still conflict.
+func (as *authService) replacePendingUser(ctx *gin.Context, userParams sqlc.CreateUserParams) (sqlc.User, error) {
+	rateLimitKey := fmt.Sprintf("verify_email:ratelimit:%s", userParams.UserEmail)
+	if exists, err := as.cacheService.Exited(rateLimitKey); err == nil && exists {
+		return sqlc.User{}, utils.NewError(utils.TooManyRequestsError, "Please wait before requesting another verification email")
+	}
+
+	user, err := as.userRepo.ReplacePending(ctx.Request.Context(), userParams)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.User{}, utils.NewError(utils.ConflictError, "Email already exitst")
+	}
+	if err != nil {
+		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "failed to replace the pending registration", err)
+	}
+
+	return user, nil
+}
+
+// verifyEmailUserKey points to the latest verification token of a user so a resend can revoke the previous one
+func verifyEmailUserKey(userUuid string) string {
+	return "verify_email:user:" + userUuid
+}
+
+func (as *authService) VerifyEmail(ctx *gin.Context, token string) error {
+	context := ctx.Request.Context()
+
+	// GetDel để hai request cùng token không cùng xác thực được
+	var userUUIDStr string
+	err := as.cacheService.GetDel("verify_email:"+token, &userUUIDStr)
+	if err == redis.Nil || userUUIDStr == "" {
+		return utils.NewError(utils.NotFoundError, "Invalid or expired token")
+	}
+	if err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to get verification token")
+	}
+
+	userUuid, err := uuid.Parse(userUUIDStr)
+	if err != nil {
+		return utils.WrapError(utils.InternalServerError, "Uuid is invalid", err)
+	}
+
+	if _, err := as.userRepo.VerifyEmail(context, userUuid); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewError(utils.BadRequestError, "Account is already verified")
+		}
+		// Lỗi database thì trả lại token, hạn còn lại lấy theo key của user
+		if ttl, err := as.cacheService.TTL(verifyEmailUserKey(userUUIDStr)); err == nil && ttl > 0 {
+			if err := as.cacheService.Set("verify_email:"+token, userUUIDStr, ttl); err != nil {
+				loggers.Log.Warn().Err(err).Msg("Failed to restore verification token")
+			}
+		}
+		return utils.WrapError(utils.InternalServerError, "Failed to verify email", err)
+	}
+
+	as.cacheService.Delete(verifyEmailUserKey(userUUIDStr))
+	if err := as.cacheService.Clear("users:*"); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to clear cache")
+	}
+
+	return nil
+}
+
+func (as *authService) ResendVerificationEmail(ctx *gin.Context, email string) error {
+	context := ctx.Request.Context()
+	email = utils.NormalizeString(email)
+
+	rateLimitKey := fmt.Sprintf("verify_email:ratelimit:%s", email)
+	if exists, err := as.cacheService.Exited(rateLimitKey); err == nil && exists {
+		return utils.NewError(utils.TooManyRequestsError, "Please wait before requesting another verification email")
+	}
+
+	user, err := as.userRepo.GetByEmail(context, email)
+	if err != nil {
+		return utils.NewError(utils.NotFoundError, "Email not found")
+	}
+
+	if user.UserStatus != UserStatusPendingVerification {
+		return utils.NewError(utils.BadRequestError, "Account is already verified")
+	}
+
+	return as.sendVerificationEmail(ctx, user)
+}
+
+func (as *authService) sendVerificationEmail(ctx *gin.Context, user sqlc.User) error {
+	context := ctx.Request.Context()
+
+	token, err := utils.GenerateRandomString(32)
+	if err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to generate verification token")
+	}
+
+	// Chỉ link mới nhất còn dùng được
+	var previous string
+	if err := as.cacheService.GetDel(verifyEmailUserKey(user.UserUuid.String()), &previous); err == nil && previous != "" {
+		as.cacheService.Delete("verify_email:" + previous)
+	}
+
+	if err := as.cacheService.Set("verify_email:"+token, user.UserUuid, EmailVerificationTTL); err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to store verification token")
+	}
+	if err := as.cacheService.Set(verifyEmailUserKey(user.UserUuid.String()), token, EmailVerificationTTL); err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to store verification token")
+	}
+
+	rateLimitKey := fmt.Sprintf("verify_email:ratelimit:%s", user.UserEmail)
+	if err := as.cacheService.Set(rateLimitKey, "1", EmailVerificationCooldown); err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to store rate limit verification email")
+	}
+
+	verifyLink := fmt.Sprintf("view-to-verify-email?token=%s", token)
+	mailContent := &mail.Email{
+		To: []mail.Address{
+			{Email: user.UserEmail, Name: user.UserFullname},
+		},
+		Subject: "Verify your email address",
+		Text:    fmt.Sprintf("Hi %s, \n\n Thanks for signing up. Please click the link below to verify your email address: \n%s\n\n The link will expire in 24 hours. \n\n Best regard, \n Code With HuyDo", user.UserFullname, verifyLink),
+	}
+
+	if err := as.rabbitmq.Publish(context, "auth_email_queue", mailContent); err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to send verification email")
+	}
+
+	return nil
+}
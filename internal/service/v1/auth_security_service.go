@@ -0,0 +1,77 @@
+package v1service
+
+import (
+	"fmt"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/pkg/loggers"
+	"gin/user-management-api/pkg/mail"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func refreshReuseAlertEnabled() bool {
+	return utils.GetEnv("REFRESH_TOKEN_REUSE_ALERT", "true") == "true"
+}
+
+// handleRefreshTokenReuse treats reuse of a rotated refresh token as theft and revokes the whole family
+func (as *authService) handleRefreshTokenReuse(ctx *gin.Context, token auth.RefreshToken) {
+	if err := as.tokenService.RevokeTokenFamily(token.FamilyID); err != nil {
+		loggers.Log.Error().Err(err).Str("family_id", token.FamilyID).Msg("Failed to revoke refresh token family")
+	}
+
+	if token.SessionID != "" {
+		// Xoá session cũng đưa các access token còn hạn vào blacklist
+		as.tokenService.RevokeSession(token.UserUUID, token.SessionID)
+	}
+
+	loggers.Log.Warn().
+		Str("event", "refresh_token_reuse").
+		Str("user_uuid", token.UserUUID).
+		Str("family_id", token.FamilyID).
+		Str("session_id", token.SessionID).
+		Str("client_ip", as.getClientIP(ctx)).
+		Str("user_agent", ctx.Request.UserAgent()).
+		Msg("Refresh token reuse detected, token family revoked")
+
+	if !refreshReuseAlertEnabled() {
+		return
+	}
+
+	// Every replay of a revoked family ends up here, the user is only mailed for the first one
+	familyID := token.FamilyID
+	if familyID == "" {
+		familyID = token.Token
+	}
+	first, err := as.cacheService.SetNX("refresh_reuse_alerted:"+familyID, true, auth.RefreshTokenTTL)
+	if err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to mark refresh token reuse alert")
+	} else if !first {
+		return
+	}
+
+	userUuid, err := uuid.Parse(token.UserUUID)
+	if err != nil {
+		return
+	}
+
+	context := ctx.Request.Context()
+	user, err := as.userRepo.FindByUUID(context, userUuid)
+	if err != nil {
+		return
+	}
+
+	mailContent := &mail.Email{
+		To: []mail.Address{
+			{Email: user.UserEmail},
+		},
+		Subject:  "Suspicious sign-in activity",
+		Text:     fmt.Sprintf("Hi %s, \n\n A refresh token of your account was used after it had already been replaced, from IP %s. \n All sessions on that device have been signed out. If this was not you, please change your password. \n\n Best regard, \n Code With HuyDo", user.UserEmail, as.getClientIP(ctx)),
+		Category: "security",
+	}
+
+	if err := as.rabbitmq.Publish(context, "auth_email_queue", mailContent); err != nil {
+		loggers.Log.Error().Err(err).Msg("Failed to send refresh token reuse alert")
+	}
+}
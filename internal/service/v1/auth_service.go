@@ -1,6 +1,8 @@
 package v1service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"gin/user-management-api/internal/db/sqlc"
 	"gin/user-management-api/internal/repository"
@@ -11,43 +13,54 @@ import (
 	"gin/user-management-api/pkg/mail"
 	"gin/user-management-api/pkg/rabbitmq"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
-	"golang.org/x/crypto/bcrypt"
-	"golang.org/x/time/rate"
 )
 
 type authService struct {
-	userRepo     repository.UserRepository
-	tokenService auth.TokenService
-	cacheService cache.RedisCacheService
-	mailService  mail.EmailProviderService
-	rabbitmq     rabbitmq.RabbitMQSerivce
+	userRepo       repository.UserRepository
+	roleRepo       repository.RoleRepository
+	mfaRepo        repository.MfaRepository
+	eventRepo      repository.SecurityEventRepository
+	closureRepo    repository.AccountClosureRepository
+	identityRepo   repository.IdentityRepository
+	authenticators []Authenticator
+	tokenService   auth.TokenService
+	cacheService   cache.RedisCacheService
+	mailService    mail.EmailProviderService
+	rabbitmq       rabbitmq.RabbitMQSerivce
 }
 
-type LoginAttempt struct {
-	limiter  *rate.Limiter
-	lastSeen time.Time
+type LoginResult struct {
+	AccessToken   string
+	RefreshToken  string
+	ExpiresIn     int
+	MfaStatus     string
+	MfaToken      string
+	RecoveryCodes []string
+	// Set when the password is expired and has to be changed before tokens are issued
+	PasswordStatus string
+	PasswordToken  string
 }
 
-var (
-	mu              sync.Mutex
-	clients         = make(map[string]*LoginAttempt)
-	LoginAttemptTTL = 5 * time.Minute
-	MaxLoginAttempt = 5
-)
+var PermissionCacheTTL = 10 * time.Minute
 
-func NewAuthService(repo repository.UserRepository, tokenService auth.TokenService, cacheService cache.RedisCacheService, mailService mail.EmailProviderService, rabbitmqService rabbitmq.RabbitMQSerivce) *authService {
+func NewAuthService(repo repository.UserRepository, roleRepo repository.RoleRepository, mfaRepo repository.MfaRepository, eventRepo repository.SecurityEventRepository, closureRepo repository.AccountClosureRepository, identityRepo repository.IdentityRepository, authenticators []Authenticator, tokenService auth.TokenService, cacheService cache.RedisCacheService, mailService mail.EmailProviderService, rabbitmqService rabbitmq.RabbitMQSerivce) *authService {
 	return &authService{
-		userRepo:     repo,
-		tokenService: tokenService,
-		cacheService: cacheService,
-		mailService:  mailService,
-		rabbitmq:     rabbitmqService,
+		userRepo:       repo,
+		roleRepo:       roleRepo,
+		mfaRepo:        mfaRepo,
+		eventRepo:      eventRepo,
+		closureRepo:    closureRepo,
+		identityRepo:   identityRepo,
+		authenticators: authenticators,
+		tokenService:   tokenService,
+		cacheService:   cacheService,
+		mailService:    mailService,
+		rabbitmq:       rabbitmqService,
 	}
 }
 
@@ -60,71 +73,206 @@ func (as *authService) getClientIP(ctx *gin.Context) string {
 	return ip
 }
 
-func (as *authService) getLoginAttempt(ip string) *rate.Limiter {
-	mu.Lock()
-	defer mu.Unlock()
+func (as *authService) getUserPermissions(ctx context.Context, user sqlc.User) ([]string, error) {
+	cacheKey := fmt.Sprintf("permissions:user:%s", user.UserUuid)
+
+	var permissions []string
+	if err := as.cacheService.Get(cacheKey, &permissions); err == nil && permissions != nil {
+		return permissions, nil
+	}
+
+	permissions, err := as.roleRepo.GetPermissionCodesByUserID(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
 
-	client, exists := clients[ip]
-	if !exists {
-		limiter := rate.NewLimiter(rate.Limit(float32(MaxLoginAttempt)/float32(LoginAttemptTTL.Seconds())), MaxLoginAttempt) // 5 request/sec, brust 10
-		newClient := &LoginAttempt{limiter, time.Now()}
-		clients[ip] = newClient
-		return limiter
+	if err := as.cacheService.Set(cacheKey, permissions, PermissionCacheTTL); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to cache user permissions")
 	}
 
-	client.lastSeen = time.Now()
-	return client.limiter
+	return permissions, nil
 }
 
-func (as *authService) checkLoginAttempt(ip string) error {
-	limiter := as.getLoginAttempt(ip)
-	if !limiter.Allow() {
-		return utils.NewError(utils.TooManyRequestsError, "Too many login attempt. Please rety again later")
+func (as *authService) newSession(ctx *gin.Context, deviceName string) auth.Session {
+	deviceName = strings.TrimSpace(deviceName)
+	if deviceName == "" {
+		deviceName = "Unknown device"
+	}
+
+	return auth.Session{
+		DeviceName: deviceName,
+		IPAddress:  as.getClientIP(ctx),
+		UserAgent:  ctx.Request.UserAgent(),
 	}
-	return nil
 }
 
-func (as *authService) CleanupClients(ip string) {
-	mu.Lock()
-	defer mu.Unlock()
-	delete(clients, ip)
+// generateAccessToken limits the user's permissions to the granted scopes when the session belongs to an OAuth client
+func (as *authService) generateAccessToken(ctx context.Context, user sqlc.User, session auth.Session) (auth.AccessToken, error) {
+	permissions, err := as.getUserPermissions(ctx, user)
+	if err != nil {
+		return auth.AccessToken{}, utils.WrapError(utils.InternalServerError, "unable to load user permissions", err)
+	}
+
+	if session.ClientID != "" {
+		permissions = intersectScopes(permissions, session.Scopes)
+	}
+
+	accessToken, err := as.tokenService.GenerateAccessToken(user, auth.AccessTokenOptions{
+		Permissions: permissions,
+		SessionID:   session.ID,
+		ClientID:    session.ClientID,
+		Scopes:      session.Scopes,
+	})
+	if err != nil {
+		return auth.AccessToken{}, utils.WrapError(utils.InternalServerError, "unable to create access token", err)
+	}
+	return accessToken, nil
 }
 
-func (as *authService) Login(ctx *gin.Context, email, password string) (string, string, int, error) {
-	context := ctx.Request.Context()
-	ip := as.getClientIP(ctx)
+// issueTokens starts a new session for the user and returns its first token pair
+func (as *authService) issueTokens(ctx context.Context, user sqlc.User, session auth.Session) (LoginResult, error) {
+	now := time.Now()
+	session.ID = uuid.NewString()
+	session.UserUUID = user.UserUuid.String()
+	session.CreatedAt = now
+	session.LastUsedAt = now
 
-	if err := as.checkLoginAttempt(ip); err != nil {
-		return "", "", 0, err
+	accessToken, err := as.generateAccessToken(ctx, user, session)
+	if err != nil {
+		return LoginResult{}, err
 	}
 
-	email = utils.NormalizeString(email)
-	user, err := as.userRepo.GetByEmail(context, email)
+	refreshTokenToken, err := as.tokenService.GenerateRefreshToken(user, session.ID)
 	if err != nil {
-		as.getLoginAttempt(ip)
-		return "", "", 0, utils.NewError(utils.UnauthorizedError, "Invalid email or password")
+		return LoginResult{}, utils.WrapError(utils.InternalServerError, "unable to create access token", err)
+	}
+
+	if err := as.tokenService.StoreRefreshToken(refreshTokenToken); err != nil {
+		return LoginResult{}, utils.WrapError(utils.InternalServerError, "Cannot save refresh token", err)
 	}
-	if err := bcrypt.CompareHashAndPassword([]byte(user.UserPassword), []byte(password)); err != nil {
-		return "", "", 0, utils.NewError(utils.UnauthorizedError, "Invalid email or password")
+
+	session.RefreshToken = refreshTokenToken.Token
+	session.ExpiresAt = refreshTokenToken.ExpiresAt
+	session.AccessTokens = []auth.AccessTokenRef{{JTI: accessToken.JTI, ExpiresAt: accessToken.ExpiresAt}}
+	if err := as.tokenService.SaveSession(session); err != nil {
+		return LoginResult{}, utils.WrapError(utils.InternalServerError, "Cannot save session", err)
 	}
 
-	accessToken, err := as.tokenService.GenerateAccessToken(user)
+	return LoginResult{
+		AccessToken:  accessToken.Token,
+		RefreshToken: refreshTokenToken.Token,
+		ExpiresIn:    int(auth.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (as *authService) Login(ctx *gin.Context, email, password, deviceName string) (LoginResult, error) {
+	context := ctx.Request.Context()
+	ip := as.getClientIP(ctx)
+
+	email = utils.NormalizeString(email)
+	if err := as.checkLoginAllowed(ip, email); err != nil {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventLogin, Outcome: SecurityOutcomeFailure, Reason: "throttled", Email: email})
+		return LoginResult{}, err
+	}
+
+	authentication, err := as.authenticate(context, email, password)
+	if errors.Is(err, ErrUnknownAccount) {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventLogin, Outcome: SecurityOutcomeFailure, Reason: "unknown_account", Email: email})
+		as.recordLoginFailure(ctx, ip, email, nil)
+		return LoginResult{}, utils.NewError(utils.UnauthorizedError, "Invalid email or password")
+	}
+	if errors.Is(err, ErrInvalidPassword) {
+		var knownUser *sqlc.User
+		var userID *int32
+		if authentication.User.UserID != 0 {
+			knownUser = &authentication.User
+			userID = &authentication.User.UserID
+		}
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventLogin, Outcome: SecurityOutcomeFailure, Reason: "invalid_password", UserID: userID, Email: email})
+		as.recordLoginFailure(ctx, ip, email, knownUser)
+		return LoginResult{}, utils.NewError(utils.UnauthorizedError, "Invalid email or password")
+	}
 	if err != nil {
-		return "", "", 0, utils.WrapError(utils.InternalServerError, "unable to create access token", err)
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventLogin, Outcome: SecurityOutcomeFailure, Reason: "authenticator_error", Email: email})
+		return LoginResult{}, err
+	}
+	user := authentication.User
+
+	if user.UserStatus == UserStatusPendingVerification {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventLogin, Outcome: SecurityOutcomeFailure, Reason: "email_not_verified", UserID: &user.UserID, Email: email})
+		return LoginResult{}, utils.NewError(utils.ForbiddenError, "Please verify your email address before logging in")
 	}
 
-	refreshTokenToken, err := as.tokenService.GenerateRefreshToken(user)
+	if err := checkAccountStatus(user.UserStatus); err != nil {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventLogin, Outcome: SecurityOutcomeFailure, Reason: "account_status", UserID: &user.UserID, Email: email})
+		return LoginResult{}, err
+	}
+
+	// Directory accounts have no local password to rehash or expire
+	if authentication.Method == AuthMethodPassword {
+		as.rehashPassword(context, user, password)
+
+		if isPasswordExpired(user) {
+			return as.createPasswordChangeChallenge(user, deviceName, authentication.Closed)
+		}
+	}
+
+	challenge, err := as.createMfaChallenge(context, user, deviceName, authentication.Closed)
 	if err != nil {
-		return "", "", 0, utils.WrapError(utils.InternalServerError, "unable to create access token", err)
+		return LoginResult{}, err
+	}
+	if challenge.MfaStatus != "" {
+		return challenge, nil
 	}
 
-	if err := as.tokenService.StoreRefreshToken(refreshTokenToken); err != nil {
-		return "", "", 0, utils.WrapError(utils.InternalServerError, "Cannot save refresh token", err)
+	return as.completeLogin(ctx, user, deviceName, authentication.Method, authentication.Closed)
+}
+
+// completeLogin issues the tokens of an interactive login and records it, method tells how the user signed in.
+// Closed accounts are only restored here, once every challenge of the login has been passed
+func (as *authService) completeLogin(ctx *gin.Context, user sqlc.User, deviceName, method string, closed bool) (LoginResult, error) {
+	if err := checkAccountStatus(user.UserStatus); err != nil {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventLogin, Outcome: SecurityOutcomeFailure, Reason: "account_status", UserID: &user.UserID, Email: user.UserEmail})
+		return LoginResult{}, err
+	}
+
+	// Failures are only cleared here, a correct password alone must not reset the count of wrong MFA codes
+	as.clearLoginFailures(as.getClientIP(ctx), user.UserEmail)
+
+	if closed {
+		restored, err := as.restoreClosedAccount(ctx, user)
+		if err != nil {
+			return LoginResult{}, err
+		}
+		user = restored
+	}
+
+	result, err := as.issueTokens(ctx.Request.Context(), user, as.newSession(ctx, deviceName))
+	if err != nil {
+		return LoginResult{}, err
 	}
 
-	as.CleanupClients(ip)
+	as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventLogin, Outcome: SecurityOutcomeSuccess, Reason: method, UserID: &user.UserID, Email: user.UserEmail})
+	as.checkNewDevice(ctx, user)
+	return result, nil
+}
+
+func (as *authService) recordSecurityEvent(ctx *gin.Context, event SecurityEvent) {
+	recordSecurityEvent(ctx, as.eventRepo, event)
+}
+
+// securityEventUserID resolves the user of a token, events are still written when it cannot be found
+func (as *authService) securityEventUserID(ctx *gin.Context, userUUID string) *int32 {
+	userUuid, err := uuid.Parse(userUUID)
+	if err != nil {
+		return nil
+	}
 
-	return accessToken, refreshTokenToken.Token, int(auth.AccessTokenTTL.Seconds()), nil
+	user, err := as.userRepo.FindByUUID(ctx.Request.Context(), userUuid)
+	if err != nil {
+		return nil
+	}
+	return &user.UserID
 }
 
 func (as *authService) RefreshToken(ctx *gin.Context, refreshTokenString string) (string, string, int, error) {
@@ -132,7 +280,13 @@ func (as *authService) RefreshToken(ctx *gin.Context, refreshTokenString string)
 
 	// kiểm tra refresh token, để trả về uuid của user
 	token, err := as.tokenService.ValidateRefreshToken(refreshTokenString)
+	if errors.Is(err, auth.ErrRefreshTokenReused) {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventTokenRefresh, Outcome: SecurityOutcomeFailure, Reason: "token_reused", UserID: as.securityEventUserID(ctx, token.UserUUID)})
+		as.handleRefreshTokenReuse(ctx, token)
+		return "", "", 0, utils.NewError(utils.UnauthorizedError, "Refresh token has already been used. Please login again")
+	}
 	if err != nil {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventTokenRefresh, Outcome: SecurityOutcomeFailure, Reason: "invalid_token"})
 		return "", "", 0, utils.NewError(utils.UnauthorizedError, "Refresh token is invalid or revoked")
 	}
 
@@ -143,30 +297,68 @@ func (as *authService) RefreshToken(ctx *gin.Context, refreshTokenString string)
 		return "", "", 0, utils.NewError(utils.UnauthorizedError, "User not found")
 	}
 
-	// Tạo access token mới
-	accessToken, err := as.tokenService.GenerateAccessToken(user)
+	if err := checkAccountStatus(user.UserStatus); err != nil {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventTokenRefresh, Outcome: SecurityOutcomeFailure, Reason: "account_status", UserID: &user.UserID, Email: user.UserEmail})
+		if err := as.tokenService.RevokeRefreshToken(refreshTokenString); err != nil {
+			loggers.Log.Warn().Err(err).Msg("Failed to revoke refresh token")
+		}
+		return "", "", 0, err
+	}
+
+	// Refresh token cũ chưa có session thì tạo session mới
+	if token.SessionID == "" {
+		if err := as.tokenService.RevokeRefreshToken(refreshTokenString); err != nil {
+			return "", "", 0, utils.WrapError(utils.InternalServerError, "Unable to revoke token", err)
+		}
+
+		result, err := as.issueTokens(context, user, as.newSession(ctx, ""))
+		if err != nil {
+			return "", "", 0, err
+		}
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventTokenRefresh, Outcome: SecurityOutcomeSuccess, Reason: "legacy_token", UserID: &user.UserID, Email: user.UserEmail})
+		return result.AccessToken, result.RefreshToken, result.ExpiresIn, nil
+	}
+
+	session, err := as.tokenService.GetSession(token.SessionID)
 	if err != nil {
-		return "", "", 0, utils.WrapError(utils.InternalServerError, "unable to create access token", err)
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventTokenRefresh, Outcome: SecurityOutcomeFailure, Reason: "session_revoked", UserID: &user.UserID, Email: user.UserEmail})
+		return "", "", 0, utils.NewError(utils.UnauthorizedError, "Session has been revoked")
 	}
 
-	// Tạo refresh token mới
+	// Tạo access token mới
+	accessToken, err := as.generateAccessToken(context, user, session)
+	if err != nil {
+		return "", "", 0, err
+	}
 
-	refreshTokenToken, err := as.tokenService.GenerateRefreshToken(user)
+	// Tạo refresh token mới trong cùng family, refresh token cũ bị đánh dấu đã thay thế
+	refreshTokenToken, err := as.tokenService.RotateRefreshToken(token, user)
+	if errors.Is(err, auth.ErrRefreshTokenReused) {
+		// Một request khác đã rotate cùng token này trước
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventTokenRefresh, Outcome: SecurityOutcomeFailure, Reason: "token_reused", UserID: &user.UserID, Email: user.UserEmail})
+		as.handleRefreshTokenReuse(ctx, token)
+		return "", "", 0, utils.NewError(utils.UnauthorizedError, "Refresh token has already been used. Please login again")
+	}
 	if err != nil {
-		return "", "", 0, utils.WrapError(utils.InternalServerError, "unable to create access token", err)
+		return "", "", 0, utils.WrapError(utils.InternalServerError, "Unable to rotate refresh token", err)
 	}
 
-	// Vô hiệu hoá refresh token cũ
-	if err := as.tokenService.RevokeRefreshToken(refreshTokenString); err != nil {
-		return "", "", 0, utils.WrapError(utils.InternalServerError, "Unable to revoke token", err)
+	// Cập nhật session
+	session.RefreshToken = refreshTokenToken.Token
+	session.ExpiresAt = refreshTokenToken.ExpiresAt
+	session.LastUsedAt = time.Now()
+	session.IPAddress = as.getClientIP(ctx)
+	session.UserAgent = ctx.Request.UserAgent()
+	if err := as.tokenService.SaveSession(session); err != nil {
+		return "", "", 0, utils.WrapError(utils.InternalServerError, "Cannot save session", err)
 	}
 
-	// Lưu refeshtoken mới
-	if err := as.tokenService.StoreRefreshToken(refreshTokenToken); err != nil {
-		return "", "", 0, utils.WrapError(utils.InternalServerError, "Cannot save refresh token", err)
+	if err := as.tokenService.TrackAccessToken(session.ID, accessToken); err != nil {
+		return "", "", 0, utils.WrapError(utils.InternalServerError, "Cannot save session", err)
 	}
 
-	return accessToken, refreshTokenToken.Token, int(auth.AccessTokenTTL.Seconds()), nil
+	as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventTokenRefresh, Outcome: SecurityOutcomeSuccess, UserID: &user.UserID, Email: user.UserEmail})
+	return accessToken.Token, refreshTokenToken.Token, int(auth.AccessTokenTTL.Seconds()), nil
 }
 
 func (as *authService) Logout(ctx *gin.Context, refreshToken string) error {
@@ -183,13 +375,10 @@ func (as *authService) Logout(ctx *gin.Context, refreshToken string) error {
 
 	if jti, ok := claims["jti"].(string); ok {
 		expUnit, _ := claims["exp"].(float64)
-		exp := time.Unix(int64(expUnit), 0)
-		key := "backlist:" + jti
-		ttl := time.Until(exp)
-		as.cacheService.Set(key, "revoked", ttl)
+		as.tokenService.BlacklistAccessToken(jti, time.Unix(int64(expUnit), 0))
 	}
 
-	_, err = as.tokenService.ValidateRefreshToken(refreshToken)
+	token, err := as.tokenService.ValidateRefreshToken(refreshToken)
 	if err != nil {
 		return utils.NewError(utils.UnauthorizedError, "Refresh token is invalid or revoked")
 	}
@@ -198,6 +387,11 @@ func (as *authService) Logout(ctx *gin.Context, refreshToken string) error {
 		return utils.WrapError(utils.InternalServerError, "Invalid access token", err)
 	}
 
+	if token.SessionID != "" {
+		as.tokenService.RevokeSession(token.UserUUID, token.SessionID)
+	}
+
+	as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventLogout, Outcome: SecurityOutcomeSuccess, UserID: as.securityEventUserID(ctx, token.UserUUID)})
 	return nil
 }
 
@@ -210,9 +404,28 @@ func (as *authService) RequestForgotPassword(ctx *gin.Context, email string) err
 
 	user, err := as.userRepo.GetByEmail(context, email)
 	if err != nil {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventPasswordResetRequest, Outcome: SecurityOutcomeFailure, Reason: "unknown_account", Email: email})
 		return utils.NewError(utils.NotFoundError, "Email not found")
 	}
 
+	if err := as.CheckLocalPassword(context, user); err != nil {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventPasswordResetRequest, Outcome: SecurityOutcomeFailure, Reason: "directory_account", UserID: &user.UserID, Email: user.UserEmail})
+		return err
+	}
+
+	if err := as.cacheService.Set(rateLimitKey, "1", 10*time.Minute); err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to store rate limit reset password")
+	}
+
+	if err := as.sendPasswordResetEmail(ctx, user); err != nil {
+		return err
+	}
+
+	as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventPasswordResetRequest, Outcome: SecurityOutcomeSuccess, UserID: &user.UserID, Email: user.UserEmail})
+	return nil
+}
+
+func (as *authService) sendPasswordResetEmail(ctx *gin.Context, user sqlc.User) error {
 	token, err := utils.GenerateRandomString(16)
 	if err != nil {
 		return utils.NewError(utils.InternalServerError, "Failed to generate reset token")
@@ -222,21 +435,17 @@ func (as *authService) RequestForgotPassword(ctx *gin.Context, email string) err
 		return utils.NewError(utils.InternalServerError, "Failed to store reset token")
 	}
 
-	if err := as.cacheService.Set(rateLimitKey, "1", 10*time.Minute); err != nil {
-		return utils.NewError(utils.InternalServerError, "Failed to store rate limit reset password")
-	}
-
 	resetLink := fmt.Sprintf("view-to-reset-password?token=%s", token)
 	loggers.Log.Info().Msg(resetLink)
 	mailContent := &mail.Email{
 		To: []mail.Address{
-			{Email: email},
+			{Email: user.UserEmail},
 		},
 		Subject: "Password Reset Request",
 		Text:    fmt.Sprintf("Hi %s, \n\n You requested to reset your password. Please click the link below to reset it: \n%s\n\n The link will expire in 1 hour. \n\n Best regard, \n Code With HuyDo", user.UserEmail, resetLink),
 	}
 
-	if err := as.rabbitmq.Publish(context, "auth_email_queue", mailContent); err != nil {
+	if err := as.rabbitmq.Publish(ctx.Request.Context(), "auth_email_queue", mailContent); err != nil {
 		return utils.NewError(utils.InternalServerError, "Failed to send password reset email")
 	}
 
@@ -249,8 +458,11 @@ func (as *authService) RequestForgotPassword(ctx *gin.Context, email string) err
 
 func (as *authService) ResetPassword(ctx *gin.Context, token, password string) error {
 	context := ctx.Request.Context()
+
+	// GETDEL để token chỉ đặt lại mật khẩu được một lần, hạn còn lại dùng khi phải trả token lại
+	ttl, _ := as.cacheService.TTL("reset:" + token)
 	var userUUIDStr string
-	err := as.cacheService.Get("reset:"+token, &userUUIDStr)
+	err := as.cacheService.GetDel("reset:"+token, &userUUIDStr)
 	if err == redis.Nil || userUUIDStr == "" {
 		return utils.NewError(utils.NotFoundError, "Invalid or expired token")
 	}
@@ -263,21 +475,26 @@ func (as *authService) ResetPassword(ctx *gin.Context, token, password string) e
 		return utils.WrapError(utils.InternalServerError, "Uuid is invalid", err)
 	}
 
-	hashPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	user, err := as.userRepo.FindByUUID(context, userUuid)
 	if err != nil {
-		return utils.WrapError(utils.InternalServerError, "Failed to hash password", err)
+		return utils.NewError(utils.NotFoundError, "User not found")
 	}
 
-	input := sqlc.UpdatePasswordParams{
-		UserUuid:     userUuid,
-		UserPassword: string(hashPassword),
+	if err := as.CheckLocalPassword(context, user); err != nil {
+		return err
 	}
 
-	_, err = as.userRepo.UpdatePassword(context, input)
-	if err != nil {
-		return utils.NewError(utils.InternalServerError, "Failed to update new password")
+	if err := updatePassword(context, as.userRepo, user, password); err != nil {
+		as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventPasswordReset, Outcome: SecurityOutcomeFailure, Reason: "password_rejected", UserID: &user.UserID, Email: user.UserEmail})
+		// Mật khẩu mới bị từ chối thì trả lại token để người dùng thử lại
+		if ttl > 0 {
+			if err := as.cacheService.Set("reset:"+token, userUUIDStr, ttl); err != nil {
+				loggers.Log.Warn().Err(err).Msg("Failed to restore reset token")
+			}
+		}
+		return err
 	}
 
-	as.cacheService.Clear("reset:" + token)
+	as.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventPasswordReset, Outcome: SecurityOutcomeSuccess, UserID: &user.UserID, Email: user.UserEmail})
 	return nil
 }
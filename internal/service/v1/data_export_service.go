@@ -0,0 +1,489 @@
+package v1service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/loggers"
+	"gin/user-management-api/pkg/mail"
+	"gin/user-management-api/pkg/rabbitmq"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	DataExportQueue = "data_export_queue"
+
+	DataExportStatusPending   = "pending"
+	DataExportStatusCompleted = "completed"
+	DataExportStatusFailed    = "failed"
+
+	// dataExportMaxSecurityEvents caps the security events put into one archive
+	dataExportMaxSecurityEvents = 10000
+)
+
+var DataExportCooldown = time.Hour
+
+// DATA_EXPORT_TTL_HOURS is how long the download link of an export stays valid
+func DataExportTTL() time.Duration {
+	hours := utils.GetIntEnv("DATA_EXPORT_TTL_HOURS", 24)
+	if hours <= 0 {
+		hours = 24
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// DataExportJob is published to the worker, sessions are taken from Redis by the API since the worker has no cache
+type DataExportJob struct {
+	ExportUUID string              `json:"export_uuid"`
+	UserUUID   string              `json:"user_uuid"`
+	Sessions   []DataExportSession `json:"sessions"`
+}
+
+// DataExportSession leaves out the refresh and access tokens of the session
+type DataExportSession struct {
+	ID         string    `json:"id"`
+	DeviceName string    `json:"device_name"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	ClientID   string    `json:"client_id,omitempty"`
+	Scopes     []string  `json:"scopes,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func hashDataExportToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type dataExportService struct {
+	exportRepo   repository.DataExportRepository
+	userRepo     repository.UserRepository
+	tokenService auth.TokenService
+	cacheService cache.RedisCacheService
+	rabbitmq     rabbitmq.RabbitMQSerivce
+}
+
+func NewDataExportService(exportRepo repository.DataExportRepository, userRepo repository.UserRepository, tokenService auth.TokenService, cacheService cache.RedisCacheService, rabbitmq rabbitmq.RabbitMQSerivce) DataExportService {
+	return &dataExportService{
+		exportRepo:   exportRepo,
+		userRepo:     userRepo,
+		tokenService: tokenService,
+		cacheService: cacheService,
+		rabbitmq:     rabbitmq,
+	}
+}
+
+// RequestExport queues the export, the worker builds the archive and emails the download link
+func (des *dataExportService) RequestExport(ctx *gin.Context, userUuid uuid.UUID) (sqlc.DataExport, error) {
+	context := ctx.Request.Context()
+
+	user, err := des.findUser(context, userUuid)
+	if err != nil {
+		return sqlc.DataExport{}, err
+	}
+
+	rateLimitKey := fmt.Sprintf("data_export:ratelimit:%s", user.UserUuid)
+	if exists, err := des.cacheService.Exited(rateLimitKey); err == nil && exists {
+		return sqlc.DataExport{}, utils.NewError(utils.TooManyRequestsError, "Please wait before requesting another data export")
+	}
+
+	sessions, err := des.tokenService.ListSessions(user.UserUuid.String())
+	if err != nil {
+		return sqlc.DataExport{}, utils.WrapError(utils.InternalServerError, "Failed to get sessions", err)
+	}
+
+	export, err := des.exportRepo.Create(context, user.UserID)
+	if err != nil {
+		return sqlc.DataExport{}, utils.WrapError(utils.InternalServerError, "failed to create data export", err)
+	}
+
+	job := DataExportJob{
+		ExportUUID: export.ExportUuid.String(),
+		UserUUID:   user.UserUuid.String(),
+		Sessions:   make([]DataExportSession, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		job.Sessions = append(job.Sessions, DataExportSession{
+			ID:         session.ID,
+			DeviceName: session.DeviceName,
+			IPAddress:  session.IPAddress,
+			UserAgent:  session.UserAgent,
+			ClientID:   session.ClientID,
+			Scopes:     session.Scopes,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+		})
+	}
+
+	if err := des.rabbitmq.Publish(context, DataExportQueue, job); err != nil {
+		if err := des.exportRepo.Fail(context, export.ExportID, "failed to queue export"); err != nil {
+			loggers.Log.Warn().Err(err).Msg("Failed to mark data export as failed")
+		}
+		return sqlc.DataExport{}, utils.NewError(utils.InternalServerError, "Failed to queue data export")
+	}
+
+	if err := des.cacheService.Set(rateLimitKey, "1", DataExportCooldown); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to store rate limit data export")
+	}
+
+	return export, nil
+}
+
+func (des *dataExportService) GetExport(ctx *gin.Context, userUuid, exportUuid uuid.UUID) (sqlc.DataExport, error) {
+	context := ctx.Request.Context()
+
+	user, err := des.findUser(context, userUuid)
+	if err != nil {
+		return sqlc.DataExport{}, err
+	}
+
+	export, err := des.exportRepo.FindByUUID(context, exportUuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.DataExport{}, utils.NewError(utils.NotFoundError, "data export not found")
+		}
+		return sqlc.DataExport{}, utils.WrapError(utils.InternalServerError, "failed to get data export", err)
+	}
+
+	// Exports of other users are reported as missing so their ids cannot be probed
+	if export.UserID != user.UserID {
+		return sqlc.DataExport{}, utils.NewError(utils.NotFoundError, "data export not found")
+	}
+	return export, nil
+}
+
+// DownloadExport is reached from the emailed link, the token is the only credential
+func (des *dataExportService) DownloadExport(ctx *gin.Context, token string) (sqlc.DataExport, error) {
+	export, err := des.exportRepo.FindByTokenHash(ctx.Request.Context(), hashDataExportToken(token))
+	if err != nil || export.ExportStatus != DataExportStatusCompleted || !export.ExportExpiresAt.Valid || time.Now().After(export.ExportExpiresAt.Time) {
+		return sqlc.DataExport{}, utils.NewError(utils.NotFoundError, "Invalid or expired download link")
+	}
+	return export, nil
+}
+
+func (des *dataExportService) findUser(ctx context.Context, userUuid uuid.UUID) (sqlc.User, error) {
+	user, err := des.userRepo.FindByUUID(ctx, userUuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.User{}, utils.NewError(utils.NotFoundError, "user not found")
+		}
+		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "failed to get user", err)
+	}
+	return user, nil
+}
+
+// PruneDataExports removes exports whose download link expired, it is run by the worker
+func PruneDataExports(ctx context.Context, repo repository.DataExportRepository) (int64, error) {
+	return repo.DeleteExpired(ctx, time.Now())
+}
+
+// DataExporter builds the archives of queued exports, it runs in the worker
+type DataExporter struct {
+	exportRepo        repository.DataExportRepository
+	userRepo          repository.UserRepository
+	roleRepo          repository.RoleRepository
+	mfaRepo           repository.MfaRepository
+	securityEventRepo repository.SecurityEventRepository
+}
+
+func NewDataExporter(exportRepo repository.DataExportRepository, userRepo repository.UserRepository, roleRepo repository.RoleRepository, mfaRepo repository.MfaRepository, securityEventRepo repository.SecurityEventRepository) *DataExporter {
+	return &DataExporter{
+		exportRepo:        exportRepo,
+		userRepo:          userRepo,
+		roleRepo:          roleRepo,
+		mfaRepo:           mfaRepo,
+		securityEventRepo: securityEventRepo,
+	}
+}
+
+type dataExportProfile struct {
+	UUID              string    `json:"uuid"`
+	Email             string    `json:"email"`
+	FullName          string    `json:"full_name"`
+	Age               *int32    `json:"age"`
+	Status            int32     `json:"status"`
+	Level             int32     `json:"level"`
+	PasswordChangedAt time.Time `json:"password_changed_at"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type dataExportMfa struct {
+	Enabled             bool       `json:"enabled"`
+	EnabledAt           *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesUnused int64      `json:"recovery_codes_unused"`
+}
+
+type dataExportApiKey struct {
+	UUID       string     `json:"uuid"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type dataExportAuditLog struct {
+	Action    string          `json:"action"`
+	ActorID   *int32          `json:"actor_id"`
+	TargetID  *int32          `json:"target_id"`
+	IPAddress string          `json:"ip_address"`
+	UserAgent string          `json:"user_agent"`
+	Metadata  json.RawMessage `json:"metadata,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Process builds and stores the archive and mails its download link, the export is marked failed
+// when the link cannot be delivered so the user can request a new one
+func (de *DataExporter) Process(ctx context.Context, job DataExportJob, mailService mail.EmailProviderService) error {
+	exportUuid, err := uuid.Parse(job.ExportUUID)
+	if err != nil {
+		return err
+	}
+
+	export, err := de.exportRepo.FindByUUID(ctx, exportUuid)
+	if err != nil {
+		return err
+	}
+	if export.ExportStatus != DataExportStatusPending {
+		return fmt.Errorf("data export %s is already %s", job.ExportUUID, export.ExportStatus)
+	}
+
+	email, err := de.process(ctx, export, job)
+	if err == nil {
+		if err = mailService.SendMail(ctx, email); err != nil {
+			err = fmt.Errorf("failed to send download link: %w", err)
+		}
+	}
+	if err != nil {
+		if err := de.exportRepo.Fail(ctx, export.ExportID, err.Error()); err != nil {
+			loggers.Log.Warn().Err(err).Msg("Failed to mark data export as failed")
+		}
+		return err
+	}
+	return nil
+}
+
+func (de *DataExporter) process(ctx context.Context, export sqlc.DataExport, job DataExportJob) (*mail.Email, error) {
+	userUuid, err := uuid.Parse(job.UserUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := de.userRepo.FindByUUID(ctx, userUuid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.UserID != export.UserID {
+		return nil, errors.New("export does not belong to the user")
+	}
+
+	archive, err := de.buildArchive(ctx, user, job.Sessions)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateRandomString(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate download token: %w", err)
+	}
+
+	ttl := DataExportTTL()
+	if _, err := de.exportRepo.Complete(ctx, export.ExportID, hashDataExportToken(token), archive, time.Now().Add(ttl)); err != nil {
+		return nil, fmt.Errorf("failed to store archive: %w", err)
+	}
+
+	downloadLink := fmt.Sprintf("view-to-download-data-export?token=%s", token)
+	return &mail.Email{
+		To: []mail.Address{
+			{Email: user.UserEmail, Name: user.UserFullname},
+		},
+		Subject: "Your data export is ready",
+		Text:    fmt.Sprintf("Hi %s, \n\n The copy of your data you requested is ready. Click the link below to download it: \n%s\n\n The link will expire in %d hours. \n\n Best regard, \n Code With HuyDo", user.UserFullname, downloadLink, int(ttl.Hours())),
+	}, nil
+}
+
+func (de *DataExporter) buildArchive(ctx context.Context, user sqlc.User, sessions []DataExportSession) ([]byte, error) {
+	permissions, err := de.roleRepo.GetPermissionCodesByUserID(ctx, user.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions: %w", err)
+	}
+
+	mfa, err := de.exportMfa(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := de.securityEventRepo.GetAll(ctx, sqlc.ListSecurityEventsParams{
+		UserID: &user.UserID,
+		Limit:  dataExportMaxSecurityEvents,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get security events: %w", err)
+	}
+
+	apiKeys, err := de.exportApiKeys(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	consents, err := de.exportRepo.GetOAuthConsents(ctx, user.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oauth consents: %w", err)
+	}
+
+	auditLogs, err := de.exportAuditLogs(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", dataExportProfile{
+			UUID:              user.UserUuid.String(),
+			Email:             user.UserEmail,
+			FullName:          user.UserFullname,
+			Age:               user.UserAge,
+			Status:            user.UserStatus,
+			Level:             user.UserLevel,
+			PasswordChangedAt: user.UserPasswordChangedAt,
+			CreatedAt:         user.UserCreatedAt,
+			UpdatedAt:         user.UserUpdatedAt,
+		}},
+		{"permissions.json", permissions},
+		{"mfa.json", mfa},
+		{"sessions.json", sessions},
+		{"security_events.json", events},
+		{"api_keys.json", apiKeys},
+		{"oauth_consents.json", consents},
+		{"audit_logs.json", auditLogs},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range files {
+		content, err := json.MarshalIndent(file.data, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", file.name, err)
+		}
+
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// exportMfa leaves out the TOTP secret and the recovery code hashes
+func (de *DataExporter) exportMfa(ctx context.Context, userID int32) (dataExportMfa, error) {
+	mfa, err := de.mfaRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dataExportMfa{}, nil
+		}
+		return dataExportMfa{}, fmt.Errorf("failed to get mfa: %w", err)
+	}
+
+	unused, err := de.mfaRepo.CountUnusedRecoveryCodes(ctx, userID)
+	if err != nil {
+		return dataExportMfa{}, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+
+	result := dataExportMfa{Enabled: mfa.MfaEnabled, RecoveryCodesUnused: unused}
+	if mfa.MfaEnabledAt.Valid {
+		result.EnabledAt = &mfa.MfaEnabledAt.Time
+	}
+	return result, nil
+}
+
+// exportApiKeys leaves out the key hashes
+func (de *DataExporter) exportApiKeys(ctx context.Context, userID int32) ([]dataExportApiKey, error) {
+	keys, err := de.exportRepo.GetApiKeys(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+
+	items := make([]dataExportApiKey, 0, len(keys))
+	for _, key := range keys {
+		item := dataExportApiKey{
+			UUID:      key.ApiKeyUuid.String(),
+			Name:      key.ApiKeyName,
+			Prefix:    key.ApiKeyPrefix,
+			Scopes:    key.ApiKeyScopes,
+			CreatedAt: key.ApiKeyCreatedAt,
+		}
+		if key.ApiKeyExpiresAt.Valid {
+			item.ExpiresAt = &key.ApiKeyExpiresAt.Time
+		}
+		if key.ApiKeyLastUsedAt.Valid {
+			item.LastUsedAt = &key.ApiKeyLastUsedAt.Time
+		}
+		if key.ApiKeyRevokedAt.Valid {
+			item.RevokedAt = &key.ApiKeyRevokedAt.Time
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (de *DataExporter) exportAuditLogs(ctx context.Context, userID int32) ([]dataExportAuditLog, error) {
+	logs, err := de.exportRepo.GetAuditLogs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit logs: %w", err)
+	}
+
+	items := make([]dataExportAuditLog, 0, len(logs))
+	for _, log := range logs {
+		items = append(items, exportAuditLog(log, userID))
+	}
+	return items, nil
+}
+
+// exportAuditLog only keeps the action and time of entries another user performed on the account,
+// their IP address, user agent and metadata such as an impersonation reason belong to the staff member
+func exportAuditLog(log sqlc.AuditLog, userID int32) dataExportAuditLog {
+	item := dataExportAuditLog{
+		Action:    log.AuditAction,
+		TargetID:  log.AuditTargetID,
+		CreatedAt: log.AuditCreatedAt,
+	}
+	if log.AuditActorID == nil || *log.AuditActorID != userID {
+		return item
+	}
+
+	item.ActorID = log.AuditActorID
+	item.IPAddress = log.AuditIpAddress
+	item.UserAgent = log.AuditUserAgent
+	if json.Valid(log.AuditMetadata) {
+		item.Metadata = log.AuditMetadata
+	}
+	return item
+}
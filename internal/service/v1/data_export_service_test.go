@@ -0,0 +1,41 @@
+package v1service
+
+import (
+	"gin/user-management-api/internal/db/sqlc"
+	"testing"
+	"time"
+)
+
+func TestExportAuditLogHidesOtherActors(t *testing.T) {
+	userID, adminID := int32(7), int32(1)
+	createdAt := time.Now()
+
+	impersonated := exportAuditLog(sqlc.AuditLog{
+		AuditActorID:   &adminID,
+		AuditTargetID:  &userID,
+		AuditAction:    AuditActionImpersonationStart,
+		AuditIpAddress: "10.0.0.1",
+		AuditUserAgent: "admin-browser",
+		AuditMetadata:  []byte(`{"reason":"ticket 42"}`),
+		AuditCreatedAt: createdAt,
+	}, userID)
+
+	if impersonated.Action != AuditActionImpersonationStart || !impersonated.CreatedAt.Equal(createdAt) {
+		t.Errorf("action and time were not exported: %+v", impersonated)
+	}
+	if impersonated.ActorID != nil || impersonated.IPAddress != "" || impersonated.UserAgent != "" || impersonated.Metadata != nil {
+		t.Errorf("staff data was exported: %+v", impersonated)
+	}
+
+	own := exportAuditLog(sqlc.AuditLog{
+		AuditActorID:   &userID,
+		AuditAction:    AuditActionImpersonationStop,
+		AuditIpAddress: "192.0.2.1",
+		AuditUserAgent: "user-browser",
+		AuditMetadata:  []byte(`{}`),
+	}, userID)
+
+	if own.ActorID == nil || own.IPAddress != "192.0.2.1" || own.UserAgent != "user-browser" || own.Metadata == nil {
+		t.Errorf("own entry was not exported in full: %+v", own)
+	}
+}
@@ -0,0 +1,163 @@
+package v1service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/pkg/loggers"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	AuditActionImpersonationStart = "impersonation.start"
+	AuditActionImpersonationStop  = "impersonation.stop"
+)
+
+var (
+	ImpersonationTTL = 5 * time.Minute
+	// User levels that cannot be impersonated (1 - Administrator)
+	ImpersonationProtectedLevels = []int32{1}
+)
+
+type impersonationService struct {
+	userRepo     repository.UserRepository
+	roleRepo     repository.RoleRepository
+	auditRepo    repository.AuditRepository
+	tokenService auth.TokenService
+}
+
+func NewImpersonationService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, auditRepo repository.AuditRepository, tokenService auth.TokenService) ImpersonationService {
+	return &impersonationService{
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		auditRepo:    auditRepo,
+		tokenService: tokenService,
+	}
+}
+
+// StartImpersonation issues a short-lived access token for the target user that carries the caller as actor
+func (is *impersonationService) StartImpersonation(ctx *gin.Context, targetUuid uuid.UUID, reason string) (auth.AccessToken, sqlc.User, error) {
+	context := ctx.Request.Context()
+
+	actor, err := is.findUser(context, ctx.GetString("user_uuid"))
+	if err != nil {
+		return auth.AccessToken{}, sqlc.User{}, err
+	}
+
+	target, err := is.findUser(context, targetUuid.String())
+	if err != nil {
+		return auth.AccessToken{}, sqlc.User{}, err
+	}
+
+	if target.UserID == actor.UserID {
+		return auth.AccessToken{}, sqlc.User{}, utils.NewError(utils.BadRequestError, "You cannot impersonate yourself")
+	}
+
+	for _, level := range ImpersonationProtectedLevels {
+		if target.UserLevel == level {
+			return auth.AccessToken{}, sqlc.User{}, utils.NewError(utils.ForbiddenError, "This user cannot be impersonated")
+		}
+	}
+
+	permissions, err := is.roleRepo.GetPermissionCodesByUserID(context, target.UserID)
+	if err != nil {
+		return auth.AccessToken{}, sqlc.User{}, utils.WrapError(utils.InternalServerError, "Failed to get user permissions", err)
+	}
+
+	accessToken, err := is.tokenService.GenerateAccessToken(target, auth.AccessTokenOptions{
+		Permissions: permissions,
+		Actor: &auth.Actor{
+			UserUUID: actor.UserUuid.String(),
+			Email:    actor.UserEmail,
+		},
+		TTL: ImpersonationTTL,
+	})
+	if err != nil {
+		return auth.AccessToken{}, sqlc.User{}, utils.WrapError(utils.InternalServerError, "Unable to create impersonation token", err)
+	}
+
+	is.audit(ctx, AuditActionImpersonationStart, actor, target, map[string]any{
+		"jti":        accessToken.JTI,
+		"reason":     reason,
+		"expires_at": accessToken.ExpiresAt,
+	})
+
+	return accessToken, target, nil
+}
+
+// StopImpersonation revokes the impersonation token used for the request
+func (is *impersonationService) StopImpersonation(ctx *gin.Context) error {
+	context := ctx.Request.Context()
+
+	actorUuid := ctx.GetString("actor_uuid")
+	if actorUuid == "" {
+		return utils.NewError(utils.BadRequestError, "This token is not an impersonation token")
+	}
+
+	jti := ctx.GetString("token_jti")
+	if err := is.tokenService.BlacklistAccessToken(jti, time.Now().Add(ImpersonationTTL)); err != nil {
+		return utils.WrapError(utils.InternalServerError, "Unable to revoke impersonation token", err)
+	}
+
+	actor, err := is.findUser(context, actorUuid)
+	if err != nil {
+		return err
+	}
+
+	target, err := is.findUser(context, ctx.GetString("user_uuid"))
+	if err != nil {
+		return err
+	}
+
+	is.audit(ctx, AuditActionImpersonationStop, actor, target, map[string]any{
+		"jti": jti,
+	})
+	return nil
+}
+
+func (is *impersonationService) findUser(ctx context.Context, userUUID string) (sqlc.User, error) {
+	userUuid, err := uuid.Parse(userUUID)
+	if err != nil {
+		return sqlc.User{}, utils.NewError(utils.UnauthorizedError, "Invalid user in access token")
+	}
+
+	user, err := is.userRepo.FindByUUID(ctx, userUuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.User{}, utils.NewError(utils.NotFoundError, "user not found")
+		}
+		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "failed to get user", err)
+	}
+	return user, nil
+}
+
+// audit failures are logged but never block the request
+func (is *impersonationService) audit(ctx *gin.Context, action string, actor, target sqlc.User, metadata map[string]any) {
+	data, _ := json.Marshal(metadata)
+
+	if err := is.auditRepo.Create(ctx.Request.Context(), sqlc.CreateAuditLogParams{
+		AuditActorID:   &actor.UserID,
+		AuditTargetID:  &target.UserID,
+		AuditAction:    action,
+		AuditIpAddress: ctx.ClientIP(),
+		AuditUserAgent: ctx.Request.UserAgent(),
+		AuditMetadata:  data,
+	}); err != nil {
+		loggers.Log.Error().Err(err).Str("action", action).Msg("Failed to write audit log")
+	}
+
+	loggers.Log.Warn().
+		Str("event", action).
+		Str("actor_uuid", actor.UserUuid.String()).
+		Str("user_uuid", target.UserUuid.String()).
+		Str("client_ip", ctx.ClientIP()).
+		Msg("Impersonation audit event")
+}
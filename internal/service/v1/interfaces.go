@@ -1,7 +1,10 @@
 package v1service
 
 import (
+	"context"
 	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/pkg/auth"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -15,12 +18,102 @@ type UserService interface {
 	SoftDeleteUser(ctx *gin.Context, userUuid uuid.UUID) (sqlc.User, error)
 	RestoreUser(ctx *gin.Context, userUuid uuid.UUID) (sqlc.User, error)
 	DeleteUser(ctx *gin.Context, userUuid uuid.UUID) error
+	UnlockUser(ctx *gin.Context, userUuid uuid.UUID) (sqlc.User, error)
 }
 
 type AuthService interface {
-	Login(ctx *gin.Context, email, password string) (string, string, int, error)
+	Login(ctx *gin.Context, email, password, deviceName string) (LoginResult, error)
+	Register(ctx *gin.Context, userParams sqlc.CreateUserParams) (sqlc.User, error)
+	VerifyEmail(ctx *gin.Context, token string) error
+	ResendVerificationEmail(ctx *gin.Context, email string) error
+	RequestMagicLink(ctx *gin.Context, email, deviceName string) (string, error)
+	ConsumeMagicLink(ctx *gin.Context, signedToken, deviceToken string) (LoginResult, error)
 	Logout(ctx *gin.Context, refreshToken string) error
 	RefreshToken(ctx *gin.Context, token string) (string, string, int, error)
 	RequestForgotPassword(ctx *gin.Context, email string) error
 	ResetPassword(ctx *gin.Context, token, password string) error
+	ReportSignIn(ctx *gin.Context, token string) error
+	RequestEmailChange(ctx *gin.Context, userUuid uuid.UUID, newEmail, password string) error
+	ConfirmEmailChange(ctx *gin.Context, token string) error
+	CancelEmailChange(ctx *gin.Context, cancelToken string) error
+	ChangeExpiredPassword(ctx *gin.Context, token, password string) (LoginResult, error)
+	VerifyMfa(ctx *gin.Context, mfaToken, code string) (LoginResult, error)
+	SetupMfa(ctx *gin.Context, mfaToken string) (MfaEnrollment, error)
+	EnrollMfa(ctx *gin.Context, userUuid uuid.UUID) (MfaEnrollment, error)
+	ConfirmMfa(ctx *gin.Context, userUuid uuid.UUID, code string) ([]string, error)
+	DisableMfa(ctx *gin.Context, userUuid uuid.UUID, password, code string) error
+	RegenerateRecoveryCodes(ctx *gin.Context, userUuid uuid.UUID, code string) ([]string, error)
+}
+
+type RoleService interface {
+	GetAllRoles(ctx *gin.Context) ([]sqlc.Role, error)
+	GetRoleByID(ctx *gin.Context, roleID int32) (sqlc.Role, []sqlc.Permission, error)
+	CreateRole(ctx *gin.Context, roleParams sqlc.CreateRoleParams, permissions []string) (sqlc.Role, []sqlc.Permission, error)
+	UpdateRole(ctx *gin.Context, roleParams sqlc.UpdateRoleParams, permissions []string) (sqlc.Role, []sqlc.Permission, error)
+	DeleteRole(ctx *gin.Context, roleID int32) error
+	GetAllPermissions(ctx *gin.Context) ([]sqlc.Permission, error)
+	AssignUserRole(ctx *gin.Context, roleID int32, userUuid uuid.UUID) error
+	RemoveUserRole(ctx *gin.Context, roleID int32, userUuid uuid.UUID) error
+}
+
+type SessionService interface {
+	GetSessions(ctx *gin.Context, userUuid uuid.UUID) ([]auth.Session, error)
+	RevokeSession(ctx *gin.Context, userUuid uuid.UUID, sessionID string) error
+	RevokeAllSessions(ctx *gin.Context, userUuid uuid.UUID) error
+}
+
+// PasswordConfirmer re-checks the password of a signed in user before a sensitive change
+type PasswordConfirmer interface {
+	ConfirmPassword(ctx *gin.Context, user sqlc.User, password, eventType string) error
+	CheckLocalPassword(ctx context.Context, user sqlc.User) error
+}
+
+type ProfileService interface {
+	GetProfile(ctx *gin.Context, userUuid uuid.UUID) (sqlc.User, error)
+	UpdateProfile(ctx *gin.Context, userUuid uuid.UUID, fullname *string, age *int32) (sqlc.User, error)
+	ChangePassword(ctx *gin.Context, userUuid uuid.UUID, currentPassword, newPassword string) error
+	CloseAccount(ctx *gin.Context, userUuid uuid.UUID, password string) (sqlc.AccountClosure, error)
+}
+
+type OAuthService interface {
+	GetAllClients(ctx *gin.Context) ([]sqlc.OauthClient, error)
+	CreateClient(ctx *gin.Context, clientParams sqlc.CreateOAuthClientParams, confidential bool) (sqlc.OauthClient, string, error)
+	RotateClientSecret(ctx *gin.Context, clientID string) (sqlc.OauthClient, string, error)
+	DeleteClient(ctx *gin.Context, clientID string) error
+	PrepareAuthorization(ctx *gin.Context, userUuid uuid.UUID, req AuthorizeRequest) (AuthorizePrompt, error)
+	Authorize(ctx *gin.Context, userUuid uuid.UUID, req AuthorizeRequest, approved bool) (string, error)
+	Token(ctx *gin.Context, req TokenRequest) (TokenResult, error)
+	Introspect(ctx *gin.Context, req ClientTokenRequest) (IntrospectionResult, error)
+	Revoke(ctx *gin.Context, req ClientTokenRequest) error
+}
+
+type ImpersonationService interface {
+	StartImpersonation(ctx *gin.Context, targetUuid uuid.UUID, reason string) (auth.AccessToken, sqlc.User, error)
+	StopImpersonation(ctx *gin.Context) error
+}
+
+type ApiKeyService interface {
+	GetAllAPIKeys(ctx *gin.Context) ([]sqlc.ApiKey, error)
+	CreateAPIKey(ctx *gin.Context, name string, scopes []string, expiresAt *time.Time) (sqlc.ApiKey, string, error)
+	RotateAPIKey(ctx *gin.Context, keyUuid uuid.UUID) (sqlc.ApiKey, string, error)
+	RevokeAPIKey(ctx *gin.Context, keyUuid uuid.UUID) error
+	auth.APIKeyAuthenticator
+}
+
+type SecurityEventService interface {
+	GetSecurityEvents(ctx *gin.Context, filter SecurityEventFilter) ([]sqlc.SecurityEvent, int32, error)
+}
+
+type InvitationService interface {
+	GetPendingInvitations(ctx *gin.Context) ([]sqlc.UserInvitation, error)
+	CreateInvitation(ctx *gin.Context, email, fullname string, level int32) (sqlc.UserInvitation, error)
+	ResendInvitation(ctx *gin.Context, invitationUuid uuid.UUID) (sqlc.UserInvitation, error)
+	RevokeInvitation(ctx *gin.Context, invitationUuid uuid.UUID) error
+	AcceptInvitation(ctx *gin.Context, token, password string) (sqlc.User, error)
+}
+
+type DataExportService interface {
+	RequestExport(ctx *gin.Context, userUuid uuid.UUID) (sqlc.DataExport, error)
+	GetExport(ctx *gin.Context, userUuid, exportUuid uuid.UUID) (sqlc.DataExport, error)
+	DownloadExport(ctx *gin.Context, token string) (sqlc.DataExport, error)
 }
@@ -0,0 +1,211 @@
+package v1service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/hasher"
+	"gin/user-management-api/pkg/loggers"
+	"gin/user-management-api/pkg/mail"
+	"gin/user-management-api/pkg/rabbitmq"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var InvitationTTL = 7 * 24 * time.Hour
+
+type invitationService struct {
+	invitationRepo repository.InvitationRepository
+	userRepo       repository.UserRepository
+	cacheService   cache.RedisCacheService
+	rabbitmq       rabbitmq.RabbitMQSerivce
+}
+
+func NewInvitationService(invitationRepo repository.InvitationRepository, userRepo repository.UserRepository, cacheService cache.RedisCacheService, rabbitmq rabbitmq.RabbitMQSerivce) InvitationService {
+	return &invitationService{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		cacheService:   cacheService,
+		rabbitmq:       rabbitmq,
+	}
+}
+
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (is *invitationService) GetPendingInvitations(ctx *gin.Context) ([]sqlc.UserInvitation, error) {
+	invitations, err := is.invitationRepo.GetPending(ctx.Request.Context())
+	if err != nil {
+		return nil, utils.WrapError(utils.InternalServerError, "failed to get invitations", err)
+	}
+	return invitations, nil
+}
+
+func (is *invitationService) CreateInvitation(ctx *gin.Context, email, fullname string, level int32) (sqlc.UserInvitation, error) {
+	context := ctx.Request.Context()
+	email = utils.NormalizeString(email)
+
+	if _, err := is.userRepo.GetByEmail(context, email); err == nil {
+		return sqlc.UserInvitation{}, utils.NewError(utils.ConflictError, "Email already exists")
+	}
+
+	token, err := utils.GenerateRandomString(32)
+	if err != nil {
+		return sqlc.UserInvitation{}, utils.NewError(utils.InternalServerError, "Failed to generate invitation token")
+	}
+
+	params := sqlc.CreateInvitationParams{
+		InvitationEmail:     email,
+		InvitationFullname:  fullname,
+		InvitationLevel:     level,
+		InvitationTokenHash: hashInvitationToken(token),
+		InvitationExpiresAt: time.Now().Add(InvitationTTL),
+	}
+	if inviterUuid, err := uuid.Parse(ctx.GetString("user_uuid")); err == nil {
+		if inviter, err := is.userRepo.FindByUUID(context, inviterUuid); err == nil {
+			params.InvitationInvitedBy = &inviter.UserID
+		}
+	}
+
+	invitation, err := is.invitationRepo.Create(context, params)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return sqlc.UserInvitation{}, utils.NewError(utils.ConflictError, "A pending invitation already exists for this email")
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return sqlc.UserInvitation{}, utils.NewError(utils.BadRequestError, "Level does not match any role")
+		}
+		return sqlc.UserInvitation{}, utils.WrapError(utils.InternalServerError, "failed to create invitation", err)
+	}
+
+	if err := is.sendInvitationEmail(context, invitation, token); err != nil {
+		return sqlc.UserInvitation{}, err
+	}
+
+	return invitation, nil
+}
+
+// ResendInvitation issues a new token and expiry, links from earlier emails stop working
+func (is *invitationService) ResendInvitation(ctx *gin.Context, invitationUuid uuid.UUID) (sqlc.UserInvitation, error) {
+	context := ctx.Request.Context()
+
+	if _, err := is.findInvitation(context, invitationUuid); err != nil {
+		return sqlc.UserInvitation{}, err
+	}
+
+	token, err := utils.GenerateRandomString(32)
+	if err != nil {
+		return sqlc.UserInvitation{}, utils.NewError(utils.InternalServerError, "Failed to generate invitation token")
+	}
+
+	invitation, err := is.invitationRepo.Renew(context, invitationUuid, hashInvitationToken(token), time.Now().Add(InvitationTTL))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.UserInvitation{}, utils.NewError(utils.BadRequestError, "Invitation is no longer pending")
+		}
+		return sqlc.UserInvitation{}, utils.WrapError(utils.InternalServerError, "failed to renew invitation", err)
+	}
+
+	if err := is.sendInvitationEmail(context, invitation, token); err != nil {
+		return sqlc.UserInvitation{}, err
+	}
+
+	return invitation, nil
+}
+
+func (is *invitationService) RevokeInvitation(ctx *gin.Context, invitationUuid uuid.UUID) error {
+	context := ctx.Request.Context()
+
+	if _, err := is.findInvitation(context, invitationUuid); err != nil {
+		return err
+	}
+
+	if _, err := is.invitationRepo.Revoke(context, invitationUuid); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewError(utils.BadRequestError, "Invitation is no longer pending")
+		}
+		return utils.WrapError(utils.InternalServerError, "failed to revoke invitation", err)
+	}
+
+	return nil
+}
+
+// AcceptInvitation creates an active account, the invitation link already proves the email address
+func (is *invitationService) AcceptInvitation(ctx *gin.Context, token, password string) (sqlc.User, error) {
+	context := ctx.Request.Context()
+
+	invitation, err := is.invitationRepo.FindByTokenHash(context, hashInvitationToken(token))
+	if err != nil || invitation.InvitationAcceptedAt.Valid || invitation.InvitationRevokedAt.Valid || time.Now().After(invitation.InvitationExpiresAt) {
+		return sqlc.User{}, utils.NewError(utils.NotFoundError, "Invalid or expired invitation")
+	}
+
+	hashedPassword, err := hasher.Hash(password)
+	if err != nil {
+		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "failed to hash password", err)
+	}
+
+	user, err := is.invitationRepo.Accept(context, invitation.InvitationID, sqlc.CreateUserParams{
+		UserEmail:    invitation.InvitationEmail,
+		UserPassword: hashedPassword,
+		UserFullname: invitation.InvitationFullname,
+		UserStatus:   UserStatusActive,
+		UserLevel:    invitation.InvitationLevel,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.User{}, utils.NewError(utils.NotFoundError, "Invalid or expired invitation")
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return sqlc.User{}, utils.NewError(utils.ConflictError, "Email already exists")
+		}
+		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "failed to accept invitation", err)
+	}
+
+	if err := is.cacheService.Clear("users:*"); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to clear cache")
+	}
+
+	return user, nil
+}
+
+func (is *invitationService) findInvitation(ctx context.Context, invitationUuid uuid.UUID) (sqlc.UserInvitation, error) {
+	invitation, err := is.invitationRepo.FindByUUID(ctx, invitationUuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.UserInvitation{}, utils.NewError(utils.NotFoundError, "invitation not found")
+		}
+		return sqlc.UserInvitation{}, utils.WrapError(utils.InternalServerError, "failed to get invitation", err)
+	}
+	return invitation, nil
+}
+
+func (is *invitationService) sendInvitationEmail(ctx context.Context, invitation sqlc.UserInvitation, token string) error {
+	inviteLink := fmt.Sprintf("view-to-accept-invite?token=%s", token)
+	mailContent := &mail.Email{
+		To: []mail.Address{
+			{Email: invitation.InvitationEmail, Name: invitation.InvitationFullname},
+		},
+		Subject: "You have been invited to create an account",
+		Text:    fmt.Sprintf("Hi %s, \n\n An administrator has invited you to create an account. Click the link below to choose your password: \n%s\n\n The link will expire in %d days. \n\n Best regard, \n Code With HuyDo", invitation.InvitationFullname, inviteLink, int(InvitationTTL.Hours()/24)),
+	}
+
+	if err := is.rabbitmq.Publish(ctx, "auth_email_queue", mailContent); err != nil {
+		return utils.NewError(utils.InternalServerError, "Failed to send invitation email")
+	}
+
+	return nil
+}
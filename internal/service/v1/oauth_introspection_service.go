@@ -0,0 +1,239 @@
+package v1service
+
+import (
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/pkg/loggers"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	TokenTypeHintAccessToken  = "access_token"
+	TokenTypeHintRefreshToken = "refresh_token"
+)
+
+type ClientTokenRequest struct {
+	Token         string
+	TokenTypeHint string
+	ClientID      string
+	ClientSecret  string
+}
+
+// IntrospectionResult follows RFC 7662 section 2.2, inactive tokens only carry Active
+type IntrospectionResult struct {
+	Active    bool
+	Sub       string
+	Username  string
+	ClientID  string
+	Scope     string
+	Role      int32
+	TokenType string
+	Exp       int64
+	Iat       int64
+	Jti       string
+}
+
+// Introspect is meant for resource servers so only confidential clients may call it.
+// Like Revoke, a client only learns about tokens issued to it, any other token is reported inactive
+func (oas *oauthService) Introspect(ctx *gin.Context, req ClientTokenRequest) (IntrospectionResult, error) {
+	client, err := oas.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return IntrospectionResult{}, err
+	}
+	if client.ClientSecretHash == nil {
+		return IntrospectionResult{}, newOAuthError(http.StatusUnauthorized, "invalid_client", "Public clients cannot introspect tokens")
+	}
+
+	result, ok := oas.introspectToken(ctx, req)
+	if !ok || result.ClientID != client.ClientID {
+		return IntrospectionResult{Active: false}, nil
+	}
+	return result, nil
+}
+
+func (oas *oauthService) introspectToken(ctx *gin.Context, req ClientTokenRequest) (IntrospectionResult, bool) {
+	if req.TokenTypeHint != TokenTypeHintRefreshToken {
+		if result, ok := oas.introspectAccessToken(ctx, req.Token); ok {
+			return result, true
+		}
+	}
+
+	if result, ok := oas.introspectRefreshToken(ctx, req.Token); ok {
+		return result, true
+	}
+
+	if req.TokenTypeHint == TokenTypeHintRefreshToken {
+		return oas.introspectAccessToken(ctx, req.Token)
+	}
+	return IntrospectionResult{}, false
+}
+
+// Revoke always succeeds for unknown tokens as required by RFC 7009 section 2.2
+func (oas *oauthService) Revoke(ctx *gin.Context, req ClientTokenRequest) error {
+	client, err := oas.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return err
+	}
+
+	if req.TokenTypeHint != TokenTypeHintRefreshToken {
+		if handled, err := oas.revokeAccessToken(client, req.Token); handled {
+			return err
+		}
+	}
+
+	if handled, err := oas.revokeRefreshToken(ctx, client, req.Token); handled {
+		return err
+	}
+
+	if req.TokenTypeHint == TokenTypeHintRefreshToken {
+		if _, err := oas.revokeAccessToken(client, req.Token); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (oas *oauthService) introspectAccessToken(ctx *gin.Context, token string) (IntrospectionResult, bool) {
+	_, claims, err := oas.tokenService.ParseToken(token)
+	if err != nil {
+		return IntrospectionResult{}, false
+	}
+
+	jti, _ := claims["jti"].(string)
+	if exists, err := oas.cacheService.Exited("backlist:" + jti); err == nil && exists {
+		return IntrospectionResult{}, false
+	}
+
+	payload, err := oas.tokenService.DecryptAccessTokenPayload(token)
+	if err != nil {
+		return IntrospectionResult{}, false
+	}
+
+	exp, _ := claims["exp"].(float64)
+	iat, _ := claims["iat"].(float64)
+	result := IntrospectionResult{
+		Active:    true,
+		Sub:       payload.UserUUID,
+		Username:  payload.Email,
+		ClientID:  payload.ClientID,
+		Scope:     strings.Join(payload.Scopes, " "),
+		Role:      payload.Role,
+		TokenType: "Bearer",
+		Exp:       int64(exp),
+		Iat:       int64(iat),
+		Jti:       jti,
+	}
+
+	// Client credential tokens are issued to the client itself
+	if payload.UserUUID == "" {
+		result.Sub = payload.ClientID
+		return result, true
+	}
+
+	if _, ok := oas.activeTokenOwner(ctx, payload.UserUUID); !ok {
+		return IntrospectionResult{}, false
+	}
+	return result, true
+}
+
+func (oas *oauthService) introspectRefreshToken(ctx *gin.Context, token string) (IntrospectionResult, bool) {
+	refreshToken, err := oas.tokenService.ValidateRefreshToken(token)
+	if err != nil {
+		return IntrospectionResult{}, false
+	}
+
+	user, ok := oas.activeTokenOwner(ctx, refreshToken.UserUUID)
+	if !ok {
+		return IntrospectionResult{}, false
+	}
+
+	result := IntrospectionResult{
+		Active:    true,
+		Sub:       refreshToken.UserUUID,
+		Username:  user.UserEmail,
+		Role:      user.UserLevel,
+		TokenType: TokenTypeHintRefreshToken,
+		Exp:       refreshToken.ExpiresAt.Unix(),
+	}
+
+	if refreshToken.SessionID != "" {
+		session, err := oas.tokenService.GetSession(refreshToken.SessionID)
+		if err != nil {
+			return IntrospectionResult{}, false
+		}
+		result.ClientID = session.ClientID
+		result.Scope = strings.Join(session.Scopes, " ")
+	}
+	return result, true
+}
+
+// activeTokenOwner reports tokens of deleted, inactive or banned users as inactive
+func (oas *oauthService) activeTokenOwner(ctx *gin.Context, userUUID string) (sqlc.User, bool) {
+	userUuid, err := uuid.Parse(userUUID)
+	if err != nil {
+		return sqlc.User{}, false
+	}
+
+	user, err := oas.userRepo.FindByUUID(ctx.Request.Context(), userUuid)
+	if err != nil || checkAccountStatus(user.UserStatus) != nil {
+		return sqlc.User{}, false
+	}
+	return user, true
+}
+
+func (oas *oauthService) revokeAccessToken(client sqlc.OauthClient, token string) (bool, error) {
+	_, claims, err := oas.tokenService.ParseToken(token)
+	if err != nil {
+		return false, nil
+	}
+
+	payload, err := oas.tokenService.DecryptAccessTokenPayload(token)
+	if err != nil {
+		return false, nil
+	}
+	if payload.ClientID != client.ClientID {
+		return true, newOAuthError(http.StatusBadRequest, "unauthorized_client", "The token was not issued to this client")
+	}
+
+	jti, _ := claims["jti"].(string)
+	exp, _ := claims["exp"].(float64)
+	if err := oas.tokenService.BlacklistAccessToken(jti, time.Unix(int64(exp), 0)); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to revoke access token")
+	}
+	return true, nil
+}
+
+// revokeRefreshToken ends the whole session so access tokens issued from it stop working too
+func (oas *oauthService) revokeRefreshToken(ctx *gin.Context, client sqlc.OauthClient, token string) (bool, error) {
+	refreshToken, err := oas.tokenService.ValidateRefreshToken(token)
+	if err != nil {
+		return false, nil
+	}
+
+	var session auth.Session
+	if refreshToken.SessionID != "" {
+		session, _ = oas.tokenService.GetSession(refreshToken.SessionID)
+	}
+	if session.ClientID != client.ClientID {
+		return true, newOAuthError(http.StatusBadRequest, "unauthorized_client", "The token was not issued to this client")
+	}
+
+	if refreshToken.SessionID != "" {
+		err = oas.tokenService.RevokeSession(refreshToken.UserUUID, refreshToken.SessionID)
+	} else {
+		err = oas.tokenService.RevokeRefreshToken(token)
+	}
+	if err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to revoke refresh token")
+		return true, nil
+	}
+
+	oas.recordSecurityEvent(ctx, SecurityEvent{Type: SecurityEventLogout, Outcome: SecurityOutcomeSuccess, Reason: "token_revoked", UserID: oas.securityEventUserID(ctx, refreshToken.UserUUID)})
+	return true, nil
+}
@@ -0,0 +1,529 @@
+package v1service
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/auth"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	GrantAuthorizationCode = "authorization_code"
+	GrantRefreshToken      = "refresh_token"
+	GrantClientCredentials = "client_credentials"
+)
+
+var OAuthCodeTTL = 10 * time.Minute
+
+// OAuthError is rendered by the token endpoint in the RFC 6749 error format
+type OAuthError struct {
+	Status      int
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Description
+}
+
+func newOAuthError(status int, code, description string) error {
+	return &OAuthError{Status: status, Code: code, Description: description}
+}
+
+type AuthorizeRequest struct {
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+type AuthorizePrompt struct {
+	Client         sqlc.OauthClient
+	Scopes         []string
+	ConsentGranted bool
+}
+
+type TokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+	ClientID     string
+	ClientSecret string
+}
+
+type TokenResult struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int
+	Scopes       []string
+}
+
+type authorizationCode struct {
+	ClientID      string   `json:"client_id"`
+	UserUUID      string   `json:"user_uuid"`
+	RedirectURI   string   `json:"redirect_uri"`
+	Scopes        []string `json:"scopes"`
+	CodeChallenge string   `json:"code_challenge"`
+}
+
+type oauthService struct {
+	*authService
+	oauthRepo repository.OAuthRepository
+}
+
+// NewOAuthService reuses the auth service to issue session bound tokens for authorization code grants
+func NewOAuthService(authService *authService, oauthRepo repository.OAuthRepository) OAuthService {
+	return &oauthService{
+		authService: authService,
+		oauthRepo:   oauthRepo,
+	}
+}
+
+func (oas *oauthService) GetAllClients(ctx *gin.Context) ([]sqlc.OauthClient, error) {
+	clients, err := oas.oauthRepo.GetAllClients(ctx.Request.Context())
+	if err != nil {
+		return []sqlc.OauthClient{}, utils.WrapError(utils.InternalServerError, "failed to get oauth clients", err)
+	}
+	return clients, nil
+}
+
+func (oas *oauthService) CreateClient(ctx *gin.Context, clientParams sqlc.CreateOAuthClientParams, confidential bool) (sqlc.OauthClient, string, error) {
+	context := ctx.Request.Context()
+
+	if err := oas.validateClientScopes(ctx, clientParams.ClientScopes); err != nil {
+		return sqlc.OauthClient{}, "", err
+	}
+
+	if slices.Contains(clientParams.ClientGrantTypes, GrantAuthorizationCode) && len(clientParams.ClientRedirectUris) == 0 {
+		return sqlc.OauthClient{}, "", utils.NewError(utils.BadRequestError, "Redirect URIs are required for the authorization_code grant")
+	}
+
+	if !confidential && slices.Contains(clientParams.ClientGrantTypes, GrantClientCredentials) {
+		return sqlc.OauthClient{}, "", utils.NewError(utils.BadRequestError, "Public clients cannot use the client_credentials grant")
+	}
+
+	clientID, err := utils.GenerateRandomString(18)
+	if err != nil {
+		return sqlc.OauthClient{}, "", utils.WrapError(utils.InternalServerError, "failed to generate client id", err)
+	}
+	clientParams.ClientID = clientID
+
+	var secret string
+	if confidential {
+		secret, clientParams.ClientSecretHash, err = generateClientSecret()
+		if err != nil {
+			return sqlc.OauthClient{}, "", err
+		}
+	}
+
+	client, err := oas.oauthRepo.CreateClient(context, clientParams)
+	if err != nil {
+		return sqlc.OauthClient{}, "", utils.WrapError(utils.InternalServerError, "failed to create oauth client", err)
+	}
+
+	return client, secret, nil
+}
+
+func (oas *oauthService) RotateClientSecret(ctx *gin.Context, clientID string) (sqlc.OauthClient, string, error) {
+	context := ctx.Request.Context()
+
+	client, err := oas.findClient(ctx, clientID)
+	if err != nil {
+		return sqlc.OauthClient{}, "", err
+	}
+
+	if client.ClientSecretHash == nil {
+		return sqlc.OauthClient{}, "", utils.NewError(utils.BadRequestError, "Public clients do not have a secret")
+	}
+
+	secret, secretHash, err := generateClientSecret()
+	if err != nil {
+		return sqlc.OauthClient{}, "", err
+	}
+
+	client, err = oas.oauthRepo.UpdateClientSecret(context, clientID, secretHash)
+	if err != nil {
+		return sqlc.OauthClient{}, "", utils.WrapError(utils.InternalServerError, "failed to rotate client secret", err)
+	}
+
+	return client, secret, nil
+}
+
+func (oas *oauthService) DeleteClient(ctx *gin.Context, clientID string) error {
+	rows, err := oas.oauthRepo.DeleteClient(ctx.Request.Context(), clientID)
+	if err != nil {
+		return utils.WrapError(utils.InternalServerError, "failed to delete oauth client", err)
+	}
+
+	if rows == 0 {
+		return utils.NewError(utils.NotFoundError, "oauth client not found")
+	}
+	return nil
+}
+
+func (oas *oauthService) PrepareAuthorization(ctx *gin.Context, userUuid uuid.UUID, req AuthorizeRequest) (AuthorizePrompt, error) {
+	user, err := oas.findUserByUUID(ctx.Request.Context(), userUuid)
+	if err != nil {
+		return AuthorizePrompt{}, err
+	}
+
+	client, scopes, err := oas.validateAuthorizeRequest(ctx, req)
+	if err != nil {
+		return AuthorizePrompt{}, err
+	}
+
+	prompt := AuthorizePrompt{Client: client, Scopes: scopes}
+
+	consent, err := oas.oauthRepo.FindConsent(ctx.Request.Context(), user.UserID, client.OauthClientID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return AuthorizePrompt{}, utils.WrapError(utils.InternalServerError, "failed to get consent", err)
+	}
+	if err == nil {
+		prompt.ConsentGranted = isSubset(scopes, consent.ConsentScopes)
+	}
+
+	return prompt, nil
+}
+
+// Authorize returns the redirect URL carrying either the authorization code or an access_denied error
+func (oas *oauthService) Authorize(ctx *gin.Context, userUuid uuid.UUID, req AuthorizeRequest, approved bool) (string, error) {
+	context := ctx.Request.Context()
+
+	user, err := oas.findUserByUUID(context, userUuid)
+	if err != nil {
+		return "", err
+	}
+
+	client, scopes, err := oas.validateAuthorizeRequest(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+
+	if !approved {
+		query.Set("error", "access_denied")
+		return buildRedirectURL(req.RedirectURI, query), nil
+	}
+
+	if _, err := oas.oauthRepo.SaveConsent(context, user.UserID, client.OauthClientID, scopes); err != nil {
+		return "", utils.WrapError(utils.InternalServerError, "failed to save consent", err)
+	}
+
+	code, err := utils.GenerateRandomString(30)
+	if err != nil {
+		return "", utils.WrapError(utils.InternalServerError, "failed to generate authorization code", err)
+	}
+
+	authCode := authorizationCode{
+		ClientID:      client.ClientID,
+		UserUUID:      user.UserUuid.String(),
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		CodeChallenge: req.CodeChallenge,
+	}
+	if err := oas.cacheService.Set("oauth_code:"+code, authCode, OAuthCodeTTL); err != nil {
+		return "", utils.WrapError(utils.InternalServerError, "failed to store authorization code", err)
+	}
+
+	query.Set("code", code)
+	return buildRedirectURL(req.RedirectURI, query), nil
+}
+
+func (oas *oauthService) Token(ctx *gin.Context, req TokenRequest) (TokenResult, error) {
+	client, err := oas.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return TokenResult{}, err
+	}
+
+	if !slices.Contains(client.ClientGrantTypes, req.GrantType) {
+		return TokenResult{}, newOAuthError(http.StatusBadRequest, "unauthorized_client", "The client is not allowed to use this grant type")
+	}
+
+	switch req.GrantType {
+	case GrantAuthorizationCode:
+		return oas.exchangeAuthorizationCode(ctx, client, req)
+	case GrantRefreshToken:
+		return oas.exchangeRefreshToken(ctx, client, req)
+	case GrantClientCredentials:
+		return oas.exchangeClientCredentials(client, req)
+	default:
+		return TokenResult{}, newOAuthError(http.StatusBadRequest, "unsupported_grant_type", "Grant type is not supported")
+	}
+}
+
+func (oas *oauthService) exchangeAuthorizationCode(ctx *gin.Context, client sqlc.OauthClient, req TokenRequest) (TokenResult, error) {
+	context := ctx.Request.Context()
+
+	if req.Code == "" || req.CodeVerifier == "" {
+		return TokenResult{}, newOAuthError(http.StatusBadRequest, "invalid_request", "code and code_verifier are required")
+	}
+
+	// Code chỉ được dùng một lần nên đọc và xoá trong cùng một lệnh, hai request song song không thể cùng đổi một code
+	var authCode authorizationCode
+	if err := oas.cacheService.GetDel("oauth_code:"+req.Code, &authCode); err != nil || authCode.ClientID == "" {
+		return TokenResult{}, newOAuthError(http.StatusBadRequest, "invalid_grant", "Authorization code is invalid or expired")
+	}
+
+	if authCode.ClientID != client.ClientID || authCode.RedirectURI != req.RedirectURI {
+		return TokenResult{}, newOAuthError(http.StatusBadRequest, "invalid_grant", "Authorization code was not issued to this client")
+	}
+
+	if !verifyCodeChallenge(req.CodeVerifier, authCode.CodeChallenge) {
+		return TokenResult{}, newOAuthError(http.StatusBadRequest, "invalid_grant", "PKCE verification failed")
+	}
+
+	userUuid, err := uuid.Parse(authCode.UserUUID)
+	if err != nil {
+		return TokenResult{}, newOAuthError(http.StatusBadRequest, "invalid_grant", "Authorization code is invalid or expired")
+	}
+
+	user, err := oas.userRepo.FindByUUID(context, userUuid)
+	if err != nil {
+		return TokenResult{}, newOAuthError(http.StatusBadRequest, "invalid_grant", "User not found")
+	}
+
+	session := oas.newSession(ctx, client.ClientName)
+	session.ClientID = client.ClientID
+	session.Scopes = authCode.Scopes
+
+	result, err := oas.issueTokens(context, user, session)
+	if err != nil {
+		return TokenResult{}, err
+	}
+
+	token := TokenResult{
+		AccessToken: result.AccessToken,
+		ExpiresIn:   result.ExpiresIn,
+		Scopes:      authCode.Scopes,
+	}
+	if slices.Contains(client.ClientGrantTypes, GrantRefreshToken) {
+		token.RefreshToken = result.RefreshToken
+	}
+	return token, nil
+}
+
+func (oas *oauthService) exchangeRefreshToken(ctx *gin.Context, client sqlc.OauthClient, req TokenRequest) (TokenResult, error) {
+	if req.RefreshToken == "" {
+		return TokenResult{}, newOAuthError(http.StatusBadRequest, "invalid_request", "refresh_token is required")
+	}
+
+	// Refresh token đã bị thay thế vẫn phải đi qua RefreshToken để phát hiện reuse
+	token, err := oas.tokenService.ValidateRefreshToken(req.RefreshToken)
+	if err == nil {
+		session, err := oas.tokenService.GetSession(token.SessionID)
+		if err != nil || session.ClientID != client.ClientID {
+			return TokenResult{}, newOAuthError(http.StatusBadRequest, "invalid_grant", "Refresh token was not issued to this client")
+		}
+	}
+
+	accessToken, refreshToken, expiresIn, err := oas.RefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) && appErr.Code == utils.UnauthorizedError {
+			return TokenResult{}, newOAuthError(http.StatusBadRequest, "invalid_grant", appErr.Message)
+		}
+		return TokenResult{}, err
+	}
+
+	session, err := oas.tokenService.GetSession(token.SessionID)
+	if err != nil {
+		return TokenResult{}, utils.WrapError(utils.InternalServerError, "Cannot get session", err)
+	}
+
+	return TokenResult{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    expiresIn,
+		Scopes:       session.Scopes,
+	}, nil
+}
+
+func (oas *oauthService) exchangeClientCredentials(client sqlc.OauthClient, req TokenRequest) (TokenResult, error) {
+	if client.ClientSecretHash == nil {
+		return TokenResult{}, newOAuthError(http.StatusBadRequest, "unauthorized_client", "Public clients cannot use the client_credentials grant")
+	}
+
+	scopes, err := resolveScopes(req.Scope, client.ClientScopes)
+	if err != nil {
+		return TokenResult{}, err
+	}
+
+	accessToken, err := oas.tokenService.GenerateClientAccessToken(client.ClientID, scopes)
+	if err != nil {
+		return TokenResult{}, utils.WrapError(utils.InternalServerError, "unable to create access token", err)
+	}
+
+	return TokenResult{
+		AccessToken: accessToken.Token,
+		ExpiresIn:   int(auth.AccessTokenTTL.Seconds()),
+		Scopes:      scopes,
+	}, nil
+}
+
+// authenticateClient requires the secret for confidential clients, public clients only identify themselves
+func (oas *oauthService) authenticateClient(ctx *gin.Context, clientID, clientSecret string) (sqlc.OauthClient, error) {
+	if basicID, basicSecret, ok := ctx.Request.BasicAuth(); ok {
+		clientID, clientSecret = basicID, basicSecret
+	}
+
+	if clientID == "" {
+		return sqlc.OauthClient{}, newOAuthError(http.StatusUnauthorized, "invalid_client", "Client authentication failed")
+	}
+
+	client, err := oas.oauthRepo.FindClientByClientID(ctx.Request.Context(), clientID)
+	if err != nil {
+		return sqlc.OauthClient{}, newOAuthError(http.StatusUnauthorized, "invalid_client", "Client authentication failed")
+	}
+
+	if client.ClientSecretHash == nil {
+		return client, nil
+	}
+
+	if clientSecret == "" || bcrypt.CompareHashAndPassword([]byte(*client.ClientSecretHash), []byte(clientSecret)) != nil {
+		return sqlc.OauthClient{}, newOAuthError(http.StatusUnauthorized, "invalid_client", "Client authentication failed")
+	}
+	return client, nil
+}
+
+func (oas *oauthService) validateAuthorizeRequest(ctx *gin.Context, req AuthorizeRequest) (sqlc.OauthClient, []string, error) {
+	client, err := oas.findClient(ctx, req.ClientID)
+	if err != nil {
+		return sqlc.OauthClient{}, nil, err
+	}
+
+	if !slices.Contains(client.ClientGrantTypes, GrantAuthorizationCode) {
+		return sqlc.OauthClient{}, nil, utils.NewError(utils.BadRequestError, "The client is not allowed to use the authorization_code grant")
+	}
+
+	if !slices.Contains(client.ClientRedirectUris, req.RedirectURI) {
+		return sqlc.OauthClient{}, nil, utils.NewError(utils.BadRequestError, "redirect_uri is not registered for this client")
+	}
+
+	if req.CodeChallengeMethod != "S256" {
+		return sqlc.OauthClient{}, nil, utils.NewError(utils.BadRequestError, "Only the S256 code_challenge_method is supported")
+	}
+
+	scopes, err := resolveScopes(req.Scope, client.ClientScopes)
+	if err != nil {
+		return sqlc.OauthClient{}, nil, utils.NewError(utils.BadRequestError, err.Error())
+	}
+
+	return client, scopes, nil
+}
+
+func (oas *oauthService) findClient(ctx *gin.Context, clientID string) (sqlc.OauthClient, error) {
+	client, err := oas.oauthRepo.FindClientByClientID(ctx.Request.Context(), clientID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.OauthClient{}, utils.NewError(utils.NotFoundError, "oauth client not found")
+		}
+		return sqlc.OauthClient{}, utils.WrapError(utils.InternalServerError, "failed to get oauth client", err)
+	}
+	return client, nil
+}
+
+func (oas *oauthService) validateClientScopes(ctx *gin.Context, scopes []string) error {
+	permissions, err := oas.roleRepo.GetAllPermissions(ctx.Request.Context())
+	if err != nil {
+		return utils.WrapError(utils.InternalServerError, "failed to get all permissions", err)
+	}
+
+	known := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		known[p.PermissionCode] = true
+	}
+
+	for _, scope := range scopes {
+		if !known[scope] {
+			return utils.NewError(utils.BadRequestError, fmt.Sprintf("Unknown scope: %s", scope))
+		}
+	}
+	return nil
+}
+
+func generateClientSecret() (string, *string, error) {
+	secret, err := utils.GenerateRandomString(33)
+	if err != nil {
+		return "", nil, utils.WrapError(utils.InternalServerError, "failed to generate client secret", err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return "", nil, utils.WrapError(utils.InternalServerError, "failed to hash client secret", err)
+	}
+
+	secretHash := string(hash)
+	return secret, &secretHash, nil
+}
+
+// resolveScopes falls back to every scope of the client when none is requested
+func resolveScopes(requested string, allowed []string) ([]string, error) {
+	scopes := strings.Fields(requested)
+	if len(scopes) == 0 {
+		return allowed, nil
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(allowed, scope) {
+			return nil, newOAuthError(http.StatusBadRequest, "invalid_scope", fmt.Sprintf("Scope %s is not allowed for this client", scope))
+		}
+	}
+	return scopes, nil
+}
+
+func verifyCodeChallenge(verifier, challenge string) bool {
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+func buildRedirectURL(redirectURI string, query url.Values) string {
+	separator := "?"
+	if strings.Contains(redirectURI, "?") {
+		separator = "&"
+	}
+	return redirectURI + separator + query.Encode()
+}
+
+func intersectScopes(permissions, scopes []string) []string {
+	result := make([]string, 0, len(scopes))
+	for _, p := range permissions {
+		if slices.Contains(scopes, p) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func isSubset(items, set []string) bool {
+	for _, item := range items {
+		if !slices.Contains(set, item) {
+			return false
+		}
+	}
+	return true
+}
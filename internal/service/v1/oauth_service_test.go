@@ -0,0 +1,23 @@
+package v1service
+
+import "testing"
+
+func TestVerifyCodeChallenge(t *testing.T) {
+	// RFC 7636 appendix B
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	if !verifyCodeChallenge(verifier, challenge) {
+		t.Errorf("RFC 7636 verifier was rejected")
+	}
+	if verifyCodeChallenge(verifier+"x", challenge) {
+		t.Errorf("wrong verifier was accepted")
+	}
+	if verifyCodeChallenge("", challenge) {
+		t.Errorf("empty verifier was accepted")
+	}
+	// plain is not supported, the verifier itself is not a valid challenge
+	if verifyCodeChallenge(verifier, verifier) {
+		t.Errorf("plain challenge was accepted")
+	}
+}
@@ -0,0 +1,71 @@
+package v1service
+
+import (
+	"context"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/hasher"
+	"time"
+)
+
+// PASSWORD_HISTORY_SIZE previous passwords cannot be reused on top of the current one, 0 only blocks the current one
+func passwordHistorySize() int32 {
+	size := utils.GetIntEnv("PASSWORD_HISTORY_SIZE", 5)
+	if size < 0 {
+		return 0
+	}
+	return int32(size)
+}
+
+// PASSWORD_MAX_AGE_DAYS set to 0 disables password expiry
+func passwordMaxAge() time.Duration {
+	days := utils.GetIntEnv("PASSWORD_MAX_AGE_DAYS", 90)
+	if days <= 0 {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func isPasswordExpired(user sqlc.User) bool {
+	maxAge := passwordMaxAge()
+	return maxAge > 0 && time.Since(user.UserPasswordChangedAt) > maxAge
+}
+
+func checkPasswordReuse(ctx context.Context, userRepo repository.UserRepository, user sqlc.User, password string) error {
+	hashes, err := userRepo.GetPasswordHistory(ctx, user.UserID, passwordHistorySize())
+	if err != nil {
+		return utils.WrapError(utils.InternalServerError, "Failed to get password history", err)
+	}
+
+	for _, hash := range append([]string{user.UserPassword}, hashes...) {
+		if ok, _ := hasher.Verify(hash, password); ok {
+			return utils.NewError(utils.BadRequestError, "New password must be different from your recent passwords")
+		}
+	}
+
+	return nil
+}
+
+// updatePassword rejects the current and recent passwords before storing the new hash,
+// the previous hash is moved to the password history by the repository
+func updatePassword(ctx context.Context, userRepo repository.UserRepository, user sqlc.User, password string) error {
+	if err := checkPasswordReuse(ctx, userRepo, user, password); err != nil {
+		return err
+	}
+
+	hashPassword, err := hasher.Hash(password)
+	if err != nil {
+		return utils.WrapError(utils.InternalServerError, "Failed to hash password", err)
+	}
+
+	input := sqlc.UpdatePasswordParams{
+		UserUuid:     user.UserUuid,
+		UserPassword: hashPassword,
+	}
+
+	if _, err := userRepo.UpdatePassword(ctx, input, passwordHistorySize()); err != nil {
+		return utils.WrapError(utils.InternalServerError, "Failed to update new password", err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package v1service
+
+import (
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/loggers"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type profileService struct {
+	userRepo     repository.UserRepository
+	closureRepo  repository.AccountClosureRepository
+	eventRepo    repository.SecurityEventRepository
+	passwords    PasswordConfirmer
+	tokenService auth.TokenService
+	cacheService cache.RedisCacheService
+}
+
+func NewProfileService(userRepo repository.UserRepository, closureRepo repository.AccountClosureRepository, eventRepo repository.SecurityEventRepository, passwords PasswordConfirmer, tokenService auth.TokenService, cacheService cache.RedisCacheService) ProfileService {
+	return &profileService{
+		userRepo:     userRepo,
+		closureRepo:  closureRepo,
+		eventRepo:    eventRepo,
+		passwords:    passwords,
+		tokenService: tokenService,
+		cacheService: cacheService,
+	}
+}
+
+func (ps *profileService) GetProfile(ctx *gin.Context, userUuid uuid.UUID) (sqlc.User, error) {
+	user, err := ps.userRepo.FindByUUID(ctx.Request.Context(), userUuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.User{}, utils.NewError(utils.NotFoundError, "user not found")
+		}
+		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "failed to get user", err)
+	}
+	return user, nil
+}
+
+// UpdateProfile only receives the fields a user may change on their own account
+func (ps *profileService) UpdateProfile(ctx *gin.Context, userUuid uuid.UUID, fullname *string, age *int32) (sqlc.User, error) {
+	user, err := ps.userRepo.Update(ctx.Request.Context(), sqlc.UpdateUserByUuidParams{
+		UserFullname: fullname,
+		UserAge:      age,
+		UserUuid:     userUuid,
+	}, passwordHistorySize())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.User{}, utils.NewError(utils.NotFoundError, "user not found")
+		}
+		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "failed to update profile", err)
+	}
+
+	ps.clearUserCache()
+	return user, nil
+}
+
+// ChangePassword keeps the session that made the request and signs out every other device
+func (ps *profileService) ChangePassword(ctx *gin.Context, userUuid uuid.UUID, currentPassword, newPassword string) error {
+	context := ctx.Request.Context()
+
+	user, err := ps.GetProfile(ctx, userUuid)
+	if err != nil {
+		return err
+	}
+
+	// Directory passwords are changed in the directory
+	if err := ps.passwords.CheckLocalPassword(context, user); err != nil {
+		return err
+	}
+
+	if err := ps.passwords.ConfirmPassword(ctx, user, currentPassword, SecurityEventPasswordChange); err != nil {
+		return err
+	}
+
+	if err := updatePassword(context, ps.userRepo, user, newPassword); err != nil {
+		return err
+	}
+
+	recordSecurityEvent(ctx, ps.eventRepo, SecurityEvent{Type: SecurityEventPasswordChange, Outcome: SecurityOutcomeSuccess, UserID: &user.UserID, Email: user.UserEmail})
+	return ps.revokeOtherSessions(user.UserUuid.String(), ctx.GetString("session_id"))
+}
+
+// CloseAccount soft deletes the account and signs out all sessions, logging in before the purge date restores it
+func (ps *profileService) CloseAccount(ctx *gin.Context, userUuid uuid.UUID, password string) (sqlc.AccountClosure, error) {
+	user, err := ps.GetProfile(ctx, userUuid)
+	if err != nil {
+		return sqlc.AccountClosure{}, err
+	}
+
+	if err := ps.passwords.ConfirmPassword(ctx, user, password, SecurityEventAccountClosed); err != nil {
+		return sqlc.AccountClosure{}, err
+	}
+
+	closure, err := ps.closureRepo.Close(ctx.Request.Context(), userUuid, time.Now().Add(AccountClosureGracePeriod()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.AccountClosure{}, utils.NewError(utils.NotFoundError, "user not found")
+		}
+		return sqlc.AccountClosure{}, utils.WrapError(utils.InternalServerError, "failed to close account", err)
+	}
+
+	markUserRemoved(ps.cacheService, userUuid.String())
+	if err := ps.tokenService.RevokeAllSessions(userUuid.String()); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to revoke sessions of closed account")
+	}
+
+	loggers.Log.Info().
+		Str("event", "account_closed").
+		Str("user_uuid", userUuid.String()).
+		Time("purge_at", closure.ClosurePurgeAt).
+		Msg("Account closed by its owner")
+
+	recordSecurityEvent(ctx, ps.eventRepo, SecurityEvent{Type: SecurityEventAccountClosed, Outcome: SecurityOutcomeSuccess, UserID: &user.UserID, Email: user.UserEmail})
+
+	ps.clearUserCache()
+	return closure, nil
+}
+
+func (ps *profileService) revokeOtherSessions(userUUID, currentSessionID string) error {
+	sessions, err := ps.tokenService.ListSessions(userUUID)
+	if err != nil {
+		return utils.WrapError(utils.InternalServerError, "Failed to get sessions", err)
+	}
+
+	for _, session := range sessions {
+		if session.ID == currentSessionID {
+			continue
+		}
+		if err := ps.tokenService.RevokeSession(userUUID, session.ID); err != nil {
+			return utils.WrapError(utils.InternalServerError, fmt.Sprintf("Failed to revoke session %s", session.ID), err)
+		}
+	}
+	return nil
+}
+
+func (ps *profileService) clearUserCache() {
+	if err := ps.cacheService.Clear("users:*"); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to clear cache")
+	}
+}
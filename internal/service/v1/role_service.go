@@ -0,0 +1,215 @@
+package v1service
+
+import (
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/loggers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type roleService struct {
+	roleRepo repository.RoleRepository
+	userRepo repository.UserRepository
+	cache    cache.RedisCacheService
+}
+
+func NewRoleService(roleRepo repository.RoleRepository, userRepo repository.UserRepository, cacheService cache.RedisCacheService) RoleService {
+	return &roleService{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+		cache:    cacheService,
+	}
+}
+
+func (rs *roleService) GetAllRoles(ctx *gin.Context) ([]sqlc.Role, error) {
+	context := ctx.Request.Context()
+	roles, err := rs.roleRepo.GetAll(context)
+	if err != nil {
+		return []sqlc.Role{}, utils.WrapError(utils.InternalServerError, "failed to get all roles", err)
+	}
+	return roles, nil
+}
+
+func (rs *roleService) GetRoleByID(ctx *gin.Context, roleID int32) (sqlc.Role, []sqlc.Permission, error) {
+	context := ctx.Request.Context()
+	role, err := rs.roleRepo.FindByID(context, roleID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Role{}, nil, utils.NewError(utils.NotFoundError, "role not found")
+		}
+		return sqlc.Role{}, nil, utils.WrapError(utils.InternalServerError, "failed to get role", err)
+	}
+
+	permissions, err := rs.roleRepo.GetPermissions(context, roleID)
+	if err != nil {
+		return sqlc.Role{}, nil, utils.WrapError(utils.InternalServerError, "failed to get role permissions", err)
+	}
+
+	return role, permissions, nil
+}
+
+func (rs *roleService) CreateRole(ctx *gin.Context, roleParams sqlc.CreateRoleParams, permissions []string) (sqlc.Role, []sqlc.Permission, error) {
+	context := ctx.Request.Context()
+
+	roleParams.RoleCode = utils.NormalizeString(roleParams.RoleCode)
+	if err := rs.validatePermissions(ctx, permissions); err != nil {
+		return sqlc.Role{}, nil, err
+	}
+
+	role, err := rs.roleRepo.Create(context, roleParams, permissions)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return sqlc.Role{}, nil, utils.NewError(utils.ConflictError, "Role code already exists")
+		}
+		return sqlc.Role{}, nil, utils.WrapError(utils.InternalServerError, "failed to create role", err)
+	}
+
+	return rs.GetRoleByID(ctx, role.RoleID)
+}
+
+func (rs *roleService) UpdateRole(ctx *gin.Context, roleParams sqlc.UpdateRoleParams, permissions []string) (sqlc.Role, []sqlc.Permission, error) {
+	context := ctx.Request.Context()
+
+	if err := rs.validatePermissions(ctx, permissions); err != nil {
+		return sqlc.Role{}, nil, err
+	}
+
+	_, err := rs.roleRepo.Update(context, roleParams, permissions)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Role{}, nil, utils.NewError(utils.NotFoundError, "role not found")
+		}
+		return sqlc.Role{}, nil, utils.WrapError(utils.InternalServerError, "failed to update role", err)
+	}
+
+	rs.clearPermissionCache()
+
+	return rs.GetRoleByID(ctx, roleParams.RoleID)
+}
+
+func (rs *roleService) DeleteRole(ctx *gin.Context, roleID int32) error {
+	context := ctx.Request.Context()
+
+	role, _, err := rs.GetRoleByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+
+	if role.RoleIsSystem {
+		return utils.NewError(utils.ForbiddenError, "System roles cannot be deleted")
+	}
+
+	if _, err := rs.roleRepo.Delete(context, roleID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return utils.NewError(utils.ConflictError, "Role is still used as user level")
+		}
+		return utils.WrapError(utils.InternalServerError, "failed to delete role", err)
+	}
+
+	rs.clearPermissionCache()
+
+	return nil
+}
+
+func (rs *roleService) GetAllPermissions(ctx *gin.Context) ([]sqlc.Permission, error) {
+	context := ctx.Request.Context()
+	permissions, err := rs.roleRepo.GetAllPermissions(context)
+	if err != nil {
+		return []sqlc.Permission{}, utils.WrapError(utils.InternalServerError, "failed to get all permissions", err)
+	}
+	return permissions, nil
+}
+
+func (rs *roleService) AssignUserRole(ctx *gin.Context, roleID int32, userUuid uuid.UUID) error {
+	context := ctx.Request.Context()
+
+	if _, _, err := rs.GetRoleByID(ctx, roleID); err != nil {
+		return err
+	}
+
+	user, err := rs.userRepo.FindByUUID(context, userUuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewError(utils.NotFoundError, "user not found")
+		}
+		return utils.WrapError(utils.InternalServerError, "failed to get user", err)
+	}
+
+	if err := rs.roleRepo.AssignUserRole(context, user.UserID, roleID); err != nil {
+		return utils.WrapError(utils.InternalServerError, "failed to assign role", err)
+	}
+
+	rs.clearUserPermissionCache(user.UserUuid)
+
+	return nil
+}
+
+func (rs *roleService) RemoveUserRole(ctx *gin.Context, roleID int32, userUuid uuid.UUID) error {
+	context := ctx.Request.Context()
+
+	user, err := rs.userRepo.FindByUUID(context, userUuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewError(utils.NotFoundError, "user not found")
+		}
+		return utils.WrapError(utils.InternalServerError, "failed to get user", err)
+	}
+
+	rows, err := rs.roleRepo.RemoveUserRole(context, user.UserID, roleID)
+	if err != nil {
+		return utils.WrapError(utils.InternalServerError, "failed to remove role", err)
+	}
+
+	if rows == 0 {
+		return utils.NewError(utils.NotFoundError, "user does not have this role")
+	}
+
+	rs.clearUserPermissionCache(user.UserUuid)
+
+	return nil
+}
+
+func (rs *roleService) validatePermissions(ctx *gin.Context, permissions []string) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	allPermissions, err := rs.GetAllPermissions(ctx)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool, len(allPermissions))
+	for _, p := range allPermissions {
+		known[p.PermissionCode] = true
+	}
+
+	for _, p := range permissions {
+		if !known[p] {
+			return utils.NewError(utils.BadRequestError, fmt.Sprintf("Unknown permission: %s", p))
+		}
+	}
+	return nil
+}
+
+func (rs *roleService) clearPermissionCache() {
+	if err := rs.cache.Clear("permissions:user:*"); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to clear permission cache")
+	}
+}
+
+func (rs *roleService) clearUserPermissionCache(userUuid uuid.UUID) {
+	if err := rs.cache.Clear(fmt.Sprintf("permissions:user:%s", userUuid)); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to clear permission cache")
+	}
+}
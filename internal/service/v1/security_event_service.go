@@ -0,0 +1,156 @@
+package v1service
+
+import (
+	"context"
+	"errors"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/loggers"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	SecurityEventLogin                = "login"
+	SecurityEventTokenRefresh         = "token_refresh"
+	SecurityEventLogout               = "logout"
+	SecurityEventPasswordResetRequest = "password_reset_request"
+	SecurityEventPasswordReset        = "password_reset"
+	SecurityEventAccountLocked        = "account_locked"
+	SecurityEventSignInReported       = "sign_in_reported"
+	SecurityEventEmailChangeRequest   = "email_change_request"
+	SecurityEventEmailChange          = "email_change"
+	SecurityEventAccountRestored      = "account_restored"
+	SecurityEventPasswordChange       = "password_change"
+	SecurityEventAccountClosed        = "account_closed"
+
+	SecurityOutcomeSuccess = "success"
+	SecurityOutcomeFailure = "failure"
+)
+
+type SecurityEvent struct {
+	Type    string
+	Outcome string
+	Reason  string
+	UserID  *int32
+	Email   string
+}
+
+type SecurityEventFilter struct {
+	UserUUID  *uuid.UUID
+	EventType string
+	Outcome   string
+	IPAddress string
+	From      *time.Time
+	To        *time.Time
+	Page      int32
+	Limit     int32
+}
+
+// SECURITY_EVENT_RETENTION_DAYS set to 0 keeps events forever
+func SecurityEventRetention() time.Duration {
+	days := utils.GetIntEnv("SECURITY_EVENT_RETENTION_DAYS", 90)
+	if days <= 0 {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// recordSecurityEvent never fails the request, a lost event is only logged
+func recordSecurityEvent(ctx *gin.Context, repo repository.SecurityEventRepository, event SecurityEvent) {
+	if repo == nil {
+		return
+	}
+
+	if err := repo.Create(ctx.Request.Context(), sqlc.CreateSecurityEventParams{
+		SecurityEventUserID:    event.UserID,
+		SecurityEventEmail:     event.Email,
+		SecurityEventType:      event.Type,
+		SecurityEventOutcome:   event.Outcome,
+		SecurityEventReason:    event.Reason,
+		SecurityEventIpAddress: ctx.ClientIP(),
+		SecurityEventUserAgent: ctx.Request.UserAgent(),
+		SecurityEventTraceID:   loggers.GetTraceID(ctx.Request.Context()),
+	}); err != nil {
+		loggers.Log.Error().Err(err).Str("event", event.Type).Msg("Failed to write security event")
+	}
+}
+
+// PruneSecurityEvents removes events older than the retention, it is run by the worker
+func PruneSecurityEvents(ctx context.Context, repo repository.SecurityEventRepository, retention time.Duration) (int64, error) {
+	if retention <= 0 {
+		return 0, nil
+	}
+	return repo.DeleteBefore(ctx, time.Now().Add(-retention))
+}
+
+type securityEventService struct {
+	securityEventRepo repository.SecurityEventRepository
+	userRepo          repository.UserRepository
+}
+
+func NewSecurityEventService(securityEventRepo repository.SecurityEventRepository, userRepo repository.UserRepository) SecurityEventService {
+	return &securityEventService{
+		securityEventRepo: securityEventRepo,
+		userRepo:          userRepo,
+	}
+}
+
+func (ses *securityEventService) GetSecurityEvents(ctx *gin.Context, filter SecurityEventFilter) ([]sqlc.SecurityEvent, int32, error) {
+	context := ctx.Request.Context()
+
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = int32(utils.GetIntEnv("LIMIT_ITEM_ON_PER_PAGE", 10))
+	}
+
+	params := sqlc.ListSecurityEventsParams{
+		Limit:  filter.Limit,
+		Offset: (filter.Page - 1) * filter.Limit,
+	}
+
+	if filter.UserUUID != nil {
+		user, err := ses.userRepo.FindByUUID(context, *filter.UserUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, 0, utils.NewError(utils.NotFoundError, "user not found")
+			}
+			return nil, 0, utils.WrapError(utils.InternalServerError, "failed to get user", err)
+		}
+		params.UserID = &user.UserID
+	}
+	if filter.EventType != "" {
+		params.EventType = &filter.EventType
+	}
+	if filter.Outcome != "" {
+		params.Outcome = &filter.Outcome
+	}
+	if filter.IPAddress != "" {
+		params.IpAddress = &filter.IPAddress
+	}
+	if filter.From != nil {
+		params.CreatedFrom = pgtype.Timestamptz{Time: *filter.From, Valid: true}
+	}
+	if filter.To != nil {
+		params.CreatedTo = pgtype.Timestamptz{Time: *filter.To, Valid: true}
+	}
+
+	events, err := ses.securityEventRepo.GetAll(context, params)
+	if err != nil {
+		return nil, 0, utils.WrapError(utils.InternalServerError, "failed to get security events", err)
+	}
+
+	total, err := ses.securityEventRepo.Count(context, params)
+	if err != nil {
+		return nil, 0, utils.WrapError(utils.InternalServerError, "failed to count security events", err)
+	}
+
+	return events, int32(total), nil
+}
@@ -0,0 +1,62 @@
+package v1service
+
+import (
+	"errors"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type sessionService struct {
+	userRepo     repository.UserRepository
+	tokenService auth.TokenService
+}
+
+func NewSessionService(userRepo repository.UserRepository, tokenService auth.TokenService) SessionService {
+	return &sessionService{
+		userRepo:     userRepo,
+		tokenService: tokenService,
+	}
+}
+
+func (ss *sessionService) GetSessions(ctx *gin.Context, userUuid uuid.UUID) ([]auth.Session, error) {
+	if err := ss.ensureUserExists(ctx, userUuid); err != nil {
+		return nil, err
+	}
+
+	sessions, err := ss.tokenService.ListSessions(userUuid.String())
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (ss *sessionService) RevokeSession(ctx *gin.Context, userUuid uuid.UUID, sessionID string) error {
+	if err := ss.ensureUserExists(ctx, userUuid); err != nil {
+		return err
+	}
+
+	return ss.tokenService.RevokeSession(userUuid.String(), sessionID)
+}
+
+func (ss *sessionService) RevokeAllSessions(ctx *gin.Context, userUuid uuid.UUID) error {
+	if err := ss.ensureUserExists(ctx, userUuid); err != nil {
+		return err
+	}
+
+	return ss.tokenService.RevokeAllSessions(userUuid.String())
+}
+
+func (ss *sessionService) ensureUserExists(ctx *gin.Context, userUuid uuid.UUID) error {
+	if _, err := ss.userRepo.FindByUUID(ctx.Request.Context(), userUuid); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewError(utils.NotFoundError, "user not found")
+		}
+		return utils.WrapError(utils.InternalServerError, "failed to get user", err)
+	}
+	return nil
+}
@@ -7,7 +7,9 @@ import (
 	"gin/user-management-api/internal/db/sqlc"
 	"gin/user-management-api/internal/repository"
 	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/auth"
 	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/hasher"
 	"gin/user-management-api/pkg/loggers"
 	"strings"
 	"time"
@@ -16,18 +18,26 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/redis/go-redis/v9"
-	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	UserStatusActive              int32 = 1
+	UserStatusInactive            int32 = 2
+	UserStatusBanned              int32 = 3
+	UserStatusPendingVerification int32 = 4
 )
 
 type userService struct {
-	repository repository.UserRepository
-	cache      cache.RedisCacheService
+	repository   repository.UserRepository
+	tokenService auth.TokenService
+	cache        cache.RedisCacheService
 }
 
-func NewUserService(repository repository.UserRepository, redisClient *redis.Client) UserService {
+func NewUserService(repository repository.UserRepository, tokenService auth.TokenService, redisClient *redis.Client) UserService {
 	return &userService{
-		repository: repository,
-		cache:      cache.NewRedisCacheService(redisClient),
+		repository:   repository,
+		tokenService: tokenService,
+		cache:        cache.NewRedisCacheService(redisClient),
 	}
 }
 
@@ -92,12 +102,12 @@ func (us *userService) CreateUser(ctx *gin.Context, intUserParams sqlc.CreateUse
 	context := ctx.Request.Context()
 
 	intUserParams.UserEmail = utils.NormalizeString(intUserParams.UserEmail)
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(intUserParams.UserPassword), bcrypt.DefaultCost)
+	hashedPassword, err := hasher.Hash(intUserParams.UserPassword)
 	if err != nil {
 		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "failed to hash password", err)
 	}
 
-	intUserParams.UserPassword = string(hashedPassword)
+	intUserParams.UserPassword = hashedPassword
 
 	user, err := us.repository.Create(context, intUserParams)
 	if err != nil {
@@ -106,6 +116,10 @@ func (us *userService) CreateUser(ctx *gin.Context, intUserParams sqlc.CreateUse
 			return sqlc.User{}, utils.NewError(utils.ConflictError, "Email already exitst")
 		}
 
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return sqlc.User{}, utils.NewError(utils.BadRequestError, "Level does not match any role")
+		}
+
 		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "failed to create a new user", err)
 	}
 
@@ -131,20 +145,38 @@ func (us *userService) GetUserByUUID(ctx *gin.Context, userUuid uuid.UUID) (sqlc
 func (us *userService) UpdateUser(ctx *gin.Context, userParams sqlc.UpdateUserByUuidParams) (sqlc.User, error) {
 	context := ctx.Request.Context()
 
-	if userParams.UserPassword != nil && *userParams.UserPassword != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*userParams.UserPassword), bcrypt.DefaultCost)
+	var current sqlc.User
+	changePassword := userParams.UserPassword != nil && *userParams.UserPassword != ""
+	if changePassword || userParams.UserStatus != nil {
+		user, err := us.GetUserByUUID(ctx, userParams.UserUuid)
+		if err != nil {
+			return sqlc.User{}, err
+		}
+		current = user
+	}
+
+	if changePassword {
+		if err := checkPasswordReuse(context, us.repository, current, *userParams.UserPassword); err != nil {
+			return sqlc.User{}, err
+		}
+
+		hashedPassword, err := hasher.Hash(*userParams.UserPassword)
 		if err != nil {
 			return sqlc.User{}, utils.WrapError(utils.InternalServerError, "failed to hash password", err)
 		}
-		hashed := string(hashedPassword)
-		userParams.UserPassword = &hashed
+		userParams.UserPassword = &hashedPassword
 	}
 
-	userUpdate, err := us.repository.Update(context, userParams)
+	userUpdate, err := us.repository.Update(context, userParams, passwordHistorySize())
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return sqlc.User{}, utils.WrapError(utils.NotFoundError, "user not found", err)
 		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return sqlc.User{}, utils.NewError(utils.BadRequestError, "Level does not match any role")
+		}
 		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "failed to update user", err)
 	}
 
@@ -152,9 +184,39 @@ func (us *userService) UpdateUser(ctx *gin.Context, userParams sqlc.UpdateUserBy
 		loggers.Log.Warn().Err(err).Msg("Failed to clear cache")
 	}
 
+	if userParams.UserLevel != nil {
+		if err := us.cache.Clear(fmt.Sprintf("permissions:user:%s", userUpdate.UserUuid)); err != nil {
+			loggers.Log.Warn().Err(err).Msg("Failed to clear permission cache")
+		}
+	}
+
+	if userParams.UserStatus != nil && userUpdate.UserStatus != current.UserStatus {
+		us.applyStatusChange(ctx, userUpdate, current.UserStatus)
+	}
+
 	return userUpdate, nil
 }
 
+// applyStatusChange signs the user out everywhere when the account stops being active
+func (us *userService) applyStatusChange(ctx *gin.Context, user sqlc.User, previousStatus int32) {
+	userUUID := user.UserUuid.String()
+	markUserStatusChanged(us.cache, userUUID, user.UserStatus)
+
+	if checkAccountStatus(user.UserStatus) != nil {
+		if err := us.tokenService.RevokeAllSessions(userUUID); err != nil {
+			loggers.Log.Error().Err(err).Str("user_uuid", userUUID).Msg("Failed to revoke sessions after status change")
+		}
+	}
+
+	loggers.Log.Warn().
+		Str("event", "user_status_changed").
+		Str("user_uuid", userUUID).
+		Int32("previous_status", previousStatus).
+		Int32("status", user.UserStatus).
+		Str("changed_by", ctx.GetString("user_uuid")).
+		Msg("User status changed")
+}
+
 func (us *userService) SoftDeleteUser(ctx *gin.Context, userUuid uuid.UUID) (sqlc.User, error) {
 	context := ctx.Request.Context()
 	user, err := us.repository.SoftDelete(context, userUuid)
@@ -164,6 +226,13 @@ func (us *userService) SoftDeleteUser(ctx *gin.Context, userUuid uuid.UUID) (sql
 		}
 		return sqlc.User{}, utils.NewError(utils.ConflictError, "failed to delete user")
 	}
+
+	userUUID := user.UserUuid.String()
+	markUserRemoved(us.cache, userUUID)
+	if err := us.tokenService.RevokeAllSessions(userUUID); err != nil {
+		loggers.Log.Error().Err(err).Str("user_uuid", userUUID).Msg("Failed to revoke sessions of deleted user")
+	}
+
 	if err := us.cache.Clear("users:*"); err != nil {
 		loggers.Log.Warn().Err(err).Msg("Failed to clear cache")
 	}
@@ -185,6 +254,27 @@ func (us *userService) RestoreUser(ctx *gin.Context, userUuid uuid.UUID) (sqlc.U
 	return user, nil
 }
 
+// UnlockUser lifts a login lockout before it expires and resets the failure counters of the account
+func (us *userService) UnlockUser(ctx *gin.Context, userUuid uuid.UUID) (sqlc.User, error) {
+	user, err := us.GetUserByUUID(ctx, userUuid)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+
+	email := utils.NormalizeString(user.UserEmail)
+	if err := us.cache.Delete(loginLockKey(email), loginFailAccountKey(email), loginDelayAccountKey(email)); err != nil {
+		return sqlc.User{}, utils.WrapError(utils.InternalServerError, "failed to unlock user", err)
+	}
+
+	loggers.Log.Info().
+		Str("event", "account_unlocked").
+		Str("email", email).
+		Str("unlocked_by", ctx.GetString("user_uuid")).
+		Msg("Account unlocked by administrator")
+
+	return user, nil
+}
+
 func (us *userService) DeleteUser(ctx *gin.Context, userUuid uuid.UUID) error {
 	context := ctx.Request.Context()
 	_, err := us.repository.Delete(context, userUuid)
@@ -0,0 +1,119 @@
+package v1service
+
+import (
+	"context"
+	"errors"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/auth"
+	"gin/user-management-api/pkg/cache"
+	"gin/user-management-api/pkg/loggers"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	UserStatusCacheTTL = 5 * time.Minute
+	// Only has to outlive the access tokens issued before the change
+	UserStatusChangeTTL = time.Hour
+)
+
+func userStatusKey(userUUID string) string {
+	return "user_status:" + userUUID
+}
+
+// userStatusChangedKey holds the time of the last change with sub-second precision
+func userStatusChangedKey(userUUID string) string {
+	return "user_status_changed_at:" + userUUID
+}
+
+func checkAccountStatus(status int32) error {
+	switch status {
+	case UserStatusInactive:
+		return utils.NewError(utils.AccountInactiveError, "Your account is inactive. Please contact support")
+	case UserStatusBanned:
+		return utils.NewError(utils.AccountBannedError, "Your account has been banned")
+	}
+	return nil
+}
+
+// markUserStatusChanged refreshes the cached status and makes tokens issued before now invalid
+func markUserStatusChanged(cacheService cache.RedisCacheService, userUUID string, status int32) {
+	if err := cacheService.Set(userStatusKey(userUUID), status, UserStatusCacheTTL); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to cache user status")
+	}
+	if err := cacheService.Set(userStatusChangedKey(userUUID), time.Now(), UserStatusChangeTTL); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to store user status change")
+	}
+}
+
+// markUserRemoved drops the cached status of a deleted account so the next request looks it up and is refused
+func markUserRemoved(cacheService cache.RedisCacheService, userUUID string) {
+	if err := cacheService.Delete(userStatusKey(userUUID)); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to delete cached user status")
+	}
+	if err := cacheService.Set(userStatusChangedKey(userUUID), time.Now(), UserStatusChangeTTL); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to store user status change")
+	}
+}
+
+type userStatusService struct {
+	userRepo     repository.UserRepository
+	cacheService cache.RedisCacheService
+}
+
+func NewUserStatusService(userRepo repository.UserRepository, cacheService cache.RedisCacheService) auth.UserStatusChecker {
+	return &userStatusService{
+		userRepo:     userRepo,
+		cacheService: cacheService,
+	}
+}
+
+// CheckUserStatus refuses tokens issued before the last status change and tokens of deleted accounts.
+// It fails closed, a request is refused when the status cannot be loaded.
+func (uss *userStatusService) CheckUserStatus(ctx context.Context, userUUID string, issuedAt time.Time) error {
+	// iat chỉ tính theo giây và bị làm tròn xuống, token cấp cùng giây với thay đổi cũng bị từ chối
+	var changedAt time.Time
+	err := uss.cacheService.Get(userStatusChangedKey(userUUID), &changedAt)
+	if err == nil && issuedAt.Before(changedAt) {
+		return utils.NewError(utils.UnauthorizedError, "Token revoked")
+	}
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return utils.WrapError(utils.InternalServerError, "Failed to check user status", err)
+	}
+
+	status, err := uss.getStatus(ctx, userUUID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return utils.NewError(utils.UnauthorizedError, "User not found")
+	}
+	if err != nil {
+		return utils.WrapError(utils.InternalServerError, "Failed to check user status", err)
+	}
+	return checkAccountStatus(status)
+}
+
+func (uss *userStatusService) getStatus(ctx context.Context, userUUID string) (int32, error) {
+	var status int32
+	if err := uss.cacheService.Get(userStatusKey(userUUID), &status); err == nil && status != 0 {
+		return status, nil
+	}
+
+	userUuid, err := uuid.Parse(userUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	var user sqlc.User
+	if user, err = uss.userRepo.FindByUUID(ctx, userUuid); err != nil {
+		return 0, err
+	}
+
+	if err := uss.cacheService.Set(userStatusKey(userUUID), user.UserStatus, UserStatusCacheTTL); err != nil {
+		loggers.Log.Warn().Err(err).Msg("Failed to cache user status")
+	}
+	return user.UserStatus, nil
+}
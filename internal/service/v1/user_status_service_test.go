@@ -0,0 +1,79 @@
+package v1service
+
+import (
+	"context"
+	"errors"
+	"gin/user-management-api/internal/db/sqlc"
+	"gin/user-management-api/internal/repository"
+	"gin/user-management-api/internal/utils"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// statusUserRepo answers FindByUUID with a fixed user or error
+type statusUserRepo struct {
+	repository.UserRepository
+	user sqlc.User
+	err  error
+}
+
+func (r *statusUserRepo) FindByUUID(ctx context.Context, userUuid uuid.UUID) (sqlc.User, error) {
+	return r.user, r.err
+}
+
+// brokenCache fails every read like an unreachable redis
+type brokenCache struct {
+	memoryCache
+}
+
+func (brokenCache) Get(key string, dest any) error { return errors.New("connection refused") }
+
+func TestCheckUserStatusChangeMarker(t *testing.T) {
+	userUUID := uuid.NewString()
+	cacheService := newMemoryCache()
+	uss := &userStatusService{userRepo: &statusUserRepo{user: sqlc.User{UserStatus: UserStatusActive}}, cacheService: cacheService}
+
+	changedAt := time.Now()
+	markUserStatusChanged(cacheService, userUUID, UserStatusActive)
+
+	// The middleware only gets whole seconds, a token from the same second as the change is refused
+	cases := []struct {
+		name     string
+		issuedAt time.Time
+		wantErr  bool
+	}{
+		{"issued before the change", changedAt.Add(-time.Minute), true},
+		{"issued in the same second", changedAt.Truncate(time.Second), true},
+		{"issued after the change", changedAt.Add(time.Second), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := uss.CheckUserStatus(context.Background(), userUUID, tc.issuedAt)
+			if tc.wantErr {
+				assertErrorCode(t, err, utils.UnauthorizedError)
+			} else if err != nil {
+				t.Errorf("error = %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckUserStatusFailsClosed(t *testing.T) {
+	userUUID := uuid.NewString()
+
+	uss := &userStatusService{userRepo: &statusUserRepo{err: errors.New("connection refused")}, cacheService: newMemoryCache()}
+	assertErrorCode(t, uss.CheckUserStatus(context.Background(), userUUID, time.Now()), utils.InternalServerError)
+
+	// Nor is an unreadable change marker taken as no change
+	uss = &userStatusService{userRepo: &statusUserRepo{user: sqlc.User{UserStatus: UserStatusActive}}, cacheService: &brokenCache{*newMemoryCache()}}
+	assertErrorCode(t, uss.CheckUserStatus(context.Background(), userUUID, time.Now()), utils.InternalServerError)
+
+	uss = &userStatusService{userRepo: &statusUserRepo{err: pgx.ErrNoRows}, cacheService: newMemoryCache()}
+	assertErrorCode(t, uss.CheckUserStatus(context.Background(), userUUID, time.Now()), utils.UnauthorizedError)
+
+	uss = &userStatusService{userRepo: &statusUserRepo{user: sqlc.User{UserStatus: UserStatusBanned}}, cacheService: newMemoryCache()}
+	assertErrorCode(t, uss.CheckUserStatus(context.Background(), userUUID, time.Now()), utils.AccountBannedError)
+}
@@ -20,9 +20,10 @@ func GetEnv(key, defaulValue string) string {
 	return defaulValue
 }
 
+// GetIntEnv returns the default when the variable is unset or not a number
 func GetIntEnv(key string, defaulValue int) int {
 	value := os.Getenv(key)
-	if value != "" {
+	if value == "" {
 		return defaulValue
 	}
 	intVal, err := strconv.Atoi(value)
@@ -53,13 +54,21 @@ func NewLoggerWithPath(fileName string, level string) *zerolog.Logger {
 }
 
 func GenerateRandomString(length int) (string, error) {
-	bytes := make([]byte, length)
-	if _, err := rand.Read(bytes); err != nil {
+	bytes, err := GenerateRandomBytes(length)
+	if err != nil {
 		return "", err
 	}
 	return base64.URLEncoding.EncodeToString(bytes), nil
 }
 
+func GenerateRandomBytes(length int) ([]byte, error) {
+	bytes := make([]byte, length)
+	if _, err := rand.Read(bytes); err != nil {
+		return nil, err
+	}
+	return bytes, nil
+}
+
 func MustGetWorkingDir() string {
 	dir, err := os.Getwd()
 	if err != nil {
@@ -16,6 +16,8 @@ const (
 	ForbiddenError       ErrorCode = "FORBIDDEN"
 	ConflictError        ErrorCode = "CONFLICT"
 	TooManyRequestsError ErrorCode = "TOO_MANY_REQUESTS"
+	AccountInactiveError ErrorCode = "ACCOUNT_INACTIVE"
+	AccountBannedError   ErrorCode = "ACCOUNT_BANNED"
 )
 
 type AppError struct {
@@ -111,7 +113,7 @@ func httpStatusFromErrorCode(code ErrorCode) int {
 		return http.StatusBadRequest
 	case UnauthorizedError:
 		return http.StatusUnauthorized
-	case ForbiddenError:
+	case ForbiddenError, AccountInactiveError, AccountBannedError:
 		return http.StatusForbidden
 	case ConflictError:
 		return http.StatusConflict
@@ -2,6 +2,7 @@ package validation
 
 import (
 	"gin/user-management-api/internal/utils"
+	"gin/user-management-api/pkg/breach"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -46,6 +47,10 @@ func RegisterCustomValidations(v *validator.Validate) error {
 		return hasLower && hasUpper && hasNumber && hasSpecial
 	})
 
+	v.RegisterValidation("password_not_breached", func(fl validator.FieldLevel) bool {
+		return !breach.Default.IsBreached(fl.Field().String())
+	})
+
 	var slugRegex = regexp.MustCompile(`^[a-z0-9]+(?:[-.][a-z0-9]+)*$`)
 	v.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
 		return slugRegex.MatchString(fl.Field().String())
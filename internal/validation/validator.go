@@ -73,6 +73,8 @@ func HandleValidationErrors(err error) gin.H {
 				errors[fieldPath] = fmt.Sprintf("%s là bắt buộc", fieldPath)
 			case "search":
 				errors[fieldPath] = fmt.Sprintf("%s chỉ được chứa chữ thường, in hoa, số và khoảng trắng", fieldPath)
+			case "ip":
+				errors[fieldPath] = fmt.Sprintf("%s phải là địa chỉ IP hợp lệ", fieldPath)
 			case "email":
 				errors[fieldPath] = fmt.Sprintf("%s phải đúng định dạng là email", fieldPath)
 			case "datetime":
@@ -81,6 +83,8 @@ func HandleValidationErrors(err error) gin.H {
 				errors[fieldPath] = fmt.Sprintf("%s này nằm trong danh sách bị cấm", fieldPath)
 			case "password_strong":
 				errors[fieldPath] = fmt.Sprintf("%s phải có ít nhất 8 kí tự bao gồm(chữ thường, chữ hoa, số, ký tự đặc biệt)", fieldPath)
+			case "password_not_breached":
+				errors[fieldPath] = fmt.Sprintf("%s nằm trong danh sách mật khẩu đã bị lộ, vui lòng chọn mật khẩu khác", fieldPath)
 			case "file_ext":
 				allowedValues := strings.Join(strings.Split(e.Param(), " "), ",")
 				errors[fieldPath] = fmt.Sprintf("%s chỉ cho phép những file có extension: %s", fieldPath, allowedValues)
@@ -2,17 +2,29 @@ package auth
 
 import (
 	"gin/user-management-api/internal/db/sqlc"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 
 type TokenService interface {
-	GenerateAccessToken(user sqlc.User) (string, error)
-	GenerateRefreshToken(user sqlc.User) (RefreshToken, error)
+	GenerateAccessToken(user sqlc.User, opts AccessTokenOptions) (AccessToken, error)
+	GenerateClientAccessToken(clientID string, scopes []string) (AccessToken, error)
+	GenerateRefreshToken(user sqlc.User, sessionID string) (RefreshToken, error)
 	ParseToken(tokenString string) (*jwt.Token, jwt.MapClaims, error)
+	JWKS() JWKSet
 	DecryptAccessTokenPayload(tokenString string) (*EncryptedPayload, error)
 	StoreRefreshToken(token RefreshToken) error
 	ValidateRefreshToken(token string) (RefreshToken, error)
 	RevokeRefreshToken(token string) error
+	RotateRefreshToken(current RefreshToken, user sqlc.User) (RefreshToken, error)
+	RevokeTokenFamily(familyID string) error
+	BlacklistAccessToken(jti string, expiresAt time.Time) error
+	SaveSession(session Session) error
+	GetSession(sessionID string) (Session, error)
+	ListSessions(userUUID string) ([]Session, error)
+	TrackAccessToken(sessionID string, token AccessToken) error
+	RevokeSession(userUUID, sessionID string) error
+	RevokeAllSessions(userUUID string) error
 }
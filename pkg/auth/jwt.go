@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"gin/user-management-api/internal/db/sqlc"
 	"gin/user-management-api/internal/utils"
 	"gin/user-management-api/pkg/cache"
@@ -15,6 +16,7 @@ import (
 
 type JWTService struct {
 	cache cache.RedisCacheService
+	keys *KeySet
 }
 
 // type Claim struct {
@@ -25,15 +27,49 @@ type EncryptedPayload struct {
 	UserUUID string `json:"user_uuid"`
 	Email string `json:"email"`
 	Role int32 `json:"role"`
+	Permissions []string `json:"permissions"`
+	SessionID string `json:"session_id"`
+	ClientID string `json:"client_id,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
+	Actor *Actor `json:"act,omitempty"`
+}
+
+// Actor is the administrator acting as the token subject during impersonation (RFC 8693 act claim)
+type Actor struct {
+	UserUUID string `json:"sub"`
+	Email string `json:"email"`
+}
+
+type AccessTokenOptions struct {
+	Permissions []string
+	SessionID string
+	ClientID string
+	Scopes []string
+	Actor *Actor
+	// TTL overrides AccessTokenTTL when set
+	TTL time.Duration
+}
+
+type AccessToken struct {
+	Token string `json:"token"`
+	JTI string `json:"jti"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type RefreshToken struct {
 	Token string `json:"token"`
 	UserUUID string `json:"user_uuid"`
+	SessionID string `json:"session_id"`
+	FamilyID string `json:"family_id"`
+	ParentToken string `json:"parent_token"`
+	ReplacedBy string `json:"replaced_by"`
 	ExpiresAt time.Time `json:"expires_at"`
 	Revoked bool `json:"revoked"`
 }
 
+// ErrRefreshTokenReused is returned when a refresh token that was already rotated is presented again
+var ErrRefreshTokenReused = errors.New("refresh token has already been used")
+
 var (
 	jwtSecret = []byte(utils.GetEnv("JWT_SECRET", "JWT-SECRET-HUY-DO-DANG-HOC-GOLANG-CUC-CHILL"))
 	jwtEncrypKey = []byte(utils.GetEnv("JWT_ENCRYPT_KEY", "12345678901234567890123456789027"))
@@ -44,45 +80,76 @@ const (
 	RefreshTokenTTL = 7 * 24 * time.Hour
 )
 
-func NewJWTService(cache cache.RedisCacheService) TokenService{
+func NewJWTService(cache cache.RedisCacheService, keys *KeySet) TokenService{
 	return &JWTService{
 		cache: cache,
+		keys: keys,
 	}
 }
 
 
-func (js *JWTService) GenerateAccessToken(user sqlc.User) (string, error) {
+func (js *JWTService) GenerateAccessToken(user sqlc.User, opts AccessTokenOptions) (AccessToken, error) {
 	payload := &EncryptedPayload{
 		UserUUID: user.UserUuid.String(),
 		Email: user.UserEmail,
 		Role: user.UserLevel,
+		Permissions: opts.Permissions,
+		SessionID: opts.SessionID,
+		ClientID: opts.ClientID,
+		Scopes: opts.Scopes,
+		Actor: opts.Actor,
 	}
 
+	return js.signPayload(payload, opts.TTL)
+}
+
+// GenerateClientAccessToken issues a token for the client itself (client_credentials grant), scopes become its permissions
+func (js *JWTService) GenerateClientAccessToken(clientID string, scopes []string) (AccessToken, error) {
+	return js.signPayload(&EncryptedPayload{
+		Permissions: scopes,
+		ClientID: clientID,
+		Scopes: scopes,
+	}, 0)
+}
+
+func (js *JWTService) signPayload(payload *EncryptedPayload, ttl time.Duration) (AccessToken, error) {
 	rawData, err := json.Marshal(payload)
 	if err != nil {
-		return "", nil
+		return AccessToken{}, err
 	}
 
 	encrypted, err := utils.EncryptAES(rawData, jwtEncrypKey)
 	if err != nil {
-		return "", nil
+		return AccessToken{}, err
 	}
 
+	if ttl <= 0 {
+		ttl = AccessTokenTTL
+	}
+
+	jti := uuid.NewString()
+	expiresAt := time.Now().Add(ttl)
 	claims := jwt.MapClaims{
 		"data": encrypted,
-		"jti": uuid.NewString(),
-		"exp": jwt.NewNumericDate(time.Now().Add(AccessTokenTTL)),
+		"jti": jti,
+		"exp": jwt.NewNumericDate(expiresAt),
 		"iat": jwt.NewNumericDate(time.Now()),
 		"iss": "HuyDo",
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	signed, err := js.keys.Sign(claims)
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	return AccessToken{
+		Token: signed,
+		JTI: jti,
+		ExpiresAt: expiresAt,
+	}, nil
 }
 
 func (js *JWTService) ParseToken(tokenString string) (*jwt.Token, jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (any, error){
-		return jwtSecret, nil
-	})
+	token, err := jwt.Parse(tokenString, js.keys.Keyfunc, jwt.WithValidMethods(js.keys.Algorithms()))
 
 	if err != nil || !token.Valid {
 		return nil, nil, utils.NewError(utils.UnauthorizedError, "Invalid token")
@@ -96,6 +163,10 @@ func (js *JWTService) ParseToken(tokenString string) (*jwt.Token, jwt.MapClaims,
 	return token, claims, nil
 }
 
+func (js *JWTService) JWKS() JWKSet {
+	return js.keys.JWKS()
+}
+
 func (js *JWTService) DecryptAccessTokenPayload(tokenString string) (*EncryptedPayload, error) {
 	_, claims, err := js.ParseToken(tokenString)
 	if err != nil {
@@ -120,7 +191,7 @@ func (js *JWTService) DecryptAccessTokenPayload(tokenString string) (*EncryptedP
 }
 
 
-func (js *JWTService) GenerateRefreshToken(user sqlc.User) (RefreshToken, error) {
+func (js *JWTService) GenerateRefreshToken(user sqlc.User, sessionID string) (RefreshToken, error) {
 	tokenBytes := make([]byte, 32)
 	if _, err := rand.Read(tokenBytes); err != nil {
 		return RefreshToken{}, err
@@ -132,6 +203,8 @@ func (js *JWTService) GenerateRefreshToken(user sqlc.User) (RefreshToken, error)
 	return RefreshToken{
 		Token: token,
 		UserUUID: user.UserUuid.String(),
+		SessionID: sessionID,
+		FamilyID: uuid.NewString(),
 		ExpiresAt: time.Now().Add(RefreshTokenTTL),
 		Revoked: false,
 	}, nil
@@ -140,7 +213,77 @@ func (js *JWTService) GenerateRefreshToken(user sqlc.User) (RefreshToken, error)
 
 func (js *JWTService) StoreRefreshToken(token RefreshToken) error {
 	cacheKey := "refresh_token:" + token.Token
-	return js.cache.Set(cacheKey, token, RefreshTokenTTL)
+	if err := js.cache.Set(cacheKey, token, RefreshTokenTTL); err != nil {
+		return err
+	}
+
+	if token.FamilyID == "" {
+		return nil
+	}
+	return js.cache.AddMember("refresh_family:"+token.FamilyID, token.Token, RefreshTokenTTL)
+}
+
+// RotateRefreshToken issues a child of the current token in the same family and marks the current one as replaced.
+// The rotation is claimed with SETNX first, so when the same token is refreshed concurrently only one caller gets a
+// child and the others get ErrRefreshTokenReused, even though all of them passed ValidateRefreshToken
+func (js *JWTService) RotateRefreshToken(current RefreshToken, user sqlc.User) (RefreshToken, error) {
+	child, err := js.GenerateRefreshToken(user, current.SessionID)
+	if err != nil {
+		return RefreshToken{}, err
+	}
+
+	if current.FamilyID != "" {
+		child.FamilyID = current.FamilyID
+	}
+	child.ParentToken = current.Token
+
+	claimKey := "refresh_token_rotated:" + current.Token
+	claimed, err := js.cache.SetNX(claimKey, child.Token, time.Until(current.ExpiresAt))
+	if err != nil {
+		return RefreshToken{}, err
+	}
+	if !claimed {
+		return RefreshToken{}, ErrRefreshTokenReused
+	}
+
+	if err := js.StoreRefreshToken(child); err != nil {
+		js.cache.Delete(claimKey)
+		return RefreshToken{}, err
+	}
+
+	current.Revoked = true
+	current.ReplacedBy = child.Token
+	if err := js.cache.Set("refresh_token:"+current.Token, current, time.Until(current.ExpiresAt)); err != nil {
+		return RefreshToken{}, err
+	}
+
+	return child, nil
+}
+
+func (js *JWTService) RevokeTokenFamily(familyID string) error {
+	if familyID == "" {
+		return nil
+	}
+
+	familyKey := "refresh_family:" + familyID
+	tokens, err := js.cache.GetMembers(familyKey)
+	if err != nil {
+		return utils.WrapError(utils.InternalServerError, "Cannot get refresh token family", err)
+	}
+
+	for _, token := range tokens {
+		var refreshToken RefreshToken
+		if err := js.cache.Get("refresh_token:"+token, &refreshToken); err != nil || refreshToken.Revoked {
+			continue
+		}
+
+		refreshToken.Revoked = true
+		if err := js.cache.Set("refresh_token:"+token, refreshToken, time.Until(refreshToken.ExpiresAt)); err != nil {
+			return utils.WrapError(utils.InternalServerError, "Cannot revoke refresh token", err)
+		}
+	}
+
+	return js.cache.Delete(familyKey)
 }
 
 
@@ -149,6 +292,9 @@ func (js *JWTService) ValidateRefreshToken(token string) (RefreshToken, error) {
 	var refreshToken RefreshToken
 
 	err := js.cache.Get(cacheKey, &refreshToken)
+	if err == nil && refreshToken.ReplacedBy != "" {
+		return refreshToken, ErrRefreshTokenReused
+	}
 	if err != nil || refreshToken.Revoked || refreshToken.ExpiresAt.Before(time.Now()) {
 		return RefreshToken{}, utils.WrapError(utils.InternalServerError, "Cannot get refresh token", err)
 	}
@@ -0,0 +1,266 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"gin/user-management-api/internal/utils"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Kid used for the shared secret when no asymmetric signing key is configured
+const hmacKeyID = "hs256"
+
+type verificationKey struct {
+	id     string
+	method jwt.SigningMethod
+	key    any
+	// notAfter retires the key, zero keeps it valid
+	notAfter time.Time
+}
+
+type signingKey struct {
+	verificationKey
+	private any
+}
+
+type KeySet struct {
+	signing      signingKey
+	verification map[string]verificationKey
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoadKeySetFromEnv signs with JWT_SIGNING_KEY_FILE when it is set and falls back to the HS256 secret otherwise.
+// Public keys listed in JWT_VERIFICATION_KEY_FILES stay valid for verification while keys are being rotated.
+// JWT_ACCEPT_HS256=true keeps verifying tokens signed with the secret for one AccessTokenTTL after moving to a key file,
+// so switching does not log everybody out.
+func LoadKeySetFromEnv() (*KeySet, error) {
+	signingFile := strings.TrimSpace(utils.GetEnv("JWT_SIGNING_KEY_FILE", ""))
+	if signingFile == "" {
+		return NewHMACKeySet(jwtSecret), nil
+	}
+
+	private, err := loadPEM(signingFile)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s does not contain a private key", signingFile)
+	}
+
+	key, err := newVerificationKey(signer.Public())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", signingFile, err)
+	}
+
+	ks := &KeySet{
+		signing:      signingKey{verificationKey: key, private: private},
+		verification: map[string]verificationKey{key.id: key},
+	}
+
+	if utils.GetEnv("JWT_ACCEPT_HS256", "") == "true" {
+		ks.verification[hmacKeyID] = verificationKey{id: hmacKeyID, method: jwt.SigningMethodHS256, key: jwtSecret, notAfter: time.Now().Add(AccessTokenTTL)}
+	}
+
+	for _, file := range strings.Split(utils.GetEnv("JWT_VERIFICATION_KEY_FILES", ""), ",") {
+		file = strings.TrimSpace(file)
+		if file == "" {
+			continue
+		}
+
+		parsed, err := loadPEM(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if signer, ok := parsed.(crypto.Signer); ok {
+			parsed = signer.Public()
+		}
+
+		key, err := newVerificationKey(parsed)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		ks.verification[key.id] = key
+	}
+
+	return ks, nil
+}
+
+func NewHMACKeySet(secret []byte) *KeySet {
+	key := verificationKey{id: hmacKeyID, method: jwt.SigningMethodHS256, key: secret}
+	return &KeySet{
+		signing:      signingKey{verificationKey: key, private: secret},
+		verification: map[string]verificationKey{hmacKeyID: key},
+	}
+}
+
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.method, claims)
+	token.Header["kid"] = ks.signing.id
+	return token.SignedString(ks.signing.private)
+}
+
+// Keyfunc selects the verification key by kid and rejects tokens whose alg does not match that key
+func (ks *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		// Token được ký trước khi có kid, chỉ chấp nhận khi vẫn dùng HS256
+		kid = hmacKeyID
+	}
+
+	key, ok := ks.verification[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q for key %q", token.Method.Alg(), kid)
+	}
+	if !key.notAfter.IsZero() && time.Now().After(key.notAfter) {
+		return nil, fmt.Errorf("key %q is retired", kid)
+	}
+	return key.key, nil
+}
+
+func (ks *KeySet) Algorithms() []string {
+	seen := make(map[string]bool)
+	algs := make([]string, 0, len(ks.verification))
+	for _, key := range ks.verification {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
+
+// JWKS only publishes asymmetric public keys
+func (ks *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range ks.verification {
+		jwk, ok := toJWK(key.key)
+		if !ok {
+			continue
+		}
+		jwk.Use = "sig"
+		jwk.Alg = key.method.Alg()
+		jwk.Kid = key.id
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+func loadPEM(path string) (any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s: unsupported PEM block %q", path, block.Type)
+	}
+}
+
+// newVerificationKey derives the algorithm from the key type and uses the RFC 7638 thumbprint as kid
+func newVerificationKey(public any) (verificationKey, error) {
+	var method jwt.SigningMethod
+	switch k := public.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < 2048 {
+			return verificationKey{}, fmt.Errorf("RSA keys must be at least 2048 bits")
+		}
+		method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return verificationKey{}, fmt.Errorf("only P-256 EC keys are supported")
+		}
+		method = jwt.SigningMethodES256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return verificationKey{}, fmt.Errorf("unsupported key type %T", public)
+	}
+
+	jwk, _ := toJWK(public)
+	return verificationKey{id: thumbprint(jwk), method: method, key: public}, nil
+}
+
+func toJWK(public any) (JWK, bool) {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	switch k := public.(type) {
+	case *rsa.PublicKey:
+		return JWK{Kty: "RSA", N: encode(k.N.Bytes()), E: encode(big.NewInt(int64(k.E)).Bytes())}, true
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return JWK{Kty: "EC", Crv: k.Curve.Params().Name, X: encode(k.X.FillBytes(make([]byte, size))), Y: encode(k.Y.FillBytes(make([]byte, size)))}, true
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Crv: "Ed25519", X: encode(k)}, true
+	default:
+		return JWK{}, false
+	}
+}
+
+func thumbprint(jwk JWK) string {
+	var members map[string]string
+	switch jwk.Kty {
+	case "RSA":
+		members = map[string]string{"e": jwk.E, "kty": jwk.Kty, "n": jwk.N}
+	case "EC":
+		members = map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X, "y": jwk.Y}
+	default:
+		members = map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X}
+	}
+
+	// encoding/json sorts map keys, which gives the canonical member order
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
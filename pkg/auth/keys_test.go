@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writeKeyFile(t *testing.T, key crypto.Signer) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "signing.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func loadKeySet(t *testing.T, key crypto.Signer) *KeySet {
+	t.Helper()
+	t.Setenv("JWT_SIGNING_KEY_FILE", writeKeyFile(t, key))
+	t.Setenv("JWT_VERIFICATION_KEY_FILES", "")
+	ks, err := LoadKeySetFromEnv()
+	if err != nil {
+		t.Fatalf("LoadKeySetFromEnv() error = %v", err)
+	}
+	return ks
+}
+
+func parse(ks *KeySet, token string) error {
+	_, err := jwt.Parse(token, ks.Keyfunc, jwt.WithValidMethods(ks.Algorithms()))
+	return err
+}
+
+func testClaims() jwt.MapClaims {
+	return jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Minute).Unix()}
+}
+
+func TestKeySetRoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		key  crypto.Signer
+		alg  string
+		kty  string
+	}{
+		{"RSA", rsaKey, "RS256", "RSA"},
+		{"EC", ecKey, "ES256", "EC"},
+		{"Ed25519", edKey, "EdDSA", "OKP"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ks := loadKeySet(t, tc.key)
+
+			signed, err := ks.Sign(testClaims())
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			if err := parse(ks, signed); err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+
+			jwks := ks.JWKS()
+			if len(jwks.Keys) != 1 {
+				t.Fatalf("JWKS has %d keys, want 1", len(jwks.Keys))
+			}
+			jwk := jwks.Keys[0]
+			if jwk.Kty != tc.kty || jwk.Alg != tc.alg || jwk.Use != "sig" {
+				t.Errorf("JWK = %+v", jwk)
+			}
+			if jwk.Kid != thumbprint(jwk) || jwk.Kid != ks.signing.id {
+				t.Errorf("kid %q is not the thumbprint of the published key", jwk.Kid)
+			}
+
+			// The secret is not accepted once a key file is used
+			legacy, _ := NewHMACKeySet(jwtSecret).Sign(testClaims())
+			if err := parse(ks, legacy); err == nil {
+				t.Errorf("HS256 token was accepted")
+			}
+		})
+	}
+}
+
+func TestThumbprint(t *testing.T) {
+	// RFC 7638 section 3.1
+	jwk := JWK{
+		Kty: "RSA",
+		N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E:   "AQAB",
+		Alg: "RS256",
+		Kid: "2011-04-29",
+	}
+	if got := thumbprint(jwk); got != "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs" {
+		t.Errorf("thumbprint() = %q", got)
+	}
+}
+
+func TestKeyfunc(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := loadKeySet(t, edKey)
+	hmac := NewHMACKeySet([]byte("secret"))
+
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	unknown, _ := loadKeySet(t, otherKey).Sign(testClaims())
+
+	// Right kid, but signed with HS256 and the public key bytes as secret
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	confused.Header["kid"] = ks.signing.id
+	mismatch, _ := confused.SignedString([]byte(edKey.Public().(ed25519.PublicKey)))
+
+	noKid := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	legacy, _ := noKid.SignedString([]byte("secret"))
+
+	cases := []struct {
+		name    string
+		ks      *KeySet
+		token   string
+		wantErr bool
+	}{
+		{"unknown kid", ks, unknown, true},
+		{"kid and alg mismatch", ks, mismatch, true},
+		{"no kid with a key file", ks, legacy, true},
+		{"no kid with the secret", hmac, legacy, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Straight to Keyfunc, jwt.WithValidMethods would hide the mismatch check
+			token, _, err := jwt.NewParser().ParseUnverified(tc.token, jwt.MapClaims{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tc.ks.Keyfunc(token); (err != nil) != tc.wantErr {
+				t.Errorf("Keyfunc() error = %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestAcceptHS256AfterSwitching(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, _ := NewHMACKeySet(jwtSecret).Sign(testClaims())
+
+	t.Setenv("JWT_ACCEPT_HS256", "true")
+	ks := loadKeySet(t, edKey)
+	if err := parse(ks, legacy); err != nil {
+		t.Fatalf("HS256 token error = %v", err)
+	}
+	if len(ks.JWKS().Keys) != 1 {
+		t.Errorf("the secret was published in the JWKS")
+	}
+
+	// New tokens are only signed with the key file
+	signed, _ := ks.Sign(testClaims())
+	if token, _, _ := jwt.NewParser().ParseUnverified(signed, jwt.MapClaims{}); token.Method.Alg() != "EdDSA" {
+		t.Errorf("signed with %s", token.Method.Alg())
+	}
+
+	// One TTL later the secret is retired
+	key := ks.verification[hmacKeyID]
+	key.notAfter = time.Now().Add(-time.Second)
+	ks.verification[hmacKeyID] = key
+	if err := parse(ks, legacy); err == nil {
+		t.Errorf("HS256 token was accepted after the transition")
+	}
+}
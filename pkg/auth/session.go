@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"gin/user-management-api/internal/utils"
+	"sort"
+	"time"
+)
+
+type AccessTokenRef struct {
+	JTI       string    `json:"jti"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type Session struct {
+	ID           string           `json:"id"`
+	UserUUID     string           `json:"user_uuid"`
+	DeviceName   string           `json:"device_name"`
+	IPAddress    string           `json:"ip_address"`
+	UserAgent    string           `json:"user_agent"`
+	ClientID     string           `json:"client_id,omitempty"`
+	Scopes       []string         `json:"scopes,omitempty"`
+	RefreshToken string           `json:"refresh_token"`
+	AccessTokens []AccessTokenRef `json:"access_tokens"`
+	CreatedAt    time.Time        `json:"created_at"`
+	LastUsedAt   time.Time        `json:"last_used_at"`
+	ExpiresAt    time.Time        `json:"expires_at"`
+}
+
+func sessionKey(sessionID string) string {
+	return "session:" + sessionID
+}
+
+func userSessionsKey(userUUID string) string {
+	return "user_sessions:" + userUUID
+}
+
+func (js *JWTService) BlacklistAccessToken(jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if jti == "" || ttl <= 0 {
+		return nil
+	}
+	return js.cache.Set("backlist:"+jti, "revoked", ttl)
+}
+
+func (js *JWTService) SaveSession(session Session) error {
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		return utils.NewError(utils.UnauthorizedError, "Session expired")
+	}
+
+	if err := js.cache.Set(sessionKey(session.ID), session, ttl); err != nil {
+		return err
+	}
+	return js.cache.AddMember(userSessionsKey(session.UserUUID), session.ID, RefreshTokenTTL)
+}
+
+func (js *JWTService) GetSession(sessionID string) (Session, error) {
+	var session Session
+	if err := js.cache.Get(sessionKey(sessionID), &session); err != nil || session.ID == "" {
+		return Session{}, utils.NewError(utils.NotFoundError, "Session not found")
+	}
+	return session, nil
+}
+
+// ListSessions drops ids of sessions that already expired from the user index
+func (js *JWTService) ListSessions(userUUID string) ([]Session, error) {
+	ids, err := js.cache.GetMembers(userSessionsKey(userUUID))
+	if err != nil {
+		return nil, utils.WrapError(utils.InternalServerError, "Cannot get user sessions", err)
+	}
+
+	sessions := make([]Session, 0, len(ids))
+	var stale []string
+	for _, id := range ids {
+		session, err := js.GetSession(id)
+		if err != nil {
+			stale = append(stale, id)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	if len(stale) > 0 {
+		js.cache.RemoveMember(userSessionsKey(userUUID), stale...)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+	return sessions, nil
+}
+
+func (js *JWTService) TrackAccessToken(sessionID string, token AccessToken) error {
+	session, err := js.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	refs := make([]AccessTokenRef, 0, len(session.AccessTokens)+1)
+	for _, ref := range session.AccessTokens {
+		if ref.ExpiresAt.After(now) {
+			refs = append(refs, ref)
+		}
+	}
+	session.AccessTokens = append(refs, AccessTokenRef{JTI: token.JTI, ExpiresAt: token.ExpiresAt})
+
+	return js.SaveSession(session)
+}
+
+func (js *JWTService) RevokeSession(userUUID, sessionID string) error {
+	session, err := js.GetSession(sessionID)
+	if err != nil || session.UserUUID != userUUID {
+		return utils.NewError(utils.NotFoundError, "Session not found")
+	}
+
+	if session.RefreshToken != "" {
+		js.RevokeRefreshToken(session.RefreshToken)
+	}
+
+	for _, ref := range session.AccessTokens {
+		if err := js.BlacklistAccessToken(ref.JTI, ref.ExpiresAt); err != nil {
+			return utils.WrapError(utils.InternalServerError, "Cannot revoke access token", err)
+		}
+	}
+
+	if err := js.cache.Delete(sessionKey(sessionID)); err != nil {
+		return utils.WrapError(utils.InternalServerError, "Cannot delete session", err)
+	}
+	return js.cache.RemoveMember(userSessionsKey(userUUID), sessionID)
+}
+
+func (js *JWTService) RevokeAllSessions(userUUID string) error {
+	sessions, err := js.ListSessions(userUUID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if err := js.RevokeSession(userUUID, session.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// UserStatusChecker is used by the auth middleware to reject tokens of users who were deactivated or banned
+type UserStatusChecker interface {
+	CheckUserStatus(ctx context.Context, userUUID string, issuedAt time.Time) error
+}
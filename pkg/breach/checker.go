@@ -0,0 +1,229 @@
+package breach
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const prefixLength = 5
+
+// Checker keeps SHA-1 hashes of breached passwords grouped by their 5 character prefix, like the HIBP range API
+type Checker struct {
+	mu      sync.RWMutex
+	path    string
+	version listVersion
+	buckets map[string]map[string]struct{}
+	count   int
+}
+
+// Default is used by the password validators, it accepts every password until a list is loaded
+var Default = NewChecker("")
+
+func NewChecker(path string) *Checker {
+	return &Checker{
+		path:    path,
+		buckets: make(map[string]map[string]struct{}),
+	}
+}
+
+func (c *Checker) Count() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.count
+}
+
+func (c *Checker) IsBreached(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	bucket, ok := c.buckets[hash[:prefixLength]]
+	if !ok {
+		return false
+	}
+	_, ok = bucket[hash[prefixLength:]]
+	return ok
+}
+
+// Load reads either a directory of range files named after their prefix (lines "SUFFIX:COUNT")
+// or a single file of full hashes (lines "HASH:COUNT"), then swaps the list in one step
+func (c *Checker) Load() error {
+	if c.path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return fmt.Errorf("stat breached password list: %w", err)
+	}
+
+	// Taken before reading, a change made while loading is picked up by the next Watch tick
+	version, err := versionOf(c.path)
+	if err != nil {
+		return err
+	}
+
+	buckets := make(map[string]map[string]struct{})
+	count := 0
+
+	if info.IsDir() {
+		files, err := os.ReadDir(c.path)
+		if err != nil {
+			return fmt.Errorf("read breached password directory: %w", err)
+		}
+
+		for _, file := range files {
+			prefix := strings.ToUpper(strings.TrimSuffix(file.Name(), filepath.Ext(file.Name())))
+			if file.IsDir() || !isHex(prefix, prefixLength) {
+				continue
+			}
+
+			n, err := readHashes(filepath.Join(c.path, file.Name()), prefix, buckets)
+			if err != nil {
+				return err
+			}
+			count += n
+		}
+	} else {
+		if count, err = readHashes(c.path, "", buckets); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	c.buckets = buckets
+	c.count = count
+	c.version = version
+	c.mu.Unlock()
+	return nil
+}
+
+// listVersion changes whenever the list is edited, for a directory it covers the range files inside it,
+// whose edits do not touch the modification time of the directory itself
+type listVersion struct {
+	modTime int64
+	size    int64
+	files   int
+}
+
+func versionOf(path string) (listVersion, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return listVersion{}, fmt.Errorf("stat breached password list: %w", err)
+	}
+	if !info.IsDir() {
+		return listVersion{modTime: info.ModTime().UnixNano(), size: info.Size(), files: 1}, nil
+	}
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		return listVersion{}, fmt.Errorf("read breached password directory: %w", err)
+	}
+
+	// Added or removed files change the directory modification time and the file count
+	version := listVersion{modTime: info.ModTime().UnixNano()}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		fileInfo, err := file.Info()
+		if err != nil {
+			return listVersion{}, fmt.Errorf("stat breached password list: %w", err)
+		}
+		version.modTime = max(version.modTime, fileInfo.ModTime().UnixNano())
+		version.size += fileInfo.Size()
+		version.files++
+	}
+	return version, nil
+}
+
+// Watch reloads the list whenever its version changes so it can be refreshed without a restart
+func (c *Checker) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	if c.path == "" || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			version, err := versionOf(c.path)
+			if err != nil {
+				onError(err)
+				continue
+			}
+
+			c.mu.RLock()
+			changed := version != c.version
+			c.mu.RUnlock()
+
+			if changed {
+				if err := c.Load(); err != nil {
+					onError(err)
+				}
+			}
+		}
+	}
+}
+
+func readHashes(path, prefix string, buckets map[string]map[string]struct{}) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("open breached password list: %w", err)
+	}
+	defer file.Close()
+
+	count := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(line, ":")
+		hash = strings.ToUpper(prefix + strings.TrimSpace(hash))
+		if !isHex(hash, sha1.Size*2) {
+			continue
+		}
+
+		bucket, ok := buckets[hash[:prefixLength]]
+		if !ok {
+			bucket = make(map[string]struct{})
+			buckets[hash[:prefixLength]] = bucket
+		}
+		bucket[hash[prefixLength:]] = struct{}{}
+		count++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("read %s: %w", path, err)
+	}
+	return count, nil
+}
+
+func isHex(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, r := range value {
+		if !strings.ContainsRune("0123456789ABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package breach
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// rangeLine is a line of the range file named after the first 5 characters of the hash
+func rangeLine(password string) (prefix, line string) {
+	hash := sha1Hex(password)
+	return hash[:prefixLength], hash[prefixLength:] + ":42\n"
+}
+
+func TestLoadRangeDirectory(t *testing.T) {
+	dir := t.TempDir()
+	prefix, line := rangeLine("password")
+	writeFile(t, filepath.Join(dir, prefix+".txt"), line)
+	// Lower case names and suffixes are accepted, files that are not named after a prefix are ignored
+	other, otherLine := rangeLine("123456")
+	writeFile(t, filepath.Join(dir, strings.ToLower(other)), strings.ToLower(otherLine))
+	writeFile(t, filepath.Join(dir, "README.md"), sha1Hex("letmein")+"\n")
+
+	c := NewChecker(dir)
+	if err := c.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if c.Count() != 2 {
+		t.Errorf("Count() = %d, want 2", c.Count())
+	}
+	for password, want := range map[string]bool{"password": true, "123456": true, "letmein": false, "correct horse": false} {
+		if got := c.IsBreached(password); got != want {
+			t.Errorf("IsBreached(%q) = %v, want %v", password, got, want)
+		}
+	}
+}
+
+func TestLoadSingleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	writeFile(t, path, strings.Join([]string{
+		"# breached passwords",
+		"",
+		sha1Hex("password") + ":3861493",
+		"   " + strings.ToLower(sha1Hex("123456")) + "   ",
+		"# " + sha1Hex("letmein"),
+		"not a hash:1",
+	}, "\n"))
+
+	c := NewChecker(path)
+	if err := c.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if c.Count() != 2 {
+		t.Errorf("Count() = %d, want 2", c.Count())
+	}
+	for password, want := range map[string]bool{"password": true, "123456": true, "letmein": false} {
+		if got := c.IsBreached(password); got != want {
+			t.Errorf("IsBreached(%q) = %v, want %v", password, got, want)
+		}
+	}
+}
+
+func TestLoadWithoutPath(t *testing.T) {
+	c := NewChecker("")
+	if err := c.Load(); err != nil || c.IsBreached("password") {
+		t.Errorf("empty checker: Load() = %v, IsBreached = %v", err, c.IsBreached("password"))
+	}
+
+	if err := NewChecker(filepath.Join(t.TempDir(), "missing")).Load(); err == nil {
+		t.Errorf("missing list was accepted")
+	}
+}
+
+func TestWatchPicksUpEditedRangeFile(t *testing.T) {
+	dir := t.TempDir()
+	prefix, line := rangeLine("password")
+	file := filepath.Join(dir, prefix+".txt")
+	writeFile(t, file, line)
+
+	c := NewChecker(dir)
+	if err := c.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	before, err := versionOf(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another suffix in the same range file, editing it does not touch the directory itself
+	writeFile(t, file, line+strings.Repeat("0", sha1.Size*2-prefixLength)+":1\n")
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := versionOf(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after == before {
+		t.Fatalf("versionOf() did not change after editing a range file")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Watch(ctx, 10*time.Millisecond, func(err error) { t.Errorf("Watch() error = %v", err) })
+
+	deadline := time.Now().Add(2 * time.Second)
+	for c.Count() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Count() = %d after editing the range file, want 2", c.Count())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !c.IsBreached("password") {
+		t.Errorf("reload lost the existing hash")
+	}
+}
@@ -4,7 +4,17 @@ import "time"
 
 type RedisCacheService interface {
 	Get(key string, dest any) error
+	// GetDel reads and removes the key in one step, only one caller ever gets the value
+	GetDel(key string, dest any) error
 	Set(key string, value any, ttl time.Duration) error
+	// SetNX only stores the value when the key does not exist yet and reports whether it did, only one caller wins
+	SetNX(key string, value any, ttl time.Duration) (bool, error)
 	Clear(pattern string) error
+	Delete(keys ...string) error
 	Exited(key string) (bool, error)
+	AddMember(key string, member string, ttl time.Duration) error
+	GetMembers(key string) ([]string, error)
+	RemoveMember(key string, members ...string) error
+	Increment(key string, ttl time.Duration) (int64, error)
+	TTL(key string) (time.Duration, error)
 }
@@ -34,6 +34,15 @@ func (cs *redisCacheService) Get(key string, dest any) error {
 	return json.Unmarshal([]byte(data),dest)
 }
 
+func (cs *redisCacheService) GetDel(key string, dest any) error {
+	data, err := cs.rdb.GetDel(cs.ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(data), dest)
+}
+
 func (cs *redisCacheService) Set(key string, value any, ttl time.Duration) error {
 	data, err := json.Marshal(value)
 	if err != nil {
@@ -42,6 +51,14 @@ func (cs *redisCacheService) Set(key string, value any, ttl time.Duration) error
 	return cs.rdb.Set(cs.ctx, key, data, ttl).Err()
 }
 
+func (cs *redisCacheService) SetNX(key string, value any, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	return cs.rdb.SetNX(cs.ctx, key, data, ttl).Result()
+}
+
 func (cs *redisCacheService) Clear(pattern string) error {
 	cusor := uint64(0)
 
@@ -64,6 +81,13 @@ func (cs *redisCacheService) Clear(pattern string) error {
 	return nil
 }
 
+func (cs *redisCacheService) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return cs.rdb.Del(cs.ctx, keys...).Err()
+}
+
 func (cs *redisCacheService) Exited(key string) (bool, error) {
 	count, err := cs.rdb.Exists(cs.ctx, key).Result()
 	if err != nil {
@@ -71,3 +95,48 @@ func (cs *redisCacheService) Exited(key string) (bool, error) {
 	}
 	return count > 0, nil
 }
+
+func (cs *redisCacheService) AddMember(key string, member string, ttl time.Duration) error {
+	pipe := cs.rdb.TxPipeline()
+	pipe.SAdd(cs.ctx, key, member)
+	pipe.Expire(cs.ctx, key, ttl)
+	_, err := pipe.Exec(cs.ctx)
+	return err
+}
+
+func (cs *redisCacheService) GetMembers(key string) ([]string, error) {
+	return cs.rdb.SMembers(cs.ctx, key).Result()
+}
+
+func (cs *redisCacheService) RemoveMember(key string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+	values := make([]any, len(members))
+	for i, m := range members {
+		values[i] = m
+	}
+	return cs.rdb.SRem(cs.ctx, key, values...).Err()
+}
+
+// Increment only sets the TTL when the counter is created, so the window is not extended by later hits
+func (cs *redisCacheService) Increment(key string, ttl time.Duration) (int64, error) {
+	pipe := cs.rdb.TxPipeline()
+	incr := pipe.Incr(cs.ctx, key)
+	pipe.ExpireNX(cs.ctx, key, ttl)
+	if _, err := pipe.Exec(cs.ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (cs *redisCacheService) TTL(key string) (time.Duration, error) {
+	ttl, err := cs.rdb.TTL(cs.ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
@@ -0,0 +1,131 @@
+// Package directorytest provides an in-process LDAP directory for tests of code built on directory.Client
+package directorytest
+
+import (
+	"errors"
+	"fmt"
+	"gin/user-management-api/pkg/directory"
+	"strings"
+	"sync"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+type account struct {
+	entry    *ldap.Entry
+	password string
+}
+
+// Directory answers binds and searches from memory, a search matches an entry when the request filter
+// equals the configured user filter formatted with the escaped value of the entry's email attribute,
+// ignoring case like the mail attribute of real directories
+type Directory struct {
+	mu       sync.Mutex
+	cfg      directory.Config
+	accounts map[string]account
+	filters  []string
+	binds    []string
+}
+
+func New(cfg directory.Config) *Directory {
+	return &Directory{
+		cfg:      cfg,
+		accounts: make(map[string]account),
+	}
+}
+
+// AddUser stores an entry that can bind with password
+func (d *Directory) AddUser(dn, password string, attributes map[string][]string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.accounts[dn] = account{
+		entry:    ldap.NewEntry(dn, attributes),
+		password: password,
+	}
+}
+
+// Dial satisfies directory.Dialer
+func (d *Directory) Dial(cfg directory.Config) (directory.Conn, error) {
+	return &conn{directory: d}, nil
+}
+
+// Client returns a directory.Client that talks to this directory
+func (d *Directory) Client() directory.Client {
+	return directory.NewLDAPClientWithDialer(d.cfg, d.Dial)
+}
+
+// Filters returns the search filters received so far
+func (d *Directory) Filters() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.filters...)
+}
+
+// Binds returns the DNs that binds were attempted with
+func (d *Directory) Binds() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.binds...)
+}
+
+type conn struct {
+	directory *Directory
+	closed    bool
+}
+
+func (c *conn) Bind(username, password string) error {
+	d := c.directory
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if c.closed {
+		return errors.New("directorytest: connection is closed")
+	}
+	d.binds = append(d.binds, username)
+
+	if username == d.cfg.BindDN && password == d.cfg.BindPassword {
+		return nil
+	}
+	if acc, ok := d.accounts[username]; ok && password != "" && acc.password == password {
+		return nil
+	}
+	return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+}
+
+func (c *conn) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	d := c.directory
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if c.closed {
+		return nil, errors.New("directorytest: connection is closed")
+	}
+	d.filters = append(d.filters, req.Filter)
+
+	result := &ldap.SearchResult{}
+	for dn, acc := range d.accounts {
+		if !strings.HasSuffix(strings.ToLower(dn), strings.ToLower(req.BaseDN)) {
+			continue
+		}
+		for _, email := range acc.entry.GetAttributeValues(d.cfg.EmailAttribute) {
+			if strings.EqualFold(req.Filter, fmt.Sprintf(d.cfg.UserFilter, ldap.EscapeFilter(email))) {
+				result.Entries = append(result.Entries, acc.entry)
+				break
+			}
+		}
+	}
+
+	if req.SizeLimit > 0 && len(result.Entries) > req.SizeLimit {
+		return nil, ldap.NewError(ldap.LDAPResultSizeLimitExceeded, errors.New("size limit exceeded"))
+	}
+	return result, nil
+}
+
+func (c *conn) Close() error {
+	c.directory.mu.Lock()
+	defer c.directory.mu.Unlock()
+
+	c.closed = true
+	return nil
+}
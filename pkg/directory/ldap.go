@@ -0,0 +1,195 @@
+package directory
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/utils"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+var (
+	ErrUserNotFound       = errors.New("directory: user not found")
+	ErrInvalidCredentials = errors.New("directory: invalid credentials")
+)
+
+type Config struct {
+	URL string
+	// Service account used to search for the user, an anonymous search is made when empty
+	BindDN       string
+	BindPassword string
+	BaseDN       string
+	// UserFilter receives the escaped login email through %s
+	UserFilter     string
+	EmailAttribute string
+	NameAttribute  string
+	GroupAttribute string
+	StartTLS       bool
+	TLSConfig      *tls.Config
+	Timeout        time.Duration
+}
+
+// Entry is the directory user a login resolved to
+type Entry struct {
+	DN     string
+	Email  string
+	Name   string
+	Groups []string
+}
+
+type Client interface {
+	// Authenticate searches the user by email and binds as that user with the password
+	Authenticate(email, password string) (Entry, error)
+}
+
+// Conn is the part of *ldap.Conn the client uses, tests can dial an in-process stand-in instead
+type Conn interface {
+	Bind(username, password string) error
+	Search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error)
+	Close() error
+}
+
+type Dialer func(cfg Config) (Conn, error)
+
+// ConfigFromEnv reads the LDAP_* variables, ok is false when LDAP_URL is not set and the directory is disabled
+func ConfigFromEnv() (Config, bool, error) {
+	cfg := Config{
+		URL:            utils.GetEnv("LDAP_URL", ""),
+		BindDN:         utils.GetEnv("LDAP_BIND_DN", ""),
+		BindPassword:   utils.GetEnv("LDAP_BIND_PASSWORD", ""),
+		BaseDN:         utils.GetEnv("LDAP_BASE_DN", ""),
+		UserFilter:     utils.GetEnv("LDAP_USER_FILTER", "(&(objectClass=person)(mail=%s))"),
+		EmailAttribute: utils.GetEnv("LDAP_EMAIL_ATTRIBUTE", "mail"),
+		NameAttribute:  utils.GetEnv("LDAP_NAME_ATTRIBUTE", "cn"),
+		GroupAttribute: utils.GetEnv("LDAP_GROUP_ATTRIBUTE", "memberOf"),
+		StartTLS:       utils.GetEnv("LDAP_START_TLS", "false") == "true",
+		Timeout:        time.Duration(utils.GetIntEnv("LDAP_TIMEOUT_SECONDS", 5)) * time.Second,
+	}
+	if cfg.URL == "" {
+		return Config{}, false, nil
+	}
+
+	if cfg.BaseDN == "" {
+		return Config{}, false, errors.New("LDAP_BASE_DN is required when LDAP_URL is set")
+	}
+
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return Config{}, false, fmt.Errorf("invalid LDAP_URL: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         u.Hostname(),
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: utils.GetEnv("LDAP_TLS_INSECURE_SKIP_VERIFY", "false") == "true",
+	}
+	if caFile := utils.GetEnv("LDAP_TLS_CA_FILE", ""); caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return Config{}, false, fmt.Errorf("failed to read LDAP_TLS_CA_FILE: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return Config{}, false, errors.New("LDAP_TLS_CA_FILE does not contain any certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	cfg.TLSConfig = tlsConfig
+
+	return cfg, true, nil
+}
+
+// Dial connects to cfg.URL, ldaps:// URLs use TLS from the start and StartTLS upgrades plain ldap:// connections
+func Dial(cfg Config) (Conn, error) {
+	conn, err := ldap.DialURL(cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: cfg.Timeout}),
+		ldap.DialWithTLSConfig(cfg.TLSConfig),
+	)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(cfg.Timeout)
+
+	if cfg.StartTLS {
+		if err := conn.StartTLS(cfg.TLSConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+type ldapClient struct {
+	cfg  Config
+	dial Dialer
+}
+
+func NewLDAPClient(cfg Config) Client {
+	return NewLDAPClientWithDialer(cfg, Dial)
+}
+
+func NewLDAPClientWithDialer(cfg Config, dial Dialer) Client {
+	return &ldapClient{
+		cfg:  cfg,
+		dial: dial,
+	}
+}
+
+func (lc *ldapClient) Authenticate(email, password string) (Entry, error) {
+	// An empty password would be an unauthenticated bind, which most servers accept
+	if password == "" {
+		return Entry{}, ErrInvalidCredentials
+	}
+
+	conn, err := lc.dial(lc.cfg)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to connect to directory: %w", err)
+	}
+	defer conn.Close()
+
+	if lc.cfg.BindDN != "" {
+		if err := conn.Bind(lc.cfg.BindDN, lc.cfg.BindPassword); err != nil {
+			return Entry{}, fmt.Errorf("failed to bind service account: %w", err)
+		}
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		lc.cfg.BaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, int(lc.cfg.Timeout.Seconds()), false,
+		fmt.Sprintf(lc.cfg.UserFilter, ldap.EscapeFilter(email)),
+		[]string{lc.cfg.EmailAttribute, lc.cfg.NameAttribute, lc.cfg.GroupAttribute},
+		nil,
+	))
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to search directory: %w", err)
+	}
+
+	switch len(result.Entries) {
+	case 0:
+		return Entry{}, ErrUserNotFound
+	case 1:
+	default:
+		return Entry{}, fmt.Errorf("directory returned %d entries for %s", len(result.Entries), email)
+	}
+
+	entry := result.Entries[0]
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return Entry{}, ErrInvalidCredentials
+		}
+		return Entry{}, fmt.Errorf("failed to bind user: %w", err)
+	}
+
+	return Entry{
+		DN:     entry.DN,
+		Email:  entry.GetAttributeValue(lc.cfg.EmailAttribute),
+		Name:   entry.GetAttributeValue(lc.cfg.NameAttribute),
+		Groups: entry.GetAttributeValues(lc.cfg.GroupAttribute),
+	}, nil
+}
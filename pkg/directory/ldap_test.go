@@ -0,0 +1,132 @@
+package directory_test
+
+import (
+	"errors"
+	"gin/user-management-api/pkg/directory"
+	"gin/user-management-api/pkg/directory/directorytest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func testConfig() directory.Config {
+	return directory.Config{
+		URL:            "ldap://directory.test",
+		BindDN:         "cn=service,dc=example,dc=org",
+		BindPassword:   "service-secret",
+		BaseDN:         "ou=people,dc=example,dc=org",
+		UserFilter:     "(&(objectClass=person)(mail=%s))",
+		EmailAttribute: "mail",
+		NameAttribute:  "cn",
+		GroupAttribute: "memberOf",
+		Timeout:        time.Second,
+	}
+}
+
+func newDirectory() *directorytest.Directory {
+	dir := directorytest.New(testConfig())
+	dir.AddUser("uid=alice,ou=people,dc=example,dc=org", "alice-secret", map[string][]string{
+		"mail":     {"alice@example.org"},
+		"cn":       {"Alice Example"},
+		"memberOf": {"cn=admins,ou=groups,dc=example,dc=org", "cn=staff,ou=groups,dc=example,dc=org"},
+	})
+	return dir
+}
+
+func TestAuthenticate(t *testing.T) {
+	dir := newDirectory()
+
+	entry, err := dir.Client().Authenticate("alice@example.org", "alice-secret")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+
+	if entry.DN != "uid=alice,ou=people,dc=example,dc=org" {
+		t.Errorf("DN = %q", entry.DN)
+	}
+	if entry.Email != "alice@example.org" || entry.Name != "Alice Example" {
+		t.Errorf("Email, Name = %q, %q", entry.Email, entry.Name)
+	}
+	if len(entry.Groups) != 2 || entry.Groups[0] != "cn=admins,ou=groups,dc=example,dc=org" {
+		t.Errorf("Groups = %v", entry.Groups)
+	}
+
+	binds := dir.Binds()
+	if !slices.Equal(binds, []string{"cn=service,dc=example,dc=org", "uid=alice,ou=people,dc=example,dc=org"}) {
+		t.Errorf("binds = %v, want the service account then the user", binds)
+	}
+}
+
+func TestAuthenticateInvalidCredentials(t *testing.T) {
+	dir := newDirectory()
+
+	if _, err := dir.Client().Authenticate("alice@example.org", "wrong"); !errors.Is(err, directory.ErrInvalidCredentials) {
+		t.Errorf("wrong password error = %v, want ErrInvalidCredentials", err)
+	}
+
+	// An empty password would be an unauthenticated bind, the directory must not even be asked
+	before := len(dir.Binds())
+	if _, err := dir.Client().Authenticate("alice@example.org", ""); !errors.Is(err, directory.ErrInvalidCredentials) {
+		t.Errorf("empty password error = %v, want ErrInvalidCredentials", err)
+	}
+	if len(dir.Binds()) != before {
+		t.Errorf("empty password reached the directory")
+	}
+}
+
+func TestAuthenticateUnknownUser(t *testing.T) {
+	dir := newDirectory()
+
+	if _, err := dir.Client().Authenticate("bob@example.org", "secret"); !errors.Is(err, directory.ErrUserNotFound) {
+		t.Errorf("error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestAuthenticateAmbiguousUser(t *testing.T) {
+	dir := newDirectory()
+	dir.AddUser("uid=alice2,ou=people,dc=example,dc=org", "alice-secret", map[string][]string{
+		"mail": {"alice@example.org"},
+	})
+
+	_, err := dir.Client().Authenticate("alice@example.org", "alice-secret")
+	if err == nil || errors.Is(err, directory.ErrUserNotFound) || errors.Is(err, directory.ErrInvalidCredentials) {
+		t.Errorf("error = %v, want a lookup error", err)
+	}
+}
+
+func TestAuthenticateEscapesFilter(t *testing.T) {
+	dir := newDirectory()
+
+	_, err := dir.Client().Authenticate("*)(mail=alice@example.org", "alice-secret")
+	if !errors.Is(err, directory.ErrUserNotFound) {
+		t.Fatalf("error = %v, want ErrUserNotFound", err)
+	}
+
+	filters := dir.Filters()
+	want := `(&(objectClass=person)(mail=\2a\29\28mail=alice@example.org))`
+	if len(filters) != 1 || filters[0] != want {
+		t.Errorf("filters = %v, want [%s]", filters, want)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("LDAP_URL", "")
+	if _, ok, err := directory.ConfigFromEnv(); ok || err != nil {
+		t.Errorf("without LDAP_URL ok, err = %v, %v, want disabled", ok, err)
+	}
+
+	t.Setenv("LDAP_URL", "ldaps://directory.test:636")
+	t.Setenv("LDAP_BASE_DN", "")
+	if _, _, err := directory.ConfigFromEnv(); err == nil {
+		t.Errorf("missing LDAP_BASE_DN was accepted")
+	}
+
+	t.Setenv("LDAP_BASE_DN", "dc=example,dc=org")
+	cfg, ok, err := directory.ConfigFromEnv()
+	if err != nil || !ok {
+		t.Fatalf("ConfigFromEnv() ok, err = %v, %v", ok, err)
+	}
+	if cfg.TLSConfig == nil || cfg.TLSConfig.ServerName != "directory.test" {
+		t.Errorf("TLS server name is not taken from LDAP_URL")
+	}
+}
@@ -0,0 +1,169 @@
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"gin/user-management-api/internal/utils"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+type Hasher interface {
+	Hash(password string) (string, error)
+	Verify(hash, password string) (bool, error)
+	// NeedsRehash reports hashes made by another algorithm or with other cost parameters
+	NeedsRehash(hash string) bool
+}
+
+type Argon2idParams struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultParams follows the OWASP recommendation for argon2id
+var DefaultParams = Argon2idParams{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+var Default Hasher = NewArgon2idHasher(DefaultParams)
+
+// ParamsFromEnv reads ARGON2_MEMORY_KB, ARGON2_ITERATIONS and ARGON2_PARALLELISM, changing them rehashes passwords on the next login
+func ParamsFromEnv() (Argon2idParams, error) {
+	params := DefaultParams
+	memory := utils.GetIntEnv("ARGON2_MEMORY_KB", int(DefaultParams.Memory))
+	iterations := utils.GetIntEnv("ARGON2_ITERATIONS", int(DefaultParams.Iterations))
+	parallelism := utils.GetIntEnv("ARGON2_PARALLELISM", int(DefaultParams.Parallelism))
+
+	if memory < 8*parallelism || iterations < 1 || parallelism < 1 || parallelism > 255 {
+		return Argon2idParams{}, fmt.Errorf("invalid argon2id parameters m=%d,t=%d,p=%d", memory, iterations, parallelism)
+	}
+
+	params.Memory = uint32(memory)
+	params.Iterations = uint32(iterations)
+	params.Parallelism = uint8(parallelism)
+	return params, nil
+}
+
+type argon2idHasher struct {
+	params Argon2idParams
+}
+
+func NewArgon2idHasher(params Argon2idParams) Hasher {
+	return &argon2idHasher{params: params}
+}
+
+func Hash(password string) (string, error) {
+	return Default.Hash(password)
+}
+
+func Verify(hash, password string) (bool, error) {
+	return Default.Verify(hash, password)
+}
+
+func NeedsRehash(hash string) bool {
+	return Default.NeedsRehash(hash)
+}
+
+// Hash returns a PHC string: $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.params.Memory,
+		h.params.Iterations,
+		h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify also accepts bcrypt hashes created before the switch to argon2id
+func (h *argon2idHasher) Verify(hash, password string) (bool, error) {
+	if isBcrypt(hash) {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return false, err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+func (h *argon2idHasher) NeedsRehash(hash string) bool {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+
+	return params.Memory != h.params.Memory ||
+		params.Iterations != h.params.Iterations ||
+		params.Parallelism != h.params.Parallelism ||
+		uint32(len(salt)) != h.params.SaltLength ||
+		uint32(len(key)) != h.params.KeyLength
+}
+
+func isBcrypt(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func decodeArgon2id(hash string) (Argon2idParams, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Argon2idParams{}, nil, nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2idParams{}, nil, nil, ErrUnknownHashFormat
+	}
+
+	var params Argon2idParams
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Argon2idParams{}, nil, nil, ErrUnknownHashFormat
+	}
+	// argon2.IDKey panics on zero time or threads
+	if params.Iterations < 1 || params.Parallelism < 1 {
+		return Argon2idParams{}, nil, nil, ErrUnknownHashFormat
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2idParams{}, nil, nil, ErrUnknownHashFormat
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Argon2idParams{}, nil, nil, ErrUnknownHashFormat
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
@@ -0,0 +1,149 @@
+package hasher_test
+
+import (
+	"errors"
+	"gin/user-management-api/pkg/hasher"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testParams keeps the tests fast, the format is the same as with DefaultParams
+var testParams = hasher.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestArgon2idRoundTrip(t *testing.T) {
+	h := hasher.NewArgon2idHasher(testParams)
+
+	hash, err := h.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("Hash() = %q", hash)
+	}
+
+	if ok, err := h.Verify(hash, "correct horse"); err != nil || !ok {
+		t.Errorf("Verify(right password) = %v, %v", ok, err)
+	}
+	if ok, err := h.Verify(hash, "wrong horse"); err != nil || ok {
+		t.Errorf("Verify(wrong password) = %v, %v", ok, err)
+	}
+	if h.NeedsRehash(hash) {
+		t.Errorf("fresh hash needs a rehash")
+	}
+
+	// Same password, new salt
+	if again, _ := h.Hash("correct horse"); again == hash {
+		t.Errorf("two hashes of the same password are equal")
+	}
+}
+
+func TestBcryptHashes(t *testing.T) {
+	h := hasher.NewArgon2idHasher(testParams)
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := h.Verify(string(legacy), "correct horse"); err != nil || !ok {
+		t.Errorf("Verify(bcrypt) = %v, %v", ok, err)
+	}
+	if ok, err := h.Verify(string(legacy), "wrong horse"); err != nil || ok {
+		t.Errorf("Verify(bcrypt, wrong password) = %v, %v", ok, err)
+	}
+	if !h.NeedsRehash(string(legacy)) {
+		t.Errorf("bcrypt hash does not need a rehash")
+	}
+}
+
+func TestNeedsRehashOnParameterChange(t *testing.T) {
+	hash, err := hasher.NewArgon2idHasher(testParams).Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := map[string]func(*hasher.Argon2idParams){
+		"memory":      func(p *hasher.Argon2idParams) { p.Memory = 128 },
+		"iterations":  func(p *hasher.Argon2idParams) { p.Iterations = 2 },
+		"parallelism": func(p *hasher.Argon2idParams) { p.Parallelism = 2 },
+		"salt length": func(p *hasher.Argon2idParams) { p.SaltLength = 32 },
+		"key length":  func(p *hasher.Argon2idParams) { p.KeyLength = 64 },
+	}
+	for name, change := range changes {
+		t.Run(name, func(t *testing.T) {
+			params := testParams
+			change(&params)
+			h := hasher.NewArgon2idHasher(params)
+
+			if !h.NeedsRehash(hash) {
+				t.Errorf("NeedsRehash() = false")
+			}
+			// The old hash keeps working until the rehash on login
+			if ok, err := h.Verify(hash, "correct horse"); err != nil || !ok {
+				t.Errorf("Verify() = %v, %v", ok, err)
+			}
+		})
+	}
+}
+
+func TestMalformedHashes(t *testing.T) {
+	h := hasher.NewArgon2idHasher(testParams)
+
+	cases := []string{
+		"",
+		"plaintext",
+		"$argon2i$v=19$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+		"$argon2id$v=19$m=64$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=0,p=0$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$not*base64$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA",
+	}
+	for _, hash := range cases {
+		t.Run(hash, func(t *testing.T) {
+			if ok, err := h.Verify(hash, "password"); ok || !errors.Is(err, hasher.ErrUnknownHashFormat) {
+				t.Errorf("Verify() = %v, %v, want ErrUnknownHashFormat", ok, err)
+			}
+			if !h.NeedsRehash(hash) {
+				t.Errorf("NeedsRehash() = false")
+			}
+		})
+	}
+}
+
+func TestParamsFromEnv(t *testing.T) {
+	cases := []struct {
+		name        string
+		memory      string
+		iterations  string
+		parallelism string
+		want        hasher.Argon2idParams
+		wantErr     bool
+	}{
+		{"defaults", "", "", "", hasher.DefaultParams, false},
+		{"custom", "32768", "4", "4", hasher.Argon2idParams{Memory: 32768, Iterations: 4, Parallelism: 4, SaltLength: 16, KeyLength: 32}, false},
+		{"smallest memory for parallelism", "32", "1", "4", hasher.Argon2idParams{Memory: 32, Iterations: 1, Parallelism: 4, SaltLength: 16, KeyLength: 32}, false},
+		{"memory below 8 per lane", "31", "1", "4", hasher.Argon2idParams{}, true},
+		{"zero iterations", "", "0", "", hasher.Argon2idParams{}, true},
+		{"zero parallelism", "", "", "0", hasher.Argon2idParams{}, true},
+		{"parallelism above 255", "1000000", "", "256", hasher.Argon2idParams{}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("ARGON2_MEMORY_KB", tc.memory)
+			t.Setenv("ARGON2_ITERATIONS", tc.iterations)
+			t.Setenv("ARGON2_PARALLELISM", tc.parallelism)
+
+			params, err := hasher.ParamsFromEnv()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParamsFromEnv() error = %v, want error %v", err, tc.wantErr)
+			}
+			if params != tc.want {
+				t.Errorf("ParamsFromEnv() = %+v, want %+v", params, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package signing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// Signer adds the signature headers to outgoing requests
+type Signer struct {
+	KeyID  string
+	Secret []byte
+	// Now can be replaced to control the timestamp, it defaults to time.Now
+	Now func() time.Time
+}
+
+func NewSigner(keyID string, secret []byte) *Signer {
+	return &Signer{KeyID: keyID, Secret: secret}
+}
+
+func (s *Signer) Sign(req *http.Request) error {
+	// Outgoing bodies are built by the caller, there is nothing to protect against
+	body, err := ReadBody(req, 0)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+
+	sig := Signature{
+		KeyID:     s.KeyID,
+		Timestamp: now().Unix(),
+		Nonce:     hex.EncodeToString(nonce),
+	}
+	sig.Value = Compute(s.Secret, StringToSign(sig.KeyID, sig.Timestamp, sig.Nonce, req.Method, req.URL.EscapedPath(), req.URL.RawQuery, HashBody(body)))
+	sig.SetHeaders(req.Header)
+	return nil
+}
+
+// Transport signs every request before passing it to Base
+type Transport struct {
+	Signer *Signer
+	Base   http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = body
+	}
+
+	if err := t.Signer.Sign(req); err != nil {
+		return nil, err
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// NewClient returns an http.Client whose requests are signed with the given key
+func NewClient(keyID string, secret []byte) *http.Client {
+	return &http.Client{
+		Transport: &Transport{Signer: NewSigner(keyID, secret)},
+	}
+}
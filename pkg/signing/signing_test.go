@@ -0,0 +1,150 @@
+package signing_test
+
+import (
+	"bytes"
+	"errors"
+	"gin/user-management-api/pkg/signing"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+func signedRequest(t *testing.T, method, target, body string) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if err := signing.NewSigner("billing", secret).Sign(req); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	return req
+}
+
+func verify(t *testing.T, req *http.Request) error {
+	t.Helper()
+
+	sig, err := signing.FromHeaders(req.Header)
+	if err != nil {
+		t.Fatalf("FromHeaders() error = %v", err)
+	}
+	body, err := signing.ReadBody(req, 0)
+	if err != nil {
+		t.Fatalf("ReadBody() error = %v", err)
+	}
+	return sig.Verify(secret, req.Method, req.URL.EscapedPath(), req.URL.RawQuery, body)
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	if a, b := signing.CanonicalQuery("b=2&a=1&a=0"), signing.CanonicalQuery("a=1&a=0&b=2"); a != b {
+		t.Errorf("parameter order changes the canonical query: %q != %q", a, b)
+	}
+	if got := signing.CanonicalQuery("q=a+b&x=%2F"); got != "q=a+b&x=%2F" {
+		t.Errorf("CanonicalQuery() = %q", got)
+	}
+}
+
+func TestStringToSign(t *testing.T) {
+	got := signing.StringToSign("billing", 1700000000, "n1", "post", "/api/v1/users", "b=2&a=1", "hash")
+	want := "billing\n1700000000\nn1\nPOST\n/api/v1/users\na=1&b=2\nhash"
+	if got != want {
+		t.Errorf("StringToSign() = %q, want %q", got, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	req := signedRequest(t, http.MethodPost, "/api/v1/users?b=2&a=1", `{"name":"x"}`)
+	if err := verify(t, req); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	// Reordering the query keeps the signature valid
+	reordered := req.Clone(req.Context())
+	reordered.URL.RawQuery = "a=1&b=2"
+	reordered.Body = io.NopCloser(strings.NewReader(`{"name":"x"}`))
+	if err := verify(t, reordered); err != nil {
+		t.Errorf("reordered query error = %v", err)
+	}
+
+	tampered := []struct {
+		name   string
+		modify func(*http.Request)
+	}{
+		{"body", func(r *http.Request) { r.Body = io.NopCloser(strings.NewReader(`{"name":"y"}`)) }},
+		{"query", func(r *http.Request) { r.URL.RawQuery = "a=1&b=3" }},
+		{"path", func(r *http.Request) { r.URL.Path = "/api/v1/roles" }},
+		{"method", func(r *http.Request) { r.Method = http.MethodPut }},
+		{"nonce", func(r *http.Request) { r.Header.Set(signing.HeaderNonce, "other") }},
+	}
+	for _, tc := range tampered {
+		t.Run(tc.name, func(t *testing.T) {
+			clone := req.Clone(req.Context())
+			clone.Body = io.NopCloser(strings.NewReader(`{"name":"x"}`))
+			tc.modify(clone)
+			if err := verify(t, clone); !errors.Is(err, signing.ErrInvalidSignature) {
+				t.Errorf("error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestCheckTimestamp(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	maxSkew := 5 * time.Minute
+
+	cases := []struct {
+		name      string
+		timestamp time.Time
+		wantErr   bool
+	}{
+		{"now", now, false},
+		{"past within skew", now.Add(-maxSkew), false},
+		{"future within skew", now.Add(maxSkew), false},
+		{"too old", now.Add(-maxSkew - time.Second), true},
+		{"too far ahead", now.Add(maxSkew + time.Second), true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := signing.Signature{Timestamp: tc.timestamp.Unix()}.CheckTimestamp(now, maxSkew)
+			if tc.wantErr != errors.Is(err, signing.ErrInvalidTimestamp) {
+				t.Errorf("error = %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("12345"))
+	body, err := signing.ReadBody(req, 5)
+	if err != nil || string(body) != "12345" {
+		t.Fatalf("ReadBody() = %q, %v", body, err)
+	}
+
+	// The handler still gets the whole body
+	rest, _ := io.ReadAll(req.Body)
+	if !bytes.Equal(rest, body) {
+		t.Errorf("body after ReadBody = %q", rest)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("123456"))
+	if _, err := signing.ReadBody(req, 5); !errors.Is(err, signing.ErrBodyTooLarge) {
+		t.Errorf("error = %v, want ErrBodyTooLarge", err)
+	}
+}
+
+func TestParseSecrets(t *testing.T) {
+	secrets, err := signing.ParseSecrets(" billing:" + string(secret) + ", ")
+	if err != nil || string(secrets["billing"]) != string(secret) {
+		t.Fatalf("ParseSecrets() = %v, %v", secrets, err)
+	}
+
+	if _, err := signing.ParseSecrets("billing:short"); err == nil {
+		t.Errorf("short secret was accepted")
+	}
+	if _, err := signing.ParseSecrets("billing"); err == nil {
+		t.Errorf("entry without a secret was accepted")
+	}
+}